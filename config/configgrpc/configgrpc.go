@@ -126,7 +126,7 @@ type GRPCServerSettings = ServerConfig
 
 // ServerConfig defines common settings for a gRPC server configuration.
 type ServerConfig struct {
-	// Server net.Addr config. For transport only "tcp" and "unix" are valid options.
+	// Server net.Addr config. For transport only "tcp", "unix" and "vsock" are valid options.
 	NetAddr confignet.AddrConfig `mapstructure:",squash"`
 
 	// Configures the protocol to use TLS.
@@ -261,6 +261,17 @@ func (gcs *ClientConfig) toDialOptions(host component.Host, settings component.T
 		opts = append(opts, grpc.WithAuthority(gcs.Authority))
 	}
 
+	if strings.HasPrefix(gcs.Endpoint, confignet.VsockScheme) {
+		// gRPC doesn't resolve the vsock:// targets, they're passed as is to the dialer.
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			na := confignet.AddrConfig{Endpoint: addr}
+			return na.Dial(ctx)
+		}))
+		if gcs.Authority == "" {
+			opts = append(opts, grpc.WithAuthority("localhost"))
+		}
+	}
+
 	otelOpts := []otelgrpc.Option{
 		otelgrpc.WithTracerProvider(settings.TracerProvider),
 		otelgrpc.WithMeterProvider(settings.MeterProvider),
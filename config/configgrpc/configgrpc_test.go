@@ -669,7 +669,7 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 			Transport: "unix",
 		},
 	}
-	ln, err := gss.ToListenerContext(context.Background())
+	ln, err := gss.NetAddr.Listen(context.Background())
 	assert.NoError(t, err)
 	srv, err := gss.ToServer(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
 	assert.NoError(t, err)
@@ -697,6 +697,48 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 	srv.Stop()
 }
 
+func TestReceiveOnUnixDomainSocketEndpoint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	socketName := tempSocketName(t)
+	// The unix:// endpoints create a Unix domain socket regardless of the transport.
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint:   "unix://" + socketName,
+			Transport:  "tcp",
+			UnixSocket: confignet.UnixSocketConfig{Permissions: "0600"},
+		},
+	}
+	ln, err := gss.NetAddr.Listen(context.Background())
+	require.NoError(t, err)
+	info, err := os.Stat(socketName)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	srv, err := gss.ToServer(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	ptraceotlp.RegisterGRPCServer(srv, &grpcTraceServer{})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	gcs := &ClientConfig{
+		Endpoint: "unix://" + socketName,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	grpcClientConn, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, grpcClientConn.Close()) }()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+	_, err = ptraceotlp.NewGRPCClient(grpcClientConn).Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	assert.NoError(t, err)
+}
+
 func TestContextWithClient(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -1084,6 +1126,43 @@ func (gts *grpcTraceServer) Export(ctx context.Context, _ ptraceotlp.ExportReque
 	return ptraceotlp.NewExportResponse(), nil
 }
 
+func TestReceiveOnVsock(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("vsock is only supported on Linux")
+	}
+	// The loopback CID, VMADDR_CID_LOCAL.
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint: "vsock://1:0",
+		},
+	}
+	ln, err := gss.NetAddr.Listen(context.Background())
+	if err != nil {
+		t.Skipf("vsock loopback is not available: %v", err)
+	}
+	srv, err := gss.ToServer(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	ptraceotlp.RegisterGRPCServer(srv, &grpcTraceServer{})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	gcs := &ClientConfig{
+		Endpoint: "vsock://" + ln.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	grpcClientConn, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, grpcClientConn.Close()) }()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+	_, err = ptraceotlp.NewGRPCClient(grpcClientConn).Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	assert.NoError(t, err)
+}
+
 // tempSocketName provides a temporary Unix socket name for testing.
 func tempSocketName(t *testing.T) string {
 	tmpfile, err := os.CreateTemp("", "sock")
@@ -4,6 +4,7 @@
 package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/rs/cors"
@@ -21,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/config/internal"
@@ -36,6 +39,10 @@ type HTTPClientSettings = ClientConfig
 // ClientConfig defines settings for creating an HTTP client.
 type ClientConfig struct {
 	// The target URL to send data to (e.g.: http://some.url:9411/v1/traces).
+	// The "unix://" endpoints, e.g. unix:///var/run/otel.sock, send the data over the
+	// Unix domain socket at their path, the remainder of the request paths after the
+	// socket path is used as the HTTP path. The "vsock://" endpoints, e.g.
+	// vsock://2:4318/v1/traces, send the data over the vsock socket at their CID and port.
 	Endpoint string `mapstructure:"endpoint"`
 
 	// ProxyURL setting for the collector
@@ -182,6 +189,24 @@ func (hcs *ClientConfig) ToClient(host component.Host, settings component.Teleme
 
 	clientTransport := (http.RoundTripper)(transport)
 
+	if socketPath, ok := confignet.UnixSocketPath(hcs.Endpoint); ok {
+		dialer := &net.Dialer{}
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+		clientTransport = &unixSocketRoundTripper{
+			transport:  clientTransport,
+			socketPath: socketPath,
+		}
+	}
+	if strings.HasPrefix(hcs.Endpoint, confignet.VsockScheme) {
+		transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			na := confignet.AddrConfig{Transport: "vsock", Endpoint: addr}
+			return na.Dial(ctx)
+		}
+		clientTransport = &vsockRoundTripper{transport: clientTransport}
+	}
+
 	// The Auth RoundTripper should always be the innermost to ensure that
 	// request signing-based auth mechanisms operate after compression
 	// and header middleware modifies the request
@@ -263,13 +288,57 @@ func (interceptor *headerRoundTripper) RoundTrip(req *http.Request) (*http.Respo
 	return interceptor.transport.RoundTrip(req)
 }
 
+// unixSocketRoundTripper sends the requests to the "unix://" URLs over the Unix domain socket
+// dialed by the transport, as plain HTTP requests with the remainder of the URL path after the
+// socket path.
+type unixSocketRoundTripper struct {
+	transport  http.RoundTripper
+	socketPath string
+}
+
+func (rt *unixSocketRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "unix" {
+		return rt.transport.RoundTrip(req)
+	}
+	urlPath, ok := strings.CutPrefix(req.URL.Path, rt.socketPath)
+	if !ok {
+		return nil, fmt.Errorf("URL %q doesn't start with the unix socket path %q", req.URL, rt.socketPath)
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = "localhost"
+	req.URL.Path = urlPath
+	req.URL.RawPath = ""
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+	return rt.transport.RoundTrip(req)
+}
+
+// vsockRoundTripper sends the requests to the "vsock://" URLs over the vsock socket dialed by
+// the transport, as plain HTTP requests.
+type vsockRoundTripper struct {
+	transport http.RoundTripper
+}
+
+func (rt *vsockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "vsock" {
+		return rt.transport.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	return rt.transport.RoundTrip(req)
+}
+
 // HTTPServerSettings defines settings for creating an HTTP server.
 // Deprecated: [v0.94.0] Use ServerConfig instead
 type HTTPServerSettings = ServerConfig
 
 // ServerConfig defines settings for creating an HTTP server.
 type ServerConfig struct {
-	// Endpoint configures the listening address for the server.
+	// Endpoint configures the listening address for the server. It can also be a "unix://"
+	// endpoint, e.g. unix:///var/run/otel.sock, or a "vsock://" endpoint, e.g. vsock://4294967295:4318.
 	Endpoint string `mapstructure:"endpoint"`
 
 	// TLSSetting struct exposes TLS client configuration.
@@ -291,11 +360,20 @@ type ServerConfig struct {
 	// Additional headers attached to each HTTP response sent to the client.
 	// Header values are opaque since they may be sensitive.
 	ResponseHeaders map[string]configopaque.String `mapstructure:"response_headers"`
+
+	// UnixSocket configures the Unix domain socket created when the endpoint is a "unix://"
+	// endpoint, e.g. unix:///var/run/otel.sock.
+	UnixSocket confignet.UnixSocketConfig `mapstructure:"unix_socket"`
 }
 
 // ToListener creates a net.Listener.
 func (hss *ServerConfig) ToListener() (net.Listener, error) {
-	listener, err := net.Listen("tcp", hss.Endpoint)
+	na := confignet.AddrConfig{
+		Endpoint:   hss.Endpoint,
+		Transport:  "tcp",
+		UnixSocket: hss.UnixSocket,
+	}
+	listener, err := na.Listen(context.Background())
 	if err != nil {
 		return nil, err
 	}
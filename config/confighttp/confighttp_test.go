@@ -12,7 +12,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -1103,6 +1106,89 @@ func TestHttpClientHostHeader(t *testing.T) {
 	})
 }
 
+func TestHttpUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on Windows")
+	}
+	socketPath := filepath.Join(t.TempDir(), "otel.sock")
+	endpoint := "unix://" + socketPath
+
+	hss := &ServerConfig{
+		Endpoint:   endpoint,
+		UnixSocket: confignet.UnixSocketConfig{Permissions: "0600"},
+	}
+	ln, err := hss.ToListener()
+	require.NoError(t, err)
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	s, err := hss.ToServer(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, errWrite := fmt.Fprint(w, r.URL.Path)
+		assert.NoError(t, errWrite)
+	}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	hcs := &ClientConfig{Endpoint: endpoint}
+	client, err := hcs.ToClient(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	// The remainder of the URL path after the socket path is the HTTP path.
+	resp, err := client.Get(endpoint + "/v1/traces")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/v1/traces", string(body))
+
+	_, err = client.Get("unix:///other.sock/v1/traces")
+	assert.ErrorContains(t, err, "doesn't start with the unix socket path")
+}
+
+func TestHttpVsock(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("vsock is only supported on Linux")
+	}
+	// The loopback CID, VMADDR_CID_LOCAL.
+	hss := &ServerConfig{Endpoint: "vsock://1:0"}
+	ln, err := hss.ToListener()
+	if err != nil {
+		t.Skipf("vsock loopback is not available: %v", err)
+	}
+
+	s, err := hss.ToServer(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, errWrite := fmt.Fprint(w, r.URL.Path)
+		assert.NoError(t, errWrite)
+	}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	endpoint := "vsock://" + ln.Addr().String()
+	hcs := &ClientConfig{Endpoint: endpoint}
+	client, err := hcs.ToClient(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	resp, err := client.Get(endpoint + "/v1/traces")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/v1/traces", string(body))
+}
+
 func TestContextWithClient(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -6,6 +6,7 @@ package confignet // import "go.opentelemetry.io/collector/config/confignet"
 import (
 	"context"
 	"net"
+	"strings"
 	"time"
 )
 
@@ -30,23 +31,55 @@ type AddrConfig struct {
 	Endpoint string `mapstructure:"endpoint"`
 
 	// Transport to use. Known protocols are "tcp", "tcp4" (IPv4-only), "tcp6" (IPv6-only), "udp", "udp4" (IPv4-only),
-	// "udp6" (IPv6-only), "ip", "ip4" (IPv4-only), "ip6" (IPv6-only), "unix", "unixgram" and "unixpacket".
+	// "udp6" (IPv6-only), "ip", "ip4" (IPv4-only), "ip6" (IPv6-only), "unix", "unixgram", "unixpacket" and "vsock",
+	// whose address has the form "cid:port" (Linux-only).
+	// The endpoints "unix://" followed by a path and "vsock://" followed by an address use the "unix" and "vsock"
+	// transports regardless of this setting.
 	Transport string `mapstructure:"transport"`
 
 	// DialerConfig contains options for connecting to an address.
 	DialerConfig DialerConfig `mapstructure:"dialer"`
+
+	// UnixSocket contains options for the Unix domain sockets created by Listen.
+	UnixSocket UnixSocketConfig `mapstructure:"unix_socket"`
 }
 
 // Dial equivalent with net.Dialer's DialContext for this address.
+// The "unix://" and "vsock://" endpoints are dialed with the "unix" and "vsock" transports.
 func (na *AddrConfig) Dial(ctx context.Context) (net.Conn, error) {
 	d := net.Dialer{Timeout: na.DialerConfig.Timeout}
-	return d.DialContext(ctx, na.Transport, na.Endpoint)
+	transport, address := na.transportAddress()
+	if transport == "vsock" {
+		return dialVsock(ctx, d, address)
+	}
+	return d.DialContext(ctx, transport, address)
 }
 
 // Listen equivalent with net.ListenConfig's Listen for this address.
+// The "unix://" and "vsock://" endpoints are listened with the "unix" and "vsock" transports,
+// the Unix domain sockets are created as configured by UnixSocket.
 func (na *AddrConfig) Listen(ctx context.Context) (net.Listener, error) {
+	transport, address := na.transportAddress()
+	switch transport {
+	case "unix":
+		return na.UnixSocket.listen(ctx, address)
+	case "vsock":
+		return listenVsock(address)
+	}
 	lc := net.ListenConfig{}
-	return lc.Listen(ctx, na.Transport, na.Endpoint)
+	return lc.Listen(ctx, transport, address)
+}
+
+// transportAddress returns the transport and the address of the endpoint, the transport
+// of the "unix://" and "vsock://" endpoints being given by their scheme.
+func (na *AddrConfig) transportAddress() (string, string) {
+	if path, ok := strings.CutPrefix(na.Endpoint, UnixScheme); ok {
+		return "unix", path
+	}
+	if address, ok := strings.CutPrefix(na.Endpoint, VsockScheme); ok {
+		return "vsock", address
+	}
+	return na.Transport, na.Endpoint
 }
 
 // TCPAddr represents a TCP endpoint address.
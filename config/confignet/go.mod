@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/stretchr/testify v1.8.4
 	go.uber.org/goleak v1.3.0
+	golang.org/x/sys v0.16.0
 )
 
 require (
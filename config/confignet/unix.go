@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confignet // import "go.opentelemetry.io/collector/config/confignet"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// UnixScheme is the prefix of the endpoints of Unix domain sockets, followed by the path of the socket.
+const UnixScheme = "unix://"
+
+// UnixSocketPath returns the path of the socket of an endpoint starting with UnixScheme,
+// and false if the endpoint isn't a Unix domain socket.
+func UnixSocketPath(endpoint string) (string, bool) {
+	return strings.CutPrefix(endpoint, UnixScheme)
+}
+
+// UnixSocketConfig contains the options of the Unix domain sockets created by the servers.
+type UnixSocketConfig struct {
+	// Permissions of the socket file, as an octal string, e.g. "0660". If empty, the file
+	// is created with the default permissions of the process.
+	Permissions string `mapstructure:"permissions"`
+}
+
+// Validate checks the permissions are a valid octal file mode.
+func (uc *UnixSocketConfig) Validate() error {
+	_, err := uc.fileMode()
+	return err
+}
+
+func (uc *UnixSocketConfig) fileMode() (os.FileMode, error) {
+	if uc.Permissions == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(uc.Permissions, 8, 32)
+	if err != nil || mode&^uint64(os.ModePerm) != 0 {
+		return 0, fmt.Errorf("invalid unix socket permissions %q, must be an octal file mode, e.g. \"0660\"", uc.Permissions)
+	}
+	return os.FileMode(mode), nil
+}
+
+// listen creates a Unix domain socket listener at path, removing the socket file left by
+// a process that stopped without removing it. With the configured permissions, the socket
+// is created in a private directory and linked at path once its permissions are set, so it's
+// never accessible with other permissions. The file is removed when the listener is closed.
+func (uc *UnixSocketConfig) listen(ctx context.Context, path string) (net.Listener, error) {
+	mode, err := uc.fileMode()
+	if err != nil {
+		return nil, err
+	}
+	if err = removeStaleUnixSocket(ctx, path); err != nil {
+		return nil, err
+	}
+
+	lc := net.ListenConfig{}
+	if uc.Permissions == "" {
+		return lc.Listen(ctx, "unix", path)
+	}
+
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".sock-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the unix socket %q: %w", path, err)
+	}
+	defer os.RemoveAll(dir)
+	ln, err := lc.Listen(ctx, "unix", filepath.Join(dir, "sock"))
+	if err != nil {
+		return nil, err
+	}
+	ul := ln.(*net.UnixListener)
+	// The socket file is removed at path instead.
+	ul.SetUnlinkOnClose(false)
+	if err = os.Chmod(filepath.Join(dir, "sock"), mode); err == nil {
+		// Unlike a rename, the link fails if the path was created in the meantime.
+		err = os.Link(filepath.Join(dir, "sock"), path)
+	}
+	if err != nil {
+		_ = ul.Close()
+		return nil, fmt.Errorf("failed to create the unix socket %q: %w", path, err)
+	}
+	return &unixListener{UnixListener: ul, path: path}, nil
+}
+
+// removeStaleUnixSocket removes the socket file at path if no process accepts connections
+// on it anymore. The other files are kept, so listening on them fails.
+func removeStaleUnixSocket(ctx context.Context, path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode().Type() != os.ModeSocket {
+		return nil
+	}
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err == nil {
+		return conn.Close()
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return nil
+	}
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove the stale unix socket %q: %w", path, err)
+	}
+	return nil
+}
+
+// unixListener is a Unix domain socket listener whose socket file was linked at path.
+type unixListener struct {
+	*net.UnixListener
+	path      string
+	closeOnce sync.Once
+}
+
+func (l *unixListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: l.path, Net: "unix"}
+}
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	l.closeOnce.Do(func() {
+		if rmErr := os.Remove(l.path); rmErr != nil && !os.IsNotExist(rmErr) && err == nil {
+			err = rmErr
+		}
+	})
+	return err
+}
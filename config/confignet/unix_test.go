@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confignet
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSocketPath(t *testing.T) {
+	path, ok := UnixSocketPath("unix:///var/run/otel.sock")
+	assert.True(t, ok)
+	assert.Equal(t, "/var/run/otel.sock", path)
+
+	_, ok = UnixSocketPath("localhost:4317")
+	assert.False(t, ok)
+}
+
+func TestUnixSocketConfigValidate(t *testing.T) {
+	assert.NoError(t, (&UnixSocketConfig{}).Validate())
+	assert.NoError(t, (&UnixSocketConfig{Permissions: "0660"}).Validate())
+	assert.Error(t, (&UnixSocketConfig{Permissions: "0960"}).Validate())
+	assert.Error(t, (&UnixSocketConfig{Permissions: "10777"}).Validate())
+}
+
+func TestAddrConfigListenUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on Windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "otel.sock")
+	// The unix:// endpoints create a Unix domain socket regardless of the transport.
+	nas := &AddrConfig{
+		Endpoint:   UnixScheme + path,
+		Transport:  "tcp",
+		UnixSocket: UnixSocketConfig{Permissions: "0600"},
+	}
+	ln, err := nas.Listen(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, path, ln.Addr().String())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSocket|0600, info.Mode()&(os.ModeType|os.ModePerm))
+	// The private directory where the socket was created is removed.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn, errAccept := ln.Accept()
+		if assert.NoError(t, errAccept) {
+			assert.NoError(t, conn.Close())
+		}
+	}()
+	nac := &AddrConfig{Endpoint: UnixScheme + path}
+	conn, err := nac.Dial(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, conn.Close())
+	<-done
+
+	// The socket file is removed with the listener.
+	require.NoError(t, ln.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAddrConfigListenUnixSocketInUse(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	path := filepath.Join(t.TempDir(), "otel.sock")
+	nas := &AddrConfig{Endpoint: path, Transport: "unix"}
+	ln, err := nas.Listen(context.Background())
+	require.NoError(t, err)
+
+	// The socket of a running listener is kept.
+	_, err = nas.Listen(context.Background())
+	assert.Error(t, err)
+	nas.UnixSocket.Permissions = "0600"
+	_, err = nas.Listen(context.Background())
+	assert.Error(t, err)
+	require.NoError(t, ln.Close())
+
+	// The other files are kept too.
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+	_, err = nas.Listen(context.Background())
+	assert.Error(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+func TestAddrConfigListenUnixSocketStale(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	for _, permissions := range []string{"", "0660"} {
+		path := filepath.Join(t.TempDir(), "otel.sock")
+		// The socket file is left behind, like after a crash.
+		ln, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		ln.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.NoError(t, ln.Close())
+		_, err = os.Stat(path)
+		require.NoError(t, err)
+
+		nas := &AddrConfig{Endpoint: path, Transport: "unix", UnixSocket: UnixSocketConfig{Permissions: permissions}}
+		ln, err = nas.Listen(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, ln.Close())
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confignet // import "go.opentelemetry.io/collector/config/confignet"
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// VsockScheme is the prefix of the endpoints of vsock sockets, followed by their address
+// of the form "cid:port", e.g. vsock://2:4317. The context identifier (CID) can be omitted
+// to listen on any CID.
+const VsockScheme = "vsock://"
+
+// vsockCIDAny is the CID to listen on any CID, VMADDR_CID_ANY.
+const vsockCIDAny = math.MaxUint32
+
+// VsockAddr is the address of a vsock socket.
+type VsockAddr struct {
+	// CID is the context identifier of the virtual machine or of the host.
+	CID uint32
+	// Port is the port number.
+	Port uint32
+}
+
+// Network returns the address's network name, "vsock".
+func (a *VsockAddr) Network() string {
+	return "vsock"
+}
+
+func (a *VsockAddr) String() string {
+	return fmt.Sprintf("%d:%d", a.CID, a.Port)
+}
+
+// parseVsockAddr parses a vsock address of the form "cid:port", the CID being vsockCIDAny if omitted.
+func parseVsockAddr(address string) (*VsockAddr, error) {
+	cid, port, ok := strings.Cut(address, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid vsock address %q, must be of the form \"cid:port\"", address)
+	}
+	addr := &VsockAddr{CID: vsockCIDAny}
+	if cid != "" {
+		parsed, err := strconv.ParseUint(cid, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vsock address %q, invalid CID: %w", address, err)
+		}
+		addr.CID = uint32(parsed)
+	}
+	parsed, err := strconv.ParseUint(port, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vsock address %q, invalid port: %w", address, err)
+	}
+	addr.Port = uint32(parsed)
+	return addr, nil
+}
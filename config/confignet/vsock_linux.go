@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package confignet // import "go.opentelemetry.io/collector/config/confignet"
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// listenVsock listens on the vsock address. The socket is non-blocking and handled by
+// the runtime network poller through an os.File, since the net package doesn't support vsock.
+func listenVsock(address string) (net.Listener, error) {
+	addr, err := parseVsockAddr(address)
+	if err != nil {
+		return nil, err
+	}
+	f, err := vsockSocket()
+	if err != nil {
+		return nil, opError("listen", addr, err)
+	}
+	rc, err := f.SyscallConn()
+	if err == nil {
+		err = controlErr(rc, func(fd int) error {
+			if bindErr := unix.Bind(fd, &unix.SockaddrVM{CID: addr.CID, Port: addr.Port}); bindErr != nil {
+				return os.NewSyscallError("bind", bindErr)
+			}
+			if listenErr := unix.Listen(fd, unix.SOMAXCONN); listenErr != nil {
+				return os.NewSyscallError("listen", listenErr)
+			}
+			// The port is chosen by the kernel if it's zero.
+			local, addrErr := vsockLocalAddr(fd)
+			if addrErr == nil {
+				addr = local
+			}
+			return addrErr
+		})
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, opError("listen", addr, err)
+	}
+	return &vsockListener{file: f, rc: rc, addr: addr}, nil
+}
+
+// dialVsock connects to the vsock address, the dialer's timeout and ctx bound the connection.
+func dialVsock(ctx context.Context, d net.Dialer, address string) (net.Conn, error) {
+	addr, err := parseVsockAddr(address)
+	if err != nil {
+		return nil, err
+	}
+	if d.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+	f, err := vsockSocket()
+	if err != nil {
+		return nil, opError("dial", addr, err)
+	}
+	conn, err := connectVsock(ctx, f, addr)
+	if err != nil {
+		_ = f.Close()
+		return nil, opError("dial", addr, err)
+	}
+	return conn, nil
+}
+
+func connectVsock(ctx context.Context, f *os.File, addr *VsockAddr) (net.Conn, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	err = controlErr(rc, func(fd int) error {
+		return unix.Connect(fd, &unix.SockaddrVM{CID: addr.CID, Port: addr.Port})
+	})
+	if err != nil && !errors.Is(err, unix.EINPROGRESS) {
+		return nil, os.NewSyscallError("connect", err)
+	}
+	if err != nil {
+		// Wait for the connection to be established, the deadline interrupts the wait once ctx is done.
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = f.SetWriteDeadline(deadline)
+		}
+		stop := context.AfterFunc(ctx, func() {
+			_ = f.SetWriteDeadline(time.Unix(1, 0))
+		})
+		defer stop()
+		waited := false
+		// The result of the connection is kept apart from the error of the wait, which is returned by rc.Write.
+		var connectErr error
+		err = rc.Write(func(fd uintptr) bool {
+			if !waited {
+				waited = true
+				return false
+			}
+			soErr, sockoptErr := unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_ERROR)
+			switch {
+			case sockoptErr != nil:
+				connectErr = os.NewSyscallError("getsockopt", sockoptErr)
+			case soErr != 0:
+				connectErr = os.NewSyscallError("connect", syscall.Errno(soErr))
+			}
+			return true
+		})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if connectErr != nil {
+			return nil, connectErr
+		}
+		_ = f.SetWriteDeadline(time.Time{})
+	}
+
+	var local *VsockAddr
+	if err = controlErr(rc, func(fd int) error {
+		var addrErr error
+		local, addrErr = vsockLocalAddr(fd)
+		return addrErr
+	}); err != nil {
+		return nil, err
+	}
+	return &vsockConn{File: f, local: local, remote: addr}, nil
+}
+
+func vsockSocket() (*os.File, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	// The file of a non-blocking descriptor is handled by the runtime network poller.
+	return os.NewFile(uintptr(fd), "vsock"), nil
+}
+
+func vsockLocalAddr(fd int) (*VsockAddr, error) {
+	sa, err := unix.Getsockname(fd)
+	if err != nil {
+		return nil, os.NewSyscallError("getsockname", err)
+	}
+	return toVsockAddr(sa), nil
+}
+
+func toVsockAddr(sa unix.Sockaddr) *VsockAddr {
+	if vm, ok := sa.(*unix.SockaddrVM); ok {
+		return &VsockAddr{CID: vm.CID, Port: vm.Port}
+	}
+	return &VsockAddr{}
+}
+
+// controlErr calls fn with the descriptor of rc and returns the error of either.
+func controlErr(rc syscall.RawConn, fn func(fd int) error) error {
+	var fnErr error
+	if err := rc.Control(func(fd uintptr) {
+		fnErr = fn(int(fd))
+	}); err != nil {
+		return err
+	}
+	return fnErr
+}
+
+func opError(op string, addr *VsockAddr, err error) error {
+	var netAddr net.Addr
+	if addr != nil {
+		netAddr = addr
+	}
+	return &net.OpError{Op: op, Net: "vsock", Addr: netAddr, Err: err}
+}
+
+// vsockListener accepts the connections on a listening vsock socket.
+type vsockListener struct {
+	file *os.File
+	rc   syscall.RawConn
+	addr *VsockAddr
+}
+
+func (l *vsockListener) Accept() (net.Conn, error) {
+	var nfd int
+	var sa unix.Sockaddr
+	var acceptErr error
+	err := l.rc.Read(func(fd uintptr) bool {
+		nfd, sa, acceptErr = unix.Accept4(int(fd), unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC)
+		return !errors.Is(acceptErr, unix.EAGAIN)
+	})
+	if errors.Is(err, os.ErrClosed) {
+		err = net.ErrClosed
+	}
+	if err == nil && acceptErr != nil {
+		err = os.NewSyscallError("accept4", acceptErr)
+	}
+	if err != nil {
+		return nil, opError("accept", l.addr, err)
+	}
+	return &vsockConn{File: os.NewFile(uintptr(nfd), "vsock"), local: l.addr, remote: toVsockAddr(sa)}, nil
+}
+
+func (l *vsockListener) Close() error {
+	return l.file.Close()
+}
+
+func (l *vsockListener) Addr() net.Addr {
+	return l.addr
+}
+
+// vsockConn is a connected vsock socket, its reads, writes and deadlines are the ones of its file.
+type vsockConn struct {
+	*os.File
+	local  *VsockAddr
+	remote *VsockAddr
+}
+
+func (c *vsockConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *vsockConn) RemoteAddr() net.Addr {
+	return c.remote
+}
@@ -0,0 +1,22 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package confignet // import "go.opentelemetry.io/collector/config/confignet"
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+var errVsockNotSupported = errors.New("vsock is only supported on Linux")
+
+func listenVsock(string) (net.Listener, error) {
+	return nil, errVsockNotSupported
+}
+
+func dialVsock(context.Context, net.Dialer, string) (net.Conn, error) {
+	return nil, errVsockNotSupported
+}
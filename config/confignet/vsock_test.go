@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confignet
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVsockAddr(t *testing.T) {
+	addr, err := parseVsockAddr("3:4317")
+	require.NoError(t, err)
+	assert.Equal(t, &VsockAddr{CID: 3, Port: 4317}, addr)
+	assert.Equal(t, "vsock", addr.Network())
+	assert.Equal(t, "3:4317", addr.String())
+
+	addr, err = parseVsockAddr(":4317")
+	require.NoError(t, err)
+	assert.Equal(t, &VsockAddr{CID: vsockCIDAny, Port: 4317}, addr)
+
+	for _, invalid := range []string{"4317", "x:4317", "3:x", "3:4294967296"} {
+		_, err = parseVsockAddr(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAddrConfigVsock(t *testing.T) {
+	// The loopback CID, VMADDR_CID_LOCAL.
+	nas := &AddrConfig{Endpoint: VsockScheme + "1:0"}
+	ln, err := nas.Listen(context.Background())
+	if runtime.GOOS != "linux" {
+		assert.Error(t, err)
+		return
+	}
+	if err != nil {
+		t.Skipf("vsock loopback is not available: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn, errAccept := ln.Accept()
+		if !assert.NoError(t, errAccept) {
+			return
+		}
+		buf := make([]byte, 4)
+		_, errRead := conn.Read(buf)
+		assert.NoError(t, errRead)
+		assert.Equal(t, "ping", string(buf))
+		assert.NoError(t, conn.Close())
+	}()
+
+	nac := &AddrConfig{Endpoint: ln.Addr().String(), Transport: "vsock"}
+	conn, err := nac.Dial(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ln.Addr(), conn.RemoteAddr())
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	<-done
+	assert.NoError(t, conn.Close())
+
+	require.NoError(t, ln.Close())
+	_, err = ln.Accept()
+	assert.Error(t, err)
+}
+
+func TestAddrConfigVsockDialRefused(t *testing.T) {
+	// No listener is expected on the port, the dial must fail rather than return an unconnected socket.
+	nac := &AddrConfig{Endpoint: VsockScheme + "1:39999", DialerConfig: DialerConfig{Timeout: 10 * time.Second}}
+	conn, err := nac.Dial(context.Background())
+	require.Error(t, err)
+	assert.Nil(t, conn)
+}
@@ -7,10 +7,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // schemePattern defines the regexp pattern for scheme names.
@@ -18,6 +21,15 @@ import (
 // combination of letters, digits, plus ("+"), period ("."), or hyphen ("-").
 const schemePattern = `[A-Za-z][A-Za-z0-9+.-]+`
 
+const (
+	// defaultSeparator separates the URI from its default value, e.g. `${env:PORT:-4317}`,
+	// used when the value referenced by the URI is missing or empty.
+	defaultSeparator = ":-"
+	// requiredSeparator separates the URI from the error message, e.g. `${env:TOKEN:?the token is required}`,
+	// returned when the value referenced by the URI is missing or empty.
+	requiredSeparator = ":?"
+)
+
 var (
 	// Need to match new line as well in the OpaqueValue, so setting the "s" flag. See https://pkg.go.dev/regexp/syntax.
 	uriRegexp = regexp.MustCompile(`(?s:^(?P<Scheme>` + schemePattern + `):(?P<OpaqueValue>.*)$)`)
@@ -96,6 +108,77 @@ func findURI(input string) string {
 	return input[openIndex : closeIndex+1]
 }
 
+// findDefaultingURI returns the outermost URI of input having uri in its default value, or uri if
+// it isn't in a default value.
+func findDefaultingURI(input string, uri string) string {
+	start := strings.Index(input, uri)
+	end := start + len(uri)
+	for {
+		openIndex := enclosingOpenIndex(input, start)
+		if openIndex < 0 || !hasSeparator(input[openIndex+2:start]) {
+			return input[start:end]
+		}
+		closeIndex := matchingCloseIndex(input, openIndex)
+		if closeIndex < 0 {
+			return input[start:end]
+		}
+		start, end = openIndex, closeIndex+1
+	}
+}
+
+// enclosingOpenIndex returns the index of the "${" enclosing the URI starting at index, or -1 if there is none.
+func enclosingOpenIndex(input string, index int) int {
+	depth := 0
+	for i := index - 1; i >= 0; i-- {
+		switch {
+		case input[i] == '}':
+			depth++
+		case strings.HasPrefix(input[i:], "${"):
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// matchingCloseIndex returns the index of the "}" closing the "${" at openIndex, or -1 if there is none.
+func matchingCloseIndex(input string, openIndex int) int {
+	depth := 0
+	for i := openIndex + 2; i < len(input); i++ {
+		switch {
+		case strings.HasPrefix(input[i:], "${"):
+			depth++
+			i++
+		case input[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// hasSeparator returns whether the beginning of a URI, up to a nested URI, has a default or required separator
+// outside of the other nested URIs.
+func hasSeparator(input string) bool {
+	depth := 0
+	for i := 0; i < len(input); i++ {
+		switch {
+		case strings.HasPrefix(input[i:], "${"):
+			depth++
+			i++
+		case input[i] == '}':
+			depth--
+		case depth == 0 && (strings.HasPrefix(input[i:], defaultSeparator) || strings.HasPrefix(input[i:], requiredSeparator)):
+			return true
+		}
+	}
+	return false
+}
+
 // findAndExpandURI attempts to find and expand the first occurrence of an expandable URI in input. If an expandable URI is found it
 // returns the input with the URI expanded, true and nil. Otherwise, it returns the unchanged input, false and the expanding error.
 func (mr *Resolver) findAndExpandURI(ctx context.Context, input string) (any, bool, error) {
@@ -104,6 +187,8 @@ func (mr *Resolver) findAndExpandURI(ctx context.Context, input string) (any, bo
 		// No URI found, return.
 		return input, false, nil
 	}
+	// The URIs in default values are only expanded if the default value is used.
+	uri = findDefaultingURI(input, uri)
 	if uri == input {
 		// If the value is a single URI, then the return value can be anything.
 		// This is the case `foo: ${file:some_extra_config.yml}`.
@@ -139,7 +224,8 @@ func toString(strURI string, input any) (string, error) {
 }
 
 func (mr *Resolver) expandURI(ctx context.Context, uri string) (any, bool, error) {
-	lURI, err := newLocation(uri[2 : len(uri)-1])
+	value, separator, fallback := splitFallback(uri[2 : len(uri)-1])
+	lURI, err := newLocation(value)
 	if err != nil {
 		return nil, false, err
 	}
@@ -148,11 +234,63 @@ func (mr *Resolver) expandURI(ctx context.Context, uri string) (any, bool, error
 	}
 	ret, err := mr.retrieveValue(ctx, lURI)
 	if err != nil {
+		// The providers report the missing values with errors wrapping fs.ErrNotExist, e.g. for a missing file.
+		if separator != "" && errors.Is(err, fs.ErrNotExist) {
+			return mr.expandFallback(ctx, lURI, separator, fallback)
+		}
 		return nil, false, err
 	}
 	mr.closers = append(mr.closers, ret.Close)
 	val, err := ret.AsRaw()
-	return val, true, err
+	if err != nil {
+		return nil, false, err
+	}
+	if separator != "" && (val == nil || val == "") {
+		return mr.expandFallback(ctx, lURI, separator, fallback)
+	}
+	return val, true, nil
+}
+
+// splitFallback splits the URI from its default value or required error message, and returns the separator
+// between them, or an empty separator if the URI has neither.
+func splitFallback(uri string) (string, string, string) {
+	index := -1
+	for _, separator := range []string{defaultSeparator, requiredSeparator} {
+		if i := strings.Index(uri, separator); i >= 0 && (index < 0 || i < index) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return uri, "", ""
+	}
+	return uri[:index], uri[index : index+2], uri[index+2:]
+}
+
+// expandFallback returns the default value of a URI whose value is missing or empty, or an error if the value is required.
+func (mr *Resolver) expandFallback(ctx context.Context, lURI location, separator string, fallback string) (any, bool, error) {
+	if separator == requiredSeparator {
+		if fallback == "" {
+			return nil, false, fmt.Errorf("the uri %q references a missing or empty required value", lURI.asString())
+		}
+		return nil, false, fmt.Errorf("the uri %q references a missing or empty required value: %s", lURI.asString(), fallback)
+	}
+	if fallback == "" {
+		// yaml.Unmarshal returns nil for an empty document, which can't be embedded in a string.
+		return "", true, nil
+	}
+	if !strings.Contains(fallback, "${") {
+		// Parse the default value like the providers parse the values they retrieve, e.g. `${env:ENABLED:-true}` is a bool.
+		var val any
+		if err := yaml.Unmarshal([]byte(fallback), &val); err != nil {
+			return fallback, true, nil
+		}
+		return val, true, nil
+	}
+	val, err := mr.expandValueRecursively(ctx, fallback)
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
 }
 
 type location struct {
@@ -171,3 +309,24 @@ func newLocation(uri string) (location, error) {
 	}
 	return location{scheme: submatches[1], opaqueValue: submatches[2]}, nil
 }
+
+// containsReference returns whether the value, or any value in it, is a string referencing other values with "$".
+func containsReference(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, "$")
+	case []any:
+		for _, vint := range v {
+			if containsReference(vint) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, mv := range v {
+			if containsReference(mv) {
+				return true
+			}
+		}
+	}
+	return false
+}
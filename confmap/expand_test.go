@@ -6,6 +6,8 @@ package confmap // import "go.opentelemetry.io/collector/confmap"
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/fs"
 	"path/filepath"
 	"testing"
 
@@ -330,11 +332,184 @@ func newEnvProvider() Provider {
 			return NewRetrieved(float64(6.4))
 		case "env:BOOL":
 			return NewRetrieved(true)
+		case "env:UNSET":
+			return NewRetrieved(nil)
+		case "env:EMPTY":
+			return NewRetrieved("")
 		}
 		return nil, errors.New("impossible")
 	})
 }
 
+func newFallbackFileProvider() Provider {
+	return newFakeProvider("file", func(_ context.Context, uri string, _ WatcherFunc) (*Retrieved, error) {
+		switch uri {
+		case "file:config.yaml":
+			return NewRetrieved(map[string]any{"endpoint": "localhost:4317"})
+		case "file:missing.yaml":
+			return nil, fmt.Errorf("unable to read the file %v: %w", uri, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("unable to read the file %v: %w", uri, fs.ErrPermission)
+	})
+}
+
+func TestResolverExpandDefaultValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output any
+	}{
+		{
+			name:   "Unset",
+			input:  "${env:UNSET:-default}",
+			output: "default",
+		},
+		{
+			name:   "Empty",
+			input:  "${env:EMPTY:-default}",
+			output: "default",
+		},
+		{
+			name:   "NotUsed",
+			input:  "${env:HOST:-default}",
+			output: "localhost",
+		},
+		{
+			name:   "RequiredSet",
+			input:  "${env:HOST:?HOST is required}",
+			output: "localhost",
+		},
+		{
+			name:   "ParsedAsYAML",
+			input:  "${env:UNSET:-4317}",
+			output: 4317,
+		},
+		{
+			name:   "EmptyDefault",
+			input:  "${env:UNSET:-}",
+			output: "",
+		},
+		{
+			name:   "EmbeddedEmptyDefault",
+			input:  "x${env:UNSET:-}y",
+			output: "xy",
+		},
+		{
+			name:   "SeparatorInDefault",
+			input:  "${env:UNSET:-a:-b}",
+			output: "a:-b",
+		},
+		{
+			name:   "Embedded",
+			input:  "http://${env:UNSET:-localhost}:${env:PORT:-4317}",
+			output: "http://localhost:3044",
+		},
+		{
+			name:   "URIDefault",
+			input:  "${env:UNSET:-${env:HOST}}",
+			output: "localhost",
+		},
+		{
+			name:   "NestedDefaults",
+			input:  "${env:UNSET:-${env:EMPTY:-${env:HOST}}}",
+			output: "localhost",
+		},
+		{
+			name:   "EmbeddedInDefault",
+			input:  "${env:UNSET:-http://${env:HOST}:${env:PORT}}",
+			output: "http://localhost:3044",
+		},
+		{
+			name:   "EmbeddedWithNestedDefault",
+			input:  "http://${env:UNSET:-${env:EMPTY:-localhost}}:4317",
+			output: "http://localhost:4317",
+		},
+		{
+			name:   "MissingFile",
+			input:  "${file:missing.yaml:-${env:HOST}}",
+			output: "localhost",
+		},
+		{
+			name:   "ComplexDefault",
+			input:  "${file:missing.yaml:-${file:config.yaml}}",
+			output: map[string]any{"endpoint": "localhost:4317"},
+		},
+		{
+			name:   "UnusedDefaultNotExpanded",
+			input:  "${env:HOST:-${file:missing.yaml}}",
+			output: "localhost",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newFakeProvider("input", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+				return NewRetrieved(map[string]any{tt.name: tt.input})
+			})
+
+			resolver, err := NewResolver(ResolverSettings{URIs: []string{"input:"}, Providers: makeMapProvidersMap(provider, newEnvProvider(), newFallbackFileProvider()), Converters: nil})
+			require.NoError(t, err)
+
+			cfgMap, err := resolver.Resolve(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{tt.name: tt.output}, cfgMap.ToStringMap())
+		})
+	}
+}
+
+func TestResolverExpandDefaultValuesError(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "RequiredUnset",
+			input: "${env:UNSET:?HOST must be set}",
+			err:   `the uri "env:UNSET" references a missing or empty required value: HOST must be set`,
+		},
+		{
+			name:  "RequiredEmptyNoMessage",
+			input: "${env:EMPTY:?}",
+			err:   `the uri "env:EMPTY" references a missing or empty required value`,
+		},
+		{
+			name:  "RequiredMissingFile",
+			input: "${file:missing.yaml:?}",
+			err:   `the uri "file:missing.yaml" references a missing or empty required value`,
+		},
+		{
+			name:  "RequiredInDefault",
+			input: "${env:UNSET:-${env:EMPTY:?EMPTY must be set}}",
+			err:   `the uri "env:EMPTY" references a missing or empty required value: EMPTY must be set`,
+		},
+		{
+			name:  "OtherErrorsNotDefaulted",
+			input: "${file:unreadable.yaml:-default}",
+			err:   "unable to read the file file:unreadable.yaml: permission denied",
+		},
+		{
+			name:  "MissingFileInDefault",
+			input: "${env:UNSET:-${file:missing.yaml}}",
+			err:   "unable to read the file file:missing.yaml: file does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newFakeProvider("input", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+				return NewRetrieved(map[string]any{tt.name: tt.input})
+			})
+
+			resolver, err := NewResolver(ResolverSettings{URIs: []string{"input:"}, Providers: makeMapProvidersMap(provider, newEnvProvider(), newFallbackFileProvider()), Converters: nil})
+			require.NoError(t, err)
+
+			_, err = resolver.Resolve(context.Background())
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
 func TestResolverExpandReturnError(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -459,3 +634,47 @@ func TestResolverExpandStringValueInvalidReturnValue(t *testing.T) {
 	_, err = resolver.Resolve(context.Background())
 	assert.EqualError(t, err, `expanding ${test:PORT}, expected convertable to string value type, got ['ӛ']([]interface {})`)
 }
+
+type timeoutConverter struct{}
+
+func (timeoutConverter) Convert(_ context.Context, conf *Conf) error {
+	return conf.Merge(NewFromStringMap(map[string]any{"processors": map[string]any{"batch": map[string]any{"timeout": "1s"}}}))
+}
+
+func TestResolverExpandedKeys(t *testing.T) {
+	provider := newFakeProvider("input", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{
+			"exporters": map[string]any{
+				"otlp": map[string]any{
+					"endpoint": "${env:HOST}:4317",
+					"headers":  map[string]any{"authorization": "Bearer ${env:OS}"},
+				},
+				"debug": map[string]any{"verbosity": "basic"},
+			},
+			"receivers":  "${test:receivers}",
+			"processors": map[string]any{"batch": map[string]any{"timeout": "$TIMEOUT"}},
+			"extensions": []any{"${env:PR}", "pprof"},
+		})
+	})
+	testProvider := newFakeProvider("test", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{"otlp": map[string]any{"endpoint": "0.0.0.0:4317"}})
+	})
+	resolver, err := NewResolver(ResolverSettings{
+		URIs:       []string{"input:"},
+		Providers:  makeMapProvidersMap(provider, newEnvProvider(), testProvider),
+		Converters: []Converter{timeoutConverter{}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resolver.ExpandedKeys())
+
+	_, err = resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	// The values expanded by the converters are included, the values without references are not.
+	assert.Equal(t, []string{
+		"exporters::otlp::endpoint",
+		"exporters::otlp::headers::authorization",
+		"extensions",
+		"processors::batch::timeout",
+		"receivers",
+	}, resolver.ExpandedKeys())
+}
@@ -111,6 +111,10 @@ func (e *Encoder) encodeStruct(value reflect.Value) (any, error) {
 	result := make(map[string]any)
 	for i := 0; i < value.NumField(); i++ {
 		field := value.Field(i)
+		// The functions and channels, e.g. the callbacks set by the components, can't be encoded as values.
+		if field.Kind() == reflect.Func || field.Kind() == reflect.Chan {
+			continue
+		}
 		if field.CanInterface() {
 			info := getTagInfo(value.Type().Field(i))
 			if (info.omitEmpty && field.IsZero()) || info.name == optionSkip {
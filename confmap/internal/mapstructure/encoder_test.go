@@ -263,6 +263,24 @@ func TestEncodeStruct(t *testing.T) {
 	require.Equal(t, "final", got)
 }
 
+func TestEncodeStructSkipsFuncAndChan(t *testing.T) {
+	enc := New(nil)
+	testCase := struct {
+		Value    string       `mapstructure:"value"`
+		Func     func() error `mapstructure:"func"`
+		Untagged func(string) bool
+		Chan     chan struct{} `mapstructure:"chan"`
+	}{
+		Value:    "value",
+		Func:     func() error { return nil },
+		Untagged: func(string) bool { return true },
+		Chan:     make(chan struct{}),
+	}
+	got, err := enc.Encode(testCase)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"value": "value"}, got)
+}
+
 func TestEncodeStructError(t *testing.T) {
 	enc := New(&EncoderConfig{
 		EncodeHook: testHookFunc(),
@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confmap // import "go.opentelemetry.io/collector/confmap"
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MergeStrategy defines how the configurations retrieved from multiple URIs are merged by the Resolver.
+// The configurations are merged in the order of the URIs, each one taking precedence over the previous ones.
+type MergeStrategy string
+
+const (
+	// MergeStrategyOverride merges the maps recursively and replaces all the other values, including
+	// the lists, with the ones of the later configurations. This is the default strategy.
+	MergeStrategyOverride MergeStrategy = "override"
+
+	// MergeStrategyAppend merges the maps recursively, appends the lists of the later configurations
+	// to the ones of the previous configurations, and replaces all the other values.
+	MergeStrategyAppend MergeStrategy = "append"
+
+	// MergeStrategyKeyed merges the maps recursively, merges the lists as sets keyed by their elements,
+	// and replaces all the other values. The elements already in a list keep their position and the
+	// elements not in it are appended in order, e.g. the component IDs of the
+	// "service::pipelines::*::processors" lists are added without duplicating the ones already set.
+	MergeStrategyKeyed MergeStrategy = "keyed"
+)
+
+// DeleteMarker marks the values to delete from the previous configurations when merging with the
+// MergeStrategyAppend or MergeStrategyKeyed strategies:
+//   - A map key with the DeleteMarker value deletes the key, e.g. "exporters::debug: __delete__".
+//   - A list element that is a map with the single DeleteMarker key deletes the elements equal to its
+//     value from the list, e.g. "- __delete__: batch".
+//
+// The markers are removed from the merged configuration, whether they deleted a value or not, including
+// from the lists not matching the merge paths, which replace the previous ones.
+const DeleteMarker = "__delete__"
+
+// isValid returns whether the MergeStrategy is known, an empty MergeStrategy is MergeStrategyOverride.
+func (s MergeStrategy) isValid() bool {
+	switch s {
+	case "", MergeStrategyOverride, MergeStrategyAppend, MergeStrategyKeyed:
+		return true
+	}
+	return false
+}
+
+// mergePaths are the paths of the lists merged with the MergeStrategyAppend and MergeStrategyKeyed
+// strategies, split by KeyDelimiter. All the lists are merged if there is no path.
+type mergePaths [][]string
+
+func newMergePaths(paths []string) mergePaths {
+	ret := make(mergePaths, 0, len(paths))
+	for _, path := range paths {
+		ret = append(ret, strings.Split(path, KeyDelimiter))
+	}
+	return ret
+}
+
+// match returns whether the list at the given path is merged, the "*" segments match any key.
+func (mp mergePaths) match(path []string) bool {
+	if len(mp) == 0 {
+		return true
+	}
+	for _, pattern := range mp {
+		if matchPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, segment := range pattern {
+		if segment != "*" && segment != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeWithStrategy merges the input given configuration into the existing config using the given MergeStrategy,
+// the lists not matching the given paths are replaced like with MergeStrategyOverride.
+func (l *Conf) mergeWithStrategy(in *Conf, strategy MergeStrategy, paths mergePaths) error {
+	if strategy == "" || strategy == MergeStrategyOverride {
+		return l.Merge(in)
+	}
+	l.k = NewFromStringMap(mergeMaps(l.ToStringMap(), in.ToStringMap(), strategy, paths, nil)).k
+	return nil
+}
+
+// mergeMaps merges src, at the given path, into dst, which is modified, and returns it.
+func mergeMaps(dst, src map[string]any, strategy MergeStrategy, paths mergePaths, path []string) map[string]any {
+	for key, srcVal := range src {
+		if isDeleteMarker(srcVal) {
+			delete(dst, key)
+			continue
+		}
+		// Copy the path so the paths of the keys don't share their backing array.
+		keyPath := append(path[:len(path):len(path)], key)
+		switch val := srcVal.(type) {
+		case map[string]any:
+			dstMap, ok := dst[key].(map[string]any)
+			if !ok {
+				dstMap = map[string]any{}
+			}
+			dst[key] = mergeMaps(dstMap, val, strategy, paths, keyPath)
+		case []any:
+			if !paths.match(keyPath) {
+				// The list replaces the previous one, without the markers which have nothing to delete.
+				dst[key] = mergeLists(nil, val, MergeStrategyAppend)
+				continue
+			}
+			dstList, _ := dst[key].([]any)
+			dst[key] = mergeLists(dstList, val, strategy)
+		default:
+			dst[key] = srcVal
+		}
+	}
+	return dst
+}
+
+// mergeLists returns the elements of dst not deleted by the markers of src, followed by the
+// elements of src, all of them with MergeStrategyAppend or only the ones not in dst with MergeStrategyKeyed.
+func mergeLists(dst, src []any, strategy MergeStrategy) []any {
+	var deleted, added []any
+	for _, elem := range src {
+		if m, ok := elem.(map[string]any); ok && len(m) == 1 {
+			if val, ok := m[DeleteMarker]; ok {
+				deleted = append(deleted, val)
+				continue
+			}
+		}
+		added = append(added, elem)
+	}
+
+	ret := make([]any, 0, len(dst)+len(added))
+	for _, elem := range dst {
+		if !containsElem(deleted, elem) {
+			ret = append(ret, elem)
+		}
+	}
+	for _, elem := range added {
+		if strategy == MergeStrategyKeyed && containsElem(ret, elem) {
+			continue
+		}
+		ret = append(ret, elem)
+	}
+	return ret
+}
+
+func isDeleteMarker(val any) bool {
+	str, ok := val.(string)
+	return ok && str == DeleteMarker
+}
+
+func containsElem(list []any, elem any) bool {
+	for _, e := range list {
+		if reflect.DeepEqual(e, elem) {
+			return true
+		}
+	}
+	return false
+}
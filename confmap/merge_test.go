@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confmap
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverMergeStrategies(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		strategy MergeStrategy
+		expected map[string]any
+	}{
+		{
+			name:     "default",
+			strategy: "",
+			expected: map[string]any{
+				"debug":      DeleteMarker,
+				"extensions": []any{"health_check", "pprof"},
+				"processors": []any{"memory_limiter", "attributes", "batch"},
+				"exporters":  []any{map[string]any{DeleteMarker: "debug"}},
+			},
+		},
+		{
+			name:     "override",
+			strategy: MergeStrategyOverride,
+			expected: map[string]any{
+				"debug":      DeleteMarker,
+				"extensions": []any{"health_check", "pprof"},
+				"processors": []any{"memory_limiter", "attributes", "batch"},
+				"exporters":  []any{map[string]any{DeleteMarker: "debug"}},
+			},
+		},
+		{
+			name:     "append",
+			strategy: MergeStrategyAppend,
+			expected: map[string]any{
+				"debug":      nil,
+				"extensions": []any{"health_check", "health_check", "pprof"},
+				"processors": []any{"memory_limiter", "batch", "memory_limiter", "attributes", "batch"},
+				"exporters":  []any{"otlp"},
+			},
+		},
+		{
+			name:     "keyed",
+			strategy: MergeStrategyKeyed,
+			expected: map[string]any{
+				"debug":      nil,
+				"extensions": []any{"health_check", "pprof"},
+				"processors": []any{"memory_limiter", "batch", "attributes"},
+				"exporters":  []any{"otlp"},
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewResolver(ResolverSettings{
+				URIs: []string{
+					filepath.Join("testdata", "merge-base.yaml"),
+					filepath.Join("testdata", "merge-overlay.yaml"),
+				},
+				Providers:     makeMapProvidersMap(newFileProvider(t)),
+				MergeStrategy: tt.strategy,
+				MergePaths:    []string{"service::extensions", "service::pipelines::*::processors", "service::pipelines::*::exporters"},
+			})
+			require.NoError(t, err)
+			cfg, err := resolver.Resolve(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, "collector:4317", cfg.Get("exporters::otlp::endpoint"))
+			assert.Equal(t, "1s", cfg.Get("processors::memory_limiter::check_interval"))
+			// The lists not matching the merge paths are replaced.
+			assert.Equal(t, []any{map[string]any{"key": "env", "value": "prod", "action": "insert"}}, cfg.Get("processors::attributes::actions"))
+			assert.Equal(t, tt.expected["debug"], cfg.Get("exporters::debug"))
+			assert.Equal(t, tt.expected["extensions"], cfg.Get("service::extensions"))
+			assert.Equal(t, []any{"otlp"}, cfg.Get("service::pipelines::traces::receivers"))
+			assert.Equal(t, tt.expected["processors"], cfg.Get("service::pipelines::traces::processors"))
+			assert.Equal(t, tt.expected["exporters"], cfg.Get("service::pipelines::traces::exporters"))
+			assert.NoError(t, resolver.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestResolverMergeStrategyPrecedence(t *testing.T) {
+	resolver, err := NewResolver(ResolverSettings{
+		URIs: []string{"mock:first", "fake:second", "file:third"},
+		Providers: makeMapProvidersMap(
+			&mockProvider{retM: map[string]any{
+				"list":   []any{"a", "b"},
+				"value":  "first",
+				"delete": map[string]any{"key": "first"},
+			}},
+			newFakeProvider("fake", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+				return NewRetrieved(map[string]any{
+					"list":   []any{map[string]any{DeleteMarker: "a"}, "c"},
+					"value":  "second",
+					"delete": DeleteMarker,
+				})
+			}),
+			newFakeProvider("file", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+				return NewRetrieved(map[string]any{
+					"list":   []any{"a", "b"},
+					"delete": map[string]any{"other": "third"},
+				})
+			}),
+		),
+		MergeStrategy: MergeStrategyKeyed,
+	})
+	require.NoError(t, err)
+	cfg, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	// The configurations are merged in order: the deleted values can be set again by the later ones.
+	assert.Equal(t, map[string]any{
+		"list":   []any{"b", "c", "a"},
+		"value":  "second",
+		"delete": map[string]any{"other": "third"},
+	}, cfg.ToStringMap())
+}
+
+func TestResolverMergeStrategyDeleteMarkersOnly(t *testing.T) {
+	resolver, err := NewResolver(ResolverSettings{
+		URIs: []string{"mock:"},
+		Providers: makeMapProvidersMap(&mockProvider{retM: map[string]any{
+			"map":  map[string]any{"key": DeleteMarker, "other": "value"},
+			"list": []any{map[string]any{DeleteMarker: "a"}, "b"},
+		}}),
+		MergeStrategy: MergeStrategyAppend,
+	})
+	require.NoError(t, err)
+	cfg, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	// The markers with nothing to delete are removed.
+	assert.Equal(t, map[string]any{
+		"map":  map[string]any{"other": "value"},
+		"list": []any{"b"},
+	}, cfg.ToStringMap())
+}
+
+func TestResolverMergeStrategyDeleteMarkersNotMatchingPaths(t *testing.T) {
+	resolver, err := NewResolver(ResolverSettings{
+		URIs: []string{"mock:", "fake:"},
+		Providers: makeMapProvidersMap(
+			&mockProvider{retM: map[string]any{
+				"merged":   []any{"a", "b"},
+				"replaced": []any{"a", "b"},
+			}},
+			newFakeProvider("fake", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+				return NewRetrieved(map[string]any{
+					"merged":   []any{map[string]any{DeleteMarker: "a"}, "c"},
+					"replaced": []any{map[string]any{DeleteMarker: "a"}, "c", "c"},
+				})
+			}),
+		),
+		MergeStrategy: MergeStrategyKeyed,
+		MergePaths:    []string{"merged"},
+	})
+	require.NoError(t, err)
+	cfg, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	// The lists not matching the merge paths are replaced, and their markers are removed.
+	assert.Equal(t, map[string]any{
+		"merged":   []any{"b", "c"},
+		"replaced": []any{"c", "c"},
+	}, cfg.ToStringMap())
+}
+
+func TestMergePathsMatch(t *testing.T) {
+	paths := newMergePaths([]string{"service::extensions", "service::pipelines::*::processors"})
+	assert.True(t, paths.match([]string{"service", "extensions"}))
+	assert.True(t, paths.match([]string{"service", "pipelines", "traces", "processors"}))
+	assert.True(t, paths.match([]string{"service", "pipelines", "logs/2", "processors"}))
+	assert.False(t, paths.match([]string{"service", "pipelines", "traces", "exporters"}))
+	assert.False(t, paths.match([]string{"service", "pipelines", "processors"}))
+	assert.False(t, paths.match([]string{"processors", "attributes", "actions"}))
+	// All the lists are merged without paths.
+	assert.True(t, newMergePaths(nil).match([]string{"processors", "attributes", "actions"}))
+}
+
+func TestResolverUnknownMergeStrategy(t *testing.T) {
+	_, err := NewResolver(ResolverSettings{
+		URIs:          []string{"mock:"},
+		Providers:     makeMapProvidersMap(&mockProvider{}),
+		MergeStrategy: "unknown",
+	})
+	assert.EqualError(t, err, `invalid map resolver config: unknown merge strategy "unknown"`)
+}
@@ -48,6 +48,9 @@ type Provider interface {
 	// watcher may be nil, which indicates that the caller is not interested in
 	// knowing about the changes.
 	//
+	// If the selected data doesn't exist, the returned error should wrap fs.ErrNotExist,
+	// so the default value of the embedded uri, if any, is used instead.
+	//
 	// If ctx is cancelled should return immediately with an error.
 	// Should never be called concurrently with itself or with Shutdown.
 	Retrieve(ctx context.Context, uri string, watcher WatcherFunc) (*Retrieved, error)
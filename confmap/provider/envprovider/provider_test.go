@@ -68,3 +68,39 @@ func TestEnv(t *testing.T) {
 
 	assert.NoError(t, env.Shutdown(context.Background()))
 }
+
+func TestResolveDefaultValues(t *testing.T) {
+	t.Setenv("CONFIG", `
+exporters:
+  otlp:
+    endpoint: ${env:OTLP_HOST:-localhost}:${env:OTLP_PORT:-4317}
+    compression: ${env:OTLP_COMPRESSION:-${env:DEFAULT_COMPRESSION:-gzip}}
+    headers:
+      api-key: ${env:API_KEY:?API_KEY must be set}
+`)
+	t.Setenv("OTLP_PORT", "")
+	t.Setenv("DEFAULT_COMPRESSION", "zstd")
+	t.Setenv("API_KEY", "secret")
+
+	resolver, err := confmap.NewResolver(confmap.ResolverSettings{
+		URIs:      []string{envSchemePrefix + "CONFIG"},
+		Providers: map[string]confmap.Provider{schemeName: NewWithSettings(confmap.ProviderSettings{})},
+	})
+	require.NoError(t, err)
+	conf, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"exporters": map[string]any{
+			"otlp": map[string]any{
+				"endpoint":    "localhost:4317",
+				"compression": "zstd",
+				"headers":     map[string]any{"api-key": "secret"},
+			},
+		},
+	}, conf.ToStringMap())
+
+	t.Setenv("API_KEY", "")
+	_, err = resolver.Resolve(context.Background())
+	assert.EqualError(t, err, `the uri "env:API_KEY" references a missing or empty required value: API_KEY must be set`)
+	assert.NoError(t, resolver.Shutdown(context.Background()))
+}
@@ -3,6 +3,7 @@ module go.opentelemetry.io/collector/confmap/provider/fileprovider
 go 1.21
 
 require (
+	github.com/fsnotify/fsnotify v1.7.0
 	github.com/stretchr/testify v1.8.4
 	go.opentelemetry.io/collector/confmap v0.0.0-00010101000000-000000000000
 	go.uber.org/goleak v1.3.0
@@ -19,6 +20,7 @@ require (
 	github.com/mitchellh/reflectwalk v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
+	golang.org/x/sys v0.14.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
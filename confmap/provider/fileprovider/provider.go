@@ -33,6 +33,9 @@ type provider struct{}
 // `file:/path/to/file` - absolute path (unix, windows)
 // `file:c:/path/to/file` - absolute path including drive-letter (windows)
 // `file:c:\path\to\file` - absolute path including drive-letter (windows)
+//
+// The retrieved files are watched for changes, including the files replaced by a rename or by a Kubernetes
+// ConfigMap update, and the watcher is called once their content changes.
 func NewWithSettings(confmap.ProviderSettings) confmap.Provider {
 	return &provider{}
 }
@@ -57,18 +60,38 @@ func New() confmap.Provider {
 	return NewWithSettings(confmap.ProviderSettings{})
 }
 
-func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+func (fmp *provider) Retrieve(_ context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
 	if !strings.HasPrefix(uri, schemeName+":") {
 		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, schemeName)
 	}
 
 	// Clean the path before using it.
-	content, err := os.ReadFile(filepath.Clean(uri[len(schemeName)+1:]))
+	path := filepath.Clean(uri[len(schemeName)+1:])
+	if watcher == nil {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the file %v: %w", uri, err)
+		}
+		return internal.NewRetrievedFromYAML(content)
+	}
+
+	// Watch the file before reading it so no change is missed.
+	fw, err := newFileWatcher(path, watcher)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
 	if err != nil {
+		_ = fw.watcher.Close()
 		return nil, fmt.Errorf("unable to read the file %v: %w", uri, err)
 	}
-
-	return internal.NewRetrievedFromYAML(content)
+	ret, err := internal.NewRetrievedFromYAML(content, confmap.WithRetrievedClose(fw.close))
+	if err != nil {
+		_ = fw.watcher.Close()
+		return nil, err
+	}
+	fw.start(content)
+	return ret, nil
 }
 
 func (*provider) Scheme() string {
@@ -87,3 +87,18 @@ func absolutePath(t *testing.T, relativePath string) string {
 	require.NoError(t, err)
 	return filepath.Join(dir, relativePath)
 }
+
+func TestResolveDefaultValues(t *testing.T) {
+	resolver, err := confmap.NewResolver(confmap.ResolverSettings{
+		URIs:      []string{fileSchemePrefix + filepath.Join("testdata", "default-values.yaml")},
+		Providers: map[string]confmap.Provider{schemeName: NewWithSettings(confmap.ProviderSettings{})},
+	})
+	require.NoError(t, err)
+	conf, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"processors": map[string]any{"batch": nil},
+		"exporters":  map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317"}},
+	}, conf.ToStringMap())
+	assert.NoError(t, resolver.Shutdown(context.Background()))
+}
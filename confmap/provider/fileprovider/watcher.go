@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileprovider // import "go.opentelemetry.io/collector/confmap/provider/fileprovider"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// watchDelay is the time without changes in the watched directories before the file is read again,
+// so the multiple events of an update, e.g. the renames and removals of a Kubernetes ConfigMap update,
+// trigger a single reload.
+const watchDelay = 250 * time.Millisecond
+
+// fileWatcher calls the watcher once the content of the file changes.
+type fileWatcher struct {
+	path     string
+	content  []byte
+	onChange confmap.WatcherFunc
+	watcher  *fsnotify.Watcher
+	dirs     map[string]struct{}
+	doneCh   chan struct{}
+}
+
+func newFileWatcher(path string, onChange confmap.WatcherFunc) (*fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the watcher of the file %v: %w", path, err)
+	}
+	fw := &fileWatcher{
+		path:     path,
+		onChange: onChange,
+		watcher:  watcher,
+		doneCh:   make(chan struct{}),
+	}
+	if err = fw.watchDirs(); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	return fw, nil
+}
+
+// watchDirs watches the directory of the file, and the directory of its target if it's a symbolic link,
+// rather than the file itself to see it being replaced, e.g. renamed over by editors or swapped by
+// Kubernetes ConfigMap updates.
+func (fw *fileWatcher) watchDirs() error {
+	dirs := map[string]struct{}{filepath.Dir(fw.path): {}}
+	if target, err := filepath.EvalSymlinks(fw.path); err == nil {
+		dirs[filepath.Dir(target)] = struct{}{}
+	}
+	var errs error
+	for dir := range dirs {
+		if _, ok := fw.dirs[dir]; ok {
+			continue
+		}
+		if err := fw.watcher.Add(dir); err != nil {
+			delete(dirs, dir)
+			errs = errors.Join(errs, fmt.Errorf("failed to watch the directory %v: %w", dir, err))
+		}
+	}
+	for dir := range fw.dirs {
+		if _, ok := dirs[dir]; !ok {
+			// The directory may already be removed along with its watch.
+			_ = fw.watcher.Remove(dir)
+		}
+	}
+	fw.dirs = dirs
+	return errs
+}
+
+// start watches the changes of the file from the given content, read after the watcher was created
+// so no change is missed.
+func (fw *fileWatcher) start(content []byte) {
+	fw.content = content
+	go fw.run()
+}
+
+func (fw *fileWatcher) run() {
+	defer close(fw.doneCh)
+	timer := time.NewTimer(watchDelay)
+	defer timer.Stop()
+	// The timer only runs after events.
+	if !timer.Stop() {
+		<-timer.C
+	}
+	var timerC <-chan time.Time
+	resetTimer := func() {
+		if timerC != nil && !timer.Stop() {
+			<-timer.C
+		}
+		timer.Reset(watchDelay)
+		timerC = timer.C
+	}
+	for {
+		select {
+		case _, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+			resetTimer()
+		case err, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+			if !errors.Is(err, fsnotify.ErrEventOverflow) {
+				fw.onChange(&confmap.ChangeEvent{Error: fmt.Errorf("failed to watch the file %v: %w", fw.path, err)})
+				return
+			}
+			// Some events are lost, check the file.
+			resetTimer()
+		case <-timerC:
+			timerC = nil
+			if fw.changed() {
+				// The retrieved configuration is outdated, the watcher is called only once.
+				fw.onChange(&confmap.ChangeEvent{})
+				return
+			}
+		}
+	}
+}
+
+// changed returns whether the file can be read with a different content.
+func (fw *fileWatcher) changed() bool {
+	// Follow the symbolic link to its new target, if any.
+	_ = fw.watchDirs()
+	content, err := os.ReadFile(fw.path)
+	// The file may be missing while it's replaced, it's read again on the next events.
+	return err == nil && !bytes.Equal(content, fw.content)
+}
+
+func (fw *fileWatcher) close(ctx context.Context) error {
+	err := fw.watcher.Close()
+	select {
+	case <-fw.doneCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+const (
+	configV1 = "exporters:\n  otlp:\n    endpoint: localhost:4317\n"
+	configV2 = "exporters:\n  otlp:\n    endpoint: localhost:4318\n"
+)
+
+// retrieveWatched retrieves the file and returns the channel of the watcher events.
+func retrieveWatched(t *testing.T, path string) <-chan *confmap.ChangeEvent {
+	events := make(chan *confmap.ChangeEvent, 10)
+	fp := NewWithSettings(confmap.ProviderSettings{})
+	ret, err := fp.Retrieve(context.Background(), fileSchemePrefix+path, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, ret.Close(context.Background()))
+		assert.NoError(t, fp.Shutdown(context.Background()))
+	})
+	return events
+}
+
+func requireChangeEvent(t *testing.T, events <-chan *confmap.ChangeEvent) {
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Error)
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "no change event")
+	}
+}
+
+func requireNoChangeEvent(t *testing.T, events <-chan *confmap.ChangeEvent) {
+	select {
+	case event := <-events:
+		require.Failf(t, "unexpected change event", "%v", event)
+	case <-time.After(4 * watchDelay):
+	}
+}
+
+func TestWatchWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	events := retrieveWatched(t, path)
+
+	require.NoError(t, os.WriteFile(path, []byte(configV2), 0600))
+	requireChangeEvent(t, events)
+	// The watcher is called once, the retrieved configuration is outdated.
+	require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	requireNoChangeEvent(t, events)
+}
+
+func TestWatchRename(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	events := retrieveWatched(t, path)
+
+	tmpPath := filepath.Join(dir, "config.yaml.tmp")
+	require.NoError(t, os.WriteFile(tmpPath, []byte(configV2), 0600))
+	require.NoError(t, os.Rename(tmpPath, path))
+	requireChangeEvent(t, events)
+}
+
+func TestWatchUnchangedContent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	events := retrieveWatched(t, path)
+
+	// Neither the other files nor the same content trigger a reload.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.yaml"), []byte(configV2), 0600))
+	require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	requireNoChangeEvent(t, events)
+
+	require.NoError(t, os.WriteFile(path, []byte(configV2), 0600))
+	requireChangeEvent(t, events)
+}
+
+func TestWatchRemovedAndCreated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	events := retrieveWatched(t, path)
+
+	require.NoError(t, os.Remove(path))
+	requireNoChangeEvent(t, events)
+
+	require.NoError(t, os.WriteFile(path, []byte(configV2), 0600))
+	requireChangeEvent(t, events)
+}
+
+func TestWatchDebounce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	events := retrieveWatched(t, path)
+
+	// The file is read once the writes stop, and changed.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, os.WriteFile(path, []byte(configV2), 0600))
+		require.NoError(t, os.WriteFile(path, []byte(configV1), 0600))
+	}
+	requireNoChangeEvent(t, events)
+}
+
+func TestWatchKubernetesConfigMap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows, symbolic links require privileges")
+	}
+	// A ConfigMap volume has the file as a symbolic link to the "..data" symbolic link to a timestamped
+	// directory, and is updated by atomically renaming a new "..data" symbolic link.
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..2024_01_01"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..2024_01_01", "config.yaml"), []byte(configV1), 0600))
+	require.NoError(t, os.Symlink("..2024_01_01", filepath.Join(dir, "..data")))
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.Symlink(filepath.Join("..data", "config.yaml"), path))
+	events := retrieveWatched(t, path)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..2024_01_02"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..2024_01_02", "config.yaml"), []byte(configV2), 0600))
+	require.NoError(t, os.Symlink("..2024_01_02", filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "..2024_01_01")))
+	requireChangeEvent(t, events)
+}
+
+func TestWatchSymlinkTarget(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows, symbolic links require privileges")
+	}
+	targetPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(targetPath, []byte(configV1), 0600))
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.Symlink(targetPath, path))
+	events := retrieveWatched(t, path)
+
+	// The target is updated in place, in a directory other than the one of the link.
+	require.NoError(t, os.WriteFile(targetPath, []byte(configV2), 0600))
+	requireChangeEvent(t, events)
+}
+
+func TestWatchInvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("[invalid,"), 0600))
+	fp := NewWithSettings(confmap.ProviderSettings{})
+	_, err := fp.Retrieve(context.Background(), fileSchemePrefix+path, func(*confmap.ChangeEvent) {})
+	assert.Error(t, err)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestWatchNonExistent(t *testing.T) {
+	fp := NewWithSettings(confmap.ProviderSettings{})
+	_, err := fp.Retrieve(context.Background(), fileSchemePrefix+filepath.Join(t.TempDir(), "non-existent.yaml"), func(*confmap.ChangeEvent) {})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = fp.Retrieve(context.Background(), fileSchemePrefix+filepath.Join(t.TempDir(), "non-existent", "config.yaml"), func(*confmap.ChangeEvent) {})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
@@ -4,6 +4,8 @@
 package httpprovider // import "go.opentelemetry.io/collector/confmap/provider/httpprovider"
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/provider/internal/configurablehttpprovider"
 )
@@ -26,3 +28,24 @@ func NewWithSettings(set confmap.ProviderSettings) confmap.Provider {
 func New() confmap.Provider {
 	return NewWithSettings(confmap.ProviderSettings{})
 }
+
+// Option customizes the provider returned by NewWithOptions.
+type Option = configurablehttpprovider.Option
+
+// WithPollInterval polls the configuration at the given interval, and calls the watcher once its content changes.
+// The requests have an If-None-Match header with the ETag of the last response, if any, so the server can reply
+// with 304 Not Modified. The configuration isn't polled if the interval is 0, the default.
+func WithPollInterval(interval time.Duration) Option {
+	return configurablehttpprovider.WithPollInterval(interval)
+}
+
+// WithHeaders sets the headers of the requests, e.g. to authenticate them.
+func WithHeaders(headers map[string]string) Option {
+	return configurablehttpprovider.WithHeaders(headers)
+}
+
+// NewWithOptions returns a new confmap.Provider that reads the configuration from a http server,
+// customized with the given options.
+func NewWithOptions(set confmap.ProviderSettings, opts ...Option) confmap.Provider {
+	return configurablehttpprovider.New(configurablehttpprovider.HTTPScheme, set, opts...)
+}
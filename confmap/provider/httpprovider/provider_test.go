@@ -5,10 +5,16 @@ package httpprovider
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap"
 )
 
 func TestSupportedScheme(t *testing.T) {
@@ -16,3 +22,28 @@ func TestSupportedScheme(t *testing.T) {
 	assert.Equal(t, "http", fp.Scheme())
 	require.NoError(t, fp.Shutdown(context.Background()))
 }
+
+func TestNewWithOptions(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "value", r.Header.Get("X-Header"))
+		w.Header().Set("ETag", `"1"`)
+		if r.Header.Get("If-None-Match") == `"1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("key: value"))
+	}))
+	defer ts.Close()
+
+	fp := NewWithOptions(confmap.ProviderSettings{}, WithPollInterval(10*time.Millisecond), WithHeaders(map[string]string{"X-Header": "value"}))
+	assert.Equal(t, "http", fp.Scheme())
+	ret, err := fp.Retrieve(context.Background(), ts.URL, func(*confmap.ChangeEvent) {
+		assert.Fail(t, "unexpected change event")
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return requests.Load() > 2 }, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, ret.Close(context.Background()))
+	require.NoError(t, fp.Shutdown(context.Background()))
+}
@@ -4,6 +4,8 @@
 package httpsprovider // import "go.opentelemetry.io/collector/confmap/provider/httpsprovider"
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/provider/internal/configurablehttpprovider"
 )
@@ -13,7 +15,7 @@ import (
 // This Provider supports "https" scheme. One example of an HTTPS URI is: https://localhost:3333/getConfig
 //
 // To add extra CA certificates you need to install certificates in the system pool. This procedure is operating system
-// dependent. E.g.: on Linux please refer to the `update-ca-trust` command.
+// dependent. E.g.: on Linux please refer to the `update-ca-trust` command. NewWithOptions can add them with WithCACertPath.
 func NewWithSettings(set confmap.ProviderSettings) confmap.Provider {
 	return configurablehttpprovider.New(configurablehttpprovider.HTTPSScheme, set)
 }
@@ -28,3 +30,34 @@ func NewWithSettings(set confmap.ProviderSettings) confmap.Provider {
 func New() confmap.Provider {
 	return NewWithSettings(confmap.ProviderSettings{})
 }
+
+// Option customizes the provider returned by NewWithOptions.
+type Option = configurablehttpprovider.Option
+
+// WithPollInterval polls the configuration at the given interval, and calls the watcher once its content changes.
+// The requests have an If-None-Match header with the ETag of the last response, if any, so the server can reply
+// with 304 Not Modified. The configuration isn't polled if the interval is 0, the default.
+func WithPollInterval(interval time.Duration) Option {
+	return configurablehttpprovider.WithPollInterval(interval)
+}
+
+// WithHeaders sets the headers of the requests, e.g. to authenticate them.
+func WithHeaders(headers map[string]string) Option {
+	return configurablehttpprovider.WithHeaders(headers)
+}
+
+// WithCACertPath adds the CA certificates in the given PEM file to the system ones to verify the server certificate.
+func WithCACertPath(caCertPath string) Option {
+	return configurablehttpprovider.WithCACertPath(caCertPath)
+}
+
+// WithClientCertificate authenticates the requests with the client certificate and key in the given PEM files.
+func WithClientCertificate(certPath string, keyPath string) Option {
+	return configurablehttpprovider.WithClientCertificate(certPath, keyPath)
+}
+
+// NewWithOptions returns a new confmap.Provider that reads the configuration from a https server,
+// customized with the given options.
+func NewWithOptions(set confmap.ProviderSettings, opts ...Option) confmap.Provider {
+	return configurablehttpprovider.New(configurablehttpprovider.HTTPSScheme, set, opts...)
+}
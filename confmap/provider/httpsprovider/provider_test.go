@@ -4,12 +4,25 @@
 package httpsprovider
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap"
 )
 
 func TestSupportedScheme(t *testing.T) {
 	fp := New()
 	assert.Equal(t, "https", fp.Scheme())
 }
+
+func TestNewWithOptions(t *testing.T) {
+	fp := NewWithOptions(confmap.ProviderSettings{}, WithPollInterval(time.Minute), WithCACertPath("ca.crt"), WithClientCertificate("client.crt", "client.key"))
+	assert.Equal(t, "https", fp.Scheme())
+	_, err := fp.Retrieve(context.Background(), "https://localhost", nil)
+	assert.ErrorContains(t, err, "unable to read CA")
+	require.NoError(t, fp.Shutdown(context.Background()))
+}
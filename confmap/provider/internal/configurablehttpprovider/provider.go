@@ -4,15 +4,18 @@
 package configurablehttpprovider // import "go.opentelemetry.io/collector/confmap/provider/internal/configurablehttpprovider"
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/provider/internal"
@@ -27,8 +30,46 @@ const (
 
 type provider struct {
 	scheme             SchemeType
-	caCertPath         string // Used for tests
-	insecureSkipVerify bool   // Used for tests
+	caCertPath         string
+	clientCertPath     string
+	clientKeyPath      string
+	headers            map[string]string
+	pollInterval       time.Duration
+	insecureSkipVerify bool // Used for tests
+}
+
+// Option customizes the provider.
+type Option func(*provider)
+
+// WithPollInterval polls the configuration at the given interval, and calls the watcher once its content changes.
+// The requests have an If-None-Match header with the ETag of the last response, if any, so the server can reply
+// with 304 Not Modified. The configuration isn't polled if the interval is 0, the default.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *provider) {
+		p.pollInterval = interval
+	}
+}
+
+// WithHeaders sets the headers of the requests.
+func WithHeaders(headers map[string]string) Option {
+	return func(p *provider) {
+		p.headers = headers
+	}
+}
+
+// WithCACertPath adds the CA certificates in the given PEM file to the system ones to verify the server certificate.
+func WithCACertPath(caCertPath string) Option {
+	return func(p *provider) {
+		p.caCertPath = caCertPath
+	}
+}
+
+// WithClientCertificate authenticates the requests with the client certificate and key in the given PEM files.
+func WithClientCertificate(certPath string, keyPath string) Option {
+	return func(p *provider) {
+		p.clientCertPath = certPath
+		p.clientKeyPath = keyPath
+	}
 }
 
 // New returns a new provider that reads the configuration from http server using the configured transport mechanism
@@ -38,8 +79,12 @@ type provider struct {
 // One example for http-uri: http://localhost:3333/getConfig
 // One example for https-uri: https://localhost:3333/getConfig
 // This is used by the http and https external implementations.
-func New(scheme SchemeType, _ confmap.ProviderSettings) confmap.Provider {
-	return &provider{scheme: scheme}
+func New(scheme SchemeType, _ confmap.ProviderSettings, opts ...Option) confmap.Provider {
+	p := &provider{scheme: scheme}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // Create the client based on the type of scheme that was selected.
@@ -66,12 +111,21 @@ func (fmp *provider) createClient() (*http.Client, error) {
 			}
 		}
 
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: fmp.insecureSkipVerify,
+			RootCAs:            pool,
+		}
+		if fmp.clientCertPath != "" || fmp.clientKeyPath != "" {
+			cert, err := tls.LoadX509KeyPair(filepath.Clean(fmp.clientCertPath), filepath.Clean(fmp.clientKeyPath))
+			if err != nil {
+				return nil, fmt.Errorf("unable to load the client certificate from %q and %q: %w", fmp.clientCertPath, fmp.clientKeyPath, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
 		return &http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: fmp.insecureSkipVerify,
-					RootCAs:            pool,
-				},
+				TLSClientConfig: tlsConfig,
 			},
 		}, nil
 	default:
@@ -79,7 +133,7 @@ func (fmp *provider) createClient() (*http.Client, error) {
 	}
 }
 
-func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+func (fmp *provider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
 
 	if !strings.HasPrefix(uri, string(fmp.scheme)+":") {
 		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, string(fmp.scheme))
@@ -91,25 +145,139 @@ func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFu
 		return nil, fmt.Errorf("unable to configure http transport layer: %w", err)
 	}
 
+	body, etag, err := fmp.get(ctx, client, uri, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if watcher == nil || fmp.pollInterval <= 0 {
+		return internal.NewRetrievedFromYAML(body)
+	}
+	pw := newPollWatcher(fmp, client, uri, body, etag, watcher)
+	ret, err := internal.NewRetrievedFromYAML(body, confmap.WithRetrievedClose(pw.close))
+	if err != nil {
+		return nil, err
+	}
+	pw.start()
+	return ret, nil
+}
+
+// get sends a HTTP GET request for the uri, and returns the body and the ETag of the response. If etag isn't empty,
+// it's sent in an If-None-Match header and a nil body is returned if the server replies with 304 Not Modified.
+func (fmp *provider) get(ctx context.Context, client *http.Client, uri string, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to create the HTTP GET request for uri %q: %w", uri, err)
+	}
+	for k, v := range fmp.headers {
+		req.Header.Set(k, v)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
 	// send a HTTP GET request
-	resp, err := client.Get(uri)
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("unable to download the file via HTTP GET for uri %q: %w ", uri, err)
+		return nil, "", fmt.Errorf("unable to download the file via HTTP GET for uri %q: %w ", uri, err)
 	}
 	defer resp.Body.Close()
 
 	// check the HTTP status code
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// Wrap fs.ErrNotExist so the default value of the uri, if any, is used.
+		return nil, "", fmt.Errorf("failed to load resource from uri %q. status code: %d: %w", uri, resp.StatusCode, fs.ErrNotExist)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to load resource from uri %q. status code: %d", uri, resp.StatusCode)
+		return nil, "", fmt.Errorf("failed to load resource from uri %q. status code: %d", uri, resp.StatusCode)
 	}
 
 	// read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("fail to read the response body from uri %q: %w", uri, err)
+		return nil, "", fmt.Errorf("fail to read the response body from uri %q: %w", uri, err)
+	}
+
+	return body, resp.Header.Get("ETag"), nil
+}
+
+// pollWatcher polls the configuration and calls the watcher once its content changes.
+type pollWatcher struct {
+	provider *provider
+	client   *http.Client
+	uri      string
+	body     []byte
+	etag     string
+	onChange confmap.WatcherFunc
+	ctx      context.Context
+	cancel   context.CancelFunc
+	doneCh   chan struct{}
+}
+
+func newPollWatcher(fmp *provider, client *http.Client, uri string, body []byte, etag string, onChange confmap.WatcherFunc) *pollWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &pollWatcher{
+		provider: fmp,
+		client:   client,
+		uri:      uri,
+		body:     body,
+		etag:     etag,
+		onChange: onChange,
+		ctx:      ctx,
+		cancel:   cancel,
+		doneCh:   make(chan struct{}),
 	}
+}
+
+func (pw *pollWatcher) start() {
+	go pw.run()
+}
 
-	return internal.NewRetrievedFromYAML(body)
+func (pw *pollWatcher) run() {
+	defer close(pw.doneCh)
+	ticker := time.NewTicker(pw.provider.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pw.ctx.Done():
+			return
+		case <-ticker.C:
+			if pw.changed() {
+				// The retrieved configuration is outdated, the watcher is called only once.
+				pw.onChange(&confmap.ChangeEvent{})
+				return
+			}
+		}
+	}
+}
+
+// changed returns whether the configuration can be retrieved with a different content. The failed requests
+// are retried at the next poll, e.g. while the server is restarted.
+func (pw *pollWatcher) changed() bool {
+	body, etag, err := pw.provider.get(pw.ctx, pw.client, pw.uri, pw.etag)
+	if err != nil || body == nil {
+		return false
+	}
+	if bytes.Equal(body, pw.body) {
+		// Only the ETag changed, e.g. the server was redeployed.
+		pw.etag = etag
+		return false
+	}
+	return true
+}
+
+func (pw *pollWatcher) close(ctx context.Context) error {
+	pw.cancel()
+	select {
+	case <-pw.doneCh:
+		pw.client.CloseIdleConnections()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (fmp *provider) Scheme() string {
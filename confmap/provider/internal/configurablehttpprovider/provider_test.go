@@ -12,11 +12,14 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
+	"io/fs"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -75,7 +78,7 @@ func generateCertificate(hostname string) (cert string, key string, err error) {
 		NotBefore:             notBefore,
 		NotAfter:              notAfter,
 		KeyUsage:              keyUsage,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
 		IsCA:                  true,
 		DNSNames:              []string{hostname},
@@ -268,7 +271,7 @@ func TestNonExistent(t *testing.T) {
 	}))
 	defer ts.Close()
 	_, err := fp.Retrieve(context.Background(), ts.URL, nil)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
 	require.NoError(t, fp.Shutdown(context.Background()))
 }
 
@@ -303,3 +306,216 @@ func TestInvalidTransport(t *testing.T) {
 	_, err := fp.Retrieve(context.Background(), "foo://..", nil)
 	assert.Error(t, err)
 }
+
+func TestClientCertificateAndHeaders(t *testing.T) {
+	certPath, keyPath, err := generateCertificate("localhost")
+	require.NoError(t, err)
+	defer os.Remove(certPath)
+	defer os.Remove(keyPath)
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.NoError(t, err)
+	certPEM, err := os.ReadFile(certPath)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(certPEM))
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		answerGet(w, r)
+	}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	uri := "https://localhost:" + tsURL.Port()
+
+	fp := New(HTTPSScheme, confmap.ProviderSettings{},
+		WithCACertPath(certPath),
+		WithClientCertificate(certPath, keyPath),
+		WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+	_, err = fp.Retrieve(context.Background(), uri, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+
+	fp = New(HTTPSScheme, confmap.ProviderSettings{},
+		WithCACertPath(certPath),
+		WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+	_, err = fp.Retrieve(context.Background(), uri, nil)
+	assert.Error(t, err)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+
+	fp = New(HTTPSScheme, confmap.ProviderSettings{},
+		WithCACertPath(certPath),
+		WithClientCertificate(certPath, keyPath))
+	_, err = fp.Retrieve(context.Background(), uri, nil)
+	assert.ErrorContains(t, err, "status code: 401")
+	assert.NoError(t, fp.Shutdown(context.Background()))
+
+	fp = New(HTTPSScheme, confmap.ProviderSettings{},
+		WithCACertPath(certPath),
+		WithClientCertificate(certPath, "no_key"))
+	_, err = fp.Retrieve(context.Background(), uri, nil)
+	assert.ErrorContains(t, err, "unable to load the client certificate")
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+// configServer serves a configuration with an ETag, and replies with 304 Not Modified to the requests with a matching
+// If-None-Match header.
+type configServer struct {
+	mu       sync.Mutex
+	body     string
+	etag     string
+	requests []*http.Request
+}
+
+func (cs *configServer) set(body string, etag string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.body = body
+	cs.etag = etag
+}
+
+func (cs *configServer) requestCount() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return len(cs.requests)
+}
+
+func (cs *configServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.requests = append(cs.requests, r)
+	if cs.etag != "" {
+		w.Header().Set("ETag", cs.etag)
+		if r.Header.Get("If-None-Match") == cs.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	_, _ = w.Write([]byte(cs.body))
+}
+
+func TestPolling(t *testing.T) {
+	cs := &configServer{body: "key: value1", etag: `"1"`}
+	ts := httptest.NewServer(cs)
+	defer ts.Close()
+
+	events := make(chan *confmap.ChangeEvent, 10)
+	fp := New(HTTPScheme, confmap.ProviderSettings{}, WithPollInterval(10*time.Millisecond))
+	ret, err := fp.Retrieve(context.Background(), ts.URL, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+	raw, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "value1"}, raw)
+
+	// The unmodified configuration doesn't call the watcher.
+	require.Eventually(t, func() bool { return cs.requestCount() > 3 }, 10*time.Second, 10*time.Millisecond)
+	cs.mu.Lock()
+	assert.Equal(t, `"1"`, cs.requests[len(cs.requests)-1].Header.Get("If-None-Match"))
+	cs.mu.Unlock()
+	// Neither does the same configuration with another ETag.
+	cs.set("key: value1", `"2"`)
+	require.Eventually(t, func() bool {
+		cs.mu.Lock()
+		defer cs.mu.Unlock()
+		return cs.requests[len(cs.requests)-1].Header.Get("If-None-Match") == `"2"`
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Empty(t, events)
+
+	cs.set("key: value2", `"3"`)
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Error)
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "no change event")
+	}
+
+	require.NoError(t, ret.Close(context.Background()))
+	// The watcher is called once, the retrieved configuration is outdated.
+	count := cs.requestCount()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, count, cs.requestCount())
+	assert.Empty(t, events)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestPollingWithoutETag(t *testing.T) {
+	cs := &configServer{body: "key: value1"}
+	ts := httptest.NewServer(cs)
+	defer ts.Close()
+
+	events := make(chan *confmap.ChangeEvent, 10)
+	fp := New(HTTPScheme, confmap.ProviderSettings{}, WithPollInterval(10*time.Millisecond))
+	ret, err := fp.Retrieve(context.Background(), ts.URL, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+
+	// The content is compared when the server doesn't support ETags.
+	require.Eventually(t, func() bool { return cs.requestCount() > 3 }, 10*time.Second, 10*time.Millisecond)
+	assert.Empty(t, events)
+
+	cs.set("key: value2", "")
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Error)
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "no change event")
+	}
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestPollingServerErrors(t *testing.T) {
+	var unavailable atomic.Bool
+	cs := &configServer{body: "key: value1", etag: `"1"`}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unavailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		cs.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	events := make(chan *confmap.ChangeEvent, 10)
+	fp := New(HTTPScheme, confmap.ProviderSettings{}, WithPollInterval(10*time.Millisecond))
+	ret, err := fp.Retrieve(context.Background(), ts.URL, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+
+	// The failed requests are retried at the next poll.
+	unavailable.Store(true)
+	time.Sleep(50 * time.Millisecond)
+	cs.set("key: value2", `"2"`)
+	unavailable.Store(false)
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Error)
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "no change event")
+	}
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestNoPollingWithoutWatcher(t *testing.T) {
+	cs := &configServer{body: "key: value1"}
+	ts := httptest.NewServer(cs)
+	defer ts.Close()
+
+	fp := New(HTTPScheme, confmap.ProviderSettings{}, WithPollInterval(10*time.Millisecond))
+	ret, err := fp.Retrieve(context.Background(), ts.URL, nil)
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, cs.requestCount())
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
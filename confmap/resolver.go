@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"go.uber.org/multierr"
@@ -22,9 +24,12 @@ type Resolver struct {
 	uris       []location
 	providers  map[string]Provider
 	converters []Converter
+	strategy   MergeStrategy
+	mergePaths mergePaths
 
-	closers []CloseFunc
-	watcher chan error
+	closers      []CloseFunc
+	watcher      chan error
+	expandedKeys []string
 }
 
 // ResolverSettings are the settings to configure the behavior of the Resolver.
@@ -39,12 +44,22 @@ type ResolverSettings struct {
 
 	// MapConverters is a slice of Converter.
 	Converters []Converter
+
+	// MergeStrategy defines how the configurations retrieved from the URIs are merged.
+	// If empty, MergeStrategyOverride is used.
+	MergeStrategy MergeStrategy
+
+	// MergePaths are the paths of the lists merged with the MergeStrategyAppend and MergeStrategyKeyed
+	// strategies, e.g. "service::pipelines::*::processors", where a "*" segment matches any key. The
+	// other lists are replaced like with MergeStrategyOverride. If empty, all the lists are merged.
+	MergePaths []string
 }
 
 // NewResolver returns a new Resolver that resolves configuration from multiple URIs.
 //
 // To resolve a configuration the following steps will happen:
-//  1. Retrieves individual configurations from all given "URIs", and merge them in the retrieve order.
+//  1. Retrieves individual configurations from all given "URIs", and merge them in the retrieve order
+//     using the "MergeStrategy".
 //  2. Once the Conf is merged, apply the converters in the given order.
 //
 // After the configuration was resolved the `Resolver` can be used as a single point to watch for updates in
@@ -69,6 +84,10 @@ func NewResolver(set ResolverSettings) (*Resolver, error) {
 		return nil, errors.New("invalid map resolver config: no Providers")
 	}
 
+	if !set.MergeStrategy.isValid() {
+		return nil, fmt.Errorf("invalid map resolver config: unknown merge strategy %q", set.MergeStrategy)
+	}
+
 	// Safe copy, ensures the slices and maps cannot be changed from the caller.
 	uris := make([]location, len(set.URIs))
 	for i, uri := range set.URIs {
@@ -99,6 +118,8 @@ func NewResolver(set ResolverSettings) (*Resolver, error) {
 		uris:       uris,
 		providers:  providersCopy,
 		converters: convertersCopy,
+		strategy:   set.MergeStrategy,
+		mergePaths: newMergePaths(set.MergePaths),
 		watcher:    make(chan error, 1),
 	}, nil
 }
@@ -124,11 +145,19 @@ func (mr *Resolver) Resolve(ctx context.Context) (*Conf, error) {
 		if err != nil {
 			return nil, err
 		}
-		if err = retMap.Merge(retCfgMap); err != nil {
+		if err = retMap.mergeWithStrategy(retCfgMap, mr.strategy, mr.mergePaths); err != nil {
 			return nil, err
 		}
 	}
 
+	// Keep the values referencing other values, e.g. "${env:PASSWORD}", to find the expanded ones once resolved.
+	references := map[string]any{}
+	for _, k := range retMap.AllKeys() {
+		if val := retMap.Get(k); containsReference(val) {
+			references[k] = val
+		}
+	}
+
 	cfgMap := make(map[string]any)
 	for _, k := range retMap.AllKeys() {
 		val, err := mr.expandValueRecursively(ctx, retMap.Get(k))
@@ -146,9 +175,27 @@ func (mr *Resolver) Resolve(ctx context.Context) (*Conf, error) {
 		}
 	}
 
+	// The converters expand values as well, e.g. the expandconverter expands "$ENV" and "${ENV}".
+	mr.expandedKeys = mr.expandedKeys[:0]
+	for k, val := range references {
+		if retMap.IsSet(k) && !reflect.DeepEqual(val, retMap.Get(k)) {
+			mr.expandedKeys = append(mr.expandedKeys, k)
+		}
+	}
+	sort.Strings(mr.expandedKeys)
+
 	return retMap, nil
 }
 
+// ExpandedKeys returns the keys of the values of the last resolved configuration which were expanded from
+// other values, e.g. from "${env:PASSWORD}", and can be sensitive. The keys of the values in maps are the
+// ones of the maps, e.g. "exporters" for the "exporters: ${file:exporters.yaml}" configuration.
+//
+// Should never be called concurrently with Resolve.
+func (mr *Resolver) ExpandedKeys() []string {
+	return append([]string(nil), mr.expandedKeys...)
+}
+
 // Watch blocks until any configuration change was detected or an unrecoverable error
 // happened during monitoring the configuration changes.
 //
@@ -165,13 +212,13 @@ func (mr *Resolver) Watch() <-chan error {
 //
 // Should never be called concurrently with itself or Get.
 func (mr *Resolver) Shutdown(ctx context.Context) error {
-	close(mr.watcher)
-
 	var errs error
+	// Close the retrieved values first so their watchers don't send to the closed Watch channel.
 	errs = multierr.Append(errs, mr.closeIfNeeded(ctx))
 	for _, p := range mr.providers {
 		errs = multierr.Append(errs, p.Shutdown(ctx))
 	}
+	close(mr.watcher)
 
 	return errs
 }
@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+)
+
+// batchSender is a component that places requests into batches before passing them to the downstream senders.
+// Batches are sent out with any of the following conditions:
+// - batch size reaches cfg.MinSizeItems
+// - cfg.FlushTimeout is elapsed since the timestamp when the previous batch was sent out.
+// - concurrencyLimit is reached.
+// Requests that don't implement RequestMergeSplitter are passed through without batching.
+// The requests created from pdata are copied before being merged, so the request of a caller that stops waiting
+// for its batch, because its context is done, is left unchanged while the batch is still exported.
+type batchSender struct {
+	baseRequestSender
+	cfg exporterbatcher.Config
+
+	// concurrencyLimit is the maximum number of goroutines that can be blocked by the batcher.
+	// If this number is reached and all the goroutines are busy, the batch will be sent right away.
+	// Populated from the number of queue consumers if queue is enabled, the number can change over time
+	// if the queue consumers are adaptive.
+	concurrencyLimit func() uint64
+	activeRequests   atomic.Uint64
+
+	resetTimerCh chan struct{}
+
+	mu          sync.Mutex
+	activeBatch *batch
+
+	shutdownCh chan struct{}
+	stopped    *atomic.Bool
+}
+
+// newBatchSender returns a new batch consumer component.
+func newBatchSender(cfg exporterbatcher.Config) *batchSender {
+	return &batchSender{
+		activeBatch:  newEmptyBatch(),
+		cfg:          cfg,
+		shutdownCh:   make(chan struct{}),
+		stopped:      &atomic.Bool{},
+		resetTimerCh: make(chan struct{}, 1),
+	}
+}
+
+func (bs *batchSender) Start(_ context.Context, _ component.Host) error {
+	timer := time.NewTimer(bs.cfg.FlushTimeout)
+	go func() {
+		for {
+			select {
+			case <-bs.shutdownCh:
+				// Flush the pending batch, the requests coming after the shutdown signal bypass the batcher.
+				bs.mu.Lock()
+				if bs.activeBatch.request != nil {
+					bs.exportActiveBatch()
+				}
+				bs.mu.Unlock()
+				timer.Stop()
+				return
+			case <-timer.C:
+				bs.mu.Lock()
+				if bs.activeBatch.request != nil {
+					bs.exportActiveBatch()
+				}
+				bs.mu.Unlock()
+				timer.Reset(bs.cfg.FlushTimeout)
+			case <-bs.resetTimerCh:
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(bs.cfg.FlushTimeout)
+			}
+		}
+	}()
+
+	return nil
+}
+
+type batch struct {
+	ctx     context.Context
+	request Request
+	done    chan struct{}
+	err     error
+}
+
+func newEmptyBatch() *batch {
+	return &batch{
+		ctx:  context.Background(),
+		done: make(chan struct{}),
+	}
+}
+
+// exportActiveBatch exports the active batch asynchronously and replaces it with a new one.
+// Caller must hold the lock.
+func (bs *batchSender) exportActiveBatch() {
+	go func(b *batch) {
+		b.err = bs.nextSender.send(b.ctx, b.request)
+		close(b.done)
+	}(bs.activeBatch)
+	bs.activeBatch = newEmptyBatch()
+}
+
+// isActiveBatchReady returns true if the active batch is ready to be exported.
+// The batch is ready if it has reached the minimum size or the concurrency limit is reached.
+// Caller must hold the lock.
+func (bs *batchSender) isActiveBatchReady() bool {
+	return bs.activeBatch.request.ItemsCount() >= bs.cfg.MinSizeItems ||
+		(bs.concurrencyLimit != nil && bs.activeRequests.Load() >= bs.concurrencyLimit())
+}
+
+func (bs *batchSender) send(ctx context.Context, req Request) error {
+	msReq, ok := req.(RequestMergeSplitter)
+	if !ok {
+		return bs.nextSender.send(ctx, req)
+	}
+
+	if rc, ok := req.(requestCopier); ok {
+		msReq = rc.copyRequest().(RequestMergeSplitter)
+	}
+
+	bs.activeRequests.Add(1)
+	defer bs.activeRequests.Add(^uint64(0))
+
+	bs.mu.Lock()
+	// Stopped batch sender should act as pass-through to allow the queue to be drained.
+	// The flag is checked under the lock, so no request is added to the batch after the final flush.
+	if bs.stopped.Load() {
+		bs.mu.Unlock()
+		return bs.nextSender.send(ctx, req)
+	}
+	var reqs []Request
+	var err error
+	if bs.activeBatch.request != nil {
+		reqs, err = bs.activeBatch.request.(RequestMergeSplitter).MergeSplit(ctx, bs.cfg.MaxSizeConfig, msReq)
+	} else {
+		reqs, err = msReq.MergeSplit(ctx, bs.cfg.MaxSizeConfig, nil)
+	}
+	if err != nil || len(reqs) == 0 {
+		bs.mu.Unlock()
+		return err
+	}
+
+	if len(reqs) == 1 || bs.activeBatch.request != nil {
+		bs.updateActiveBatch(ctx, reqs[0])
+		batch := bs.activeBatch
+		if bs.isActiveBatchReady() || len(reqs) > 1 {
+			bs.exportActiveBatch()
+			bs.resetTimer()
+		}
+		bs.mu.Unlock()
+		select {
+		case <-batch.done:
+		case <-ctx.Done():
+			// The merged request stays in the batch, which is exported by the other callers or the flush timeout.
+			return ctx.Err()
+		}
+		if batch.err != nil {
+			return batch.err
+		}
+		reqs = reqs[1:]
+	} else {
+		bs.mu.Unlock()
+	}
+
+	// Intentionally do not put the last request in the active batch to not block it.
+	// TODO: Consider including the partial request in the error to avoid double publishing.
+	for _, r := range reqs {
+		if err = bs.nextSender.send(ctx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestCopier is implemented by the requests that can be copied before being merged into a batch.
+type requestCopier interface {
+	copyRequest() Request
+}
+
+// updateActiveBatch replaces the request of the active batch. Caller must hold the lock.
+func (bs *batchSender) updateActiveBatch(ctx context.Context, req Request) {
+	if bs.activeBatch.request == nil {
+		bs.activeBatch.ctx = ctx
+	}
+	bs.activeBatch.request = req
+}
+
+// resetTimer restarts the flush timeout after a batch was sent out because of its size.
+func (bs *batchSender) resetTimer() {
+	select {
+	case bs.resetTimerCh <- struct{}{}:
+	default:
+	}
+}
+
+func (bs *batchSender) Shutdown(context.Context) error {
+	bs.stopped.Store(true)
+	close(bs.shutdownCh)
+	// Wait for the active requests to finish.
+	for bs.activeRequests.Load() > 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// tracesSink records the number of spans in every exported batch.
+type tracesSink struct {
+	mu      sync.Mutex
+	batches []int
+	err     error
+}
+
+func (ts *tracesSink) push(_ context.Context, td ptrace.Traces) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.batches = append(ts.batches, td.SpanCount())
+	return ts.err
+}
+
+func (ts *tracesSink) exportedBatches() []int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]int{}, ts.batches...)
+}
+
+func newBatchingExporter(t *testing.T, cfg exporterbatcher.Config, opts ...Option) *baseExporter {
+	be, err := newBaseExporter(defaultSettings, defaultType, false, nil, nil, newNoopObsrepSender,
+		append([]Option{WithBatcher(cfg)}, opts...)...)
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	return be
+}
+
+// sendConcurrently sends the requests from separate goroutines and returns the errors once all of them are done.
+func sendConcurrently(be *baseExporter, reqs ...Request) []error {
+	errs := make([]error, len(reqs))
+	wg := sync.WaitGroup{}
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req Request) {
+			defer wg.Done()
+			errs[i] = be.send(context.Background(), req)
+		}(i, req)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestBatchSender_MergeOnFlushTimeout(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 100
+	cfg.FlushTimeout = 50 * time.Millisecond
+	be := newBatchingExporter(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	sink := &tracesSink{}
+	errs := sendConcurrently(be,
+		newTracesRequest(testdata.GenerateTraces(4), sink.push),
+		newTracesRequest(testdata.GenerateTraces(6), sink.push))
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, []int{10}, sink.exportedBatches())
+}
+
+func TestBatchSender_FlushOnMinSize(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 10
+	cfg.FlushTimeout = time.Hour
+	be := newBatchingExporter(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	sink := &tracesSink{}
+	errs := sendConcurrently(be,
+		newTracesRequest(testdata.GenerateTraces(4), sink.push),
+		newTracesRequest(testdata.GenerateTraces(6), sink.push))
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, []int{10}, sink.exportedBatches())
+}
+
+func TestBatchSender_SplitOnMaxSize(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 10
+	cfg.MaxSizeItems = 10
+	cfg.FlushTimeout = 50 * time.Millisecond
+	be := newBatchingExporter(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	sink := &tracesSink{}
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(25), sink.push)))
+	assert.Equal(t, []int{10, 10, 5}, sink.exportedBatches())
+}
+
+func TestBatchSender_ErrorReturnedToAllCallers(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 10
+	cfg.FlushTimeout = time.Hour
+	be := newBatchingExporter(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	wantErr := errors.New("transient error")
+	sink := &tracesSink{err: wantErr}
+	errs := sendConcurrently(be,
+		newTracesRequest(testdata.GenerateTraces(5), sink.push),
+		newTracesRequest(testdata.GenerateTraces(5), sink.push))
+	assert.Equal(t, []error{wantErr, wantErr}, errs)
+	assert.Equal(t, []int{10}, sink.exportedBatches())
+}
+
+func TestBatchSender_ReturnOnContextDone(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 100
+	cfg.FlushTimeout = 100 * time.Millisecond
+	be := newBatchingExporter(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	sink := &tracesSink{}
+	td := testdata.GenerateTraces(4)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, be.send(ctx, newTracesRequest(td, sink.push)), context.DeadlineExceeded)
+
+	// The request of the caller that stopped waiting is exported with the batch, but it is not changed
+	// by the requests merged after it.
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(6), sink.push)))
+	assert.Equal(t, []int{10}, sink.exportedBatches())
+	assert.Equal(t, 4, td.SpanCount())
+}
+
+func TestBatchSender_PassThroughNotMergeableRequests(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.FlushTimeout = time.Hour
+	be := newBatchingExporter(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	mockR := newMockRequest(2, nil)
+	require.NoError(t, be.send(context.Background(), mockR))
+	mockR.checkNumRequests(t, 1)
+}
+
+func TestBatchSender_ShutdownFlushesPendingBatch(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 100
+	cfg.FlushTimeout = time.Hour
+	be := newBatchingExporter(t, cfg)
+
+	sink := &tracesSink{}
+	done := make(chan error)
+	go func() {
+		done <- be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(3), sink.push))
+	}()
+	assert.Eventually(t, func() bool {
+		return be.batchSender.(*batchSender).activeRequests.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, be.Shutdown(context.Background()))
+	require.NoError(t, <-done)
+	assert.Equal(t, []int{3}, sink.exportedBatches())
+
+	// The stopped batcher passes the requests through.
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(2), sink.push)))
+	assert.Equal(t, []int{3, 2}, sink.exportedBatches())
+}
+
+func TestBatchSender_WithQueueFlushesOnConcurrencyLimit(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 2
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 100
+	cfg.FlushTimeout = time.Hour
+	be := newBatchingExporter(t, cfg, WithQueue(qCfg))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+	assert.Equal(t, uint64(2), be.batchSender.(*batchSender).concurrencyLimit())
+
+	sink := &tracesSink{}
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(3), sink.push)))
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(4), sink.push)))
+	assert.Eventually(t, func() bool {
+		batches := sink.exportedBatches()
+		return len(batches) == 1 && batches[0] == 7
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBatchSender_Disabled(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.Enabled = false
+	be := newBatchingExporter(t, cfg)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+	_, ok := be.batchSender.(*batchSender)
+	assert.False(t, ok)
+}
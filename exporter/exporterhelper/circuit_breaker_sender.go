@@ -0,0 +1,247 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+)
+
+const (
+	defaultCircuitBreakerFailureThreshold = 5
+	defaultCircuitBreakerOpenDuration     = 30 * time.Second
+	defaultCircuitBreakerHalfOpenRequests = 1
+)
+
+var errCircuitBreakerOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerSettings defines configuration for stopping the export attempts while the backend is failing.
+type CircuitBreakerSettings struct {
+	// Enabled indicates whether to stop sending the data after a number of consecutive failures.
+	Enabled bool `mapstructure:"enabled"`
+	// FailureThreshold is the number of consecutive failed export attempts after which the circuit is opened.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// OpenDuration is the time to keep the circuit open before letting trial requests through.
+	OpenDuration time.Duration `mapstructure:"open_duration"`
+	// HalfOpenRequests is the number of consecutive successful trial requests required to close the circuit.
+	HalfOpenRequests int `mapstructure:"half_open_requests"`
+	// ParkWhileOpen indicates whether to block the requests until the circuit is half-open instead of failing them.
+	// Together with the sending queue, it keeps the data in the queue while the backend is down.
+	ParkWhileOpen bool `mapstructure:"park_while_open"`
+}
+
+// NewDefaultCircuitBreakerSettings returns the default settings for CircuitBreakerSettings.
+func NewDefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		Enabled:          false,
+		FailureThreshold: defaultCircuitBreakerFailureThreshold,
+		OpenDuration:     defaultCircuitBreakerOpenDuration,
+		HalfOpenRequests: defaultCircuitBreakerHalfOpenRequests,
+	}
+}
+
+// Validate checks if the CircuitBreakerSettings configuration is valid
+func (cbCfg *CircuitBreakerSettings) Validate() error {
+	if !cbCfg.Enabled {
+		return nil
+	}
+
+	if cbCfg.FailureThreshold <= 0 {
+		return errors.New("circuit breaker failure threshold must be positive")
+	}
+
+	if cbCfg.OpenDuration <= 0 {
+		return errors.New("circuit breaker open duration must be positive")
+	}
+
+	if cbCfg.HalfOpenRequests <= 0 {
+		return errors.New("circuit breaker half open requests must be positive")
+	}
+
+	return nil
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreakerSender is a requestSender that stops sending the requests to the backend
+// after a number of consecutive failures, until a trial request succeeds.
+type circuitBreakerSender struct {
+	baseRequestSender
+	cfg          CircuitBreakerSettings
+	logger       *zap.Logger
+	reportStatus func(*component.StatusEvent)
+	stopCh       chan struct{}
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	// successes is the number of consecutive successful trial requests in the half-open state.
+	successes     int
+	openedAt      time.Time
+	trialInFlight bool
+	// stateChangedCh is closed and replaced every time a request can be let through again.
+	stateChangedCh chan struct{}
+}
+
+func newCircuitBreakerSender(cfg CircuitBreakerSettings, set exporter.CreateSettings) *circuitBreakerSender {
+	return &circuitBreakerSender{
+		cfg:            cfg,
+		logger:         set.Logger,
+		reportStatus:   set.TelemetrySettings.ReportStatus,
+		stopCh:         make(chan struct{}),
+		stateChangedCh: make(chan struct{}),
+	}
+}
+
+// Shutdown releases the parked requests.
+func (cbs *circuitBreakerSender) Shutdown(context.Context) error {
+	close(cbs.stopCh)
+	return nil
+}
+
+// send implements the requestSender interface
+func (cbs *circuitBreakerSender) send(ctx context.Context, req Request) error {
+	for {
+		isTrial, waitCh, delay := cbs.acquire()
+		if waitCh == nil {
+			err := cbs.nextSender.send(ctx, req)
+			cbs.record(isTrial, err)
+			return err
+		}
+
+		if !cbs.cfg.ParkWhileOpen {
+			if delay > 0 {
+				return NewThrottleRetry(errCircuitBreakerOpen, delay)
+			}
+			return errCircuitBreakerOpen
+		}
+
+		if err := cbs.park(ctx, waitCh, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// park blocks until the state of the circuit changes or the given delay passes.
+func (cbs *circuitBreakerSender) park(ctx context.Context, waitCh <-chan struct{}, delay time.Duration) error {
+	var timerCh <-chan time.Time
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("request is cancelled or timed out %w", errCircuitBreakerOpen)
+	case <-cbs.stopCh:
+		return experr.NewShutdownErr(errCircuitBreakerOpen)
+	case <-waitCh:
+	case <-timerCh:
+	}
+	return nil
+}
+
+// acquire checks if the request can be sent. If it can't, it returns a channel closed once the state changes
+// and the time left until the circuit is half-open, zero if it's already half-open with a trial request in flight.
+func (cbs *circuitBreakerSender) acquire() (bool, <-chan struct{}, time.Duration) {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	if cbs.state == circuitOpen {
+		if left := cbs.cfg.OpenDuration - time.Since(cbs.openedAt); left > 0 {
+			return false, cbs.stateChangedCh, left
+		}
+		cbs.state = circuitHalfOpen
+		cbs.successes = 0
+		cbs.logger.Info("Circuit breaker is half-open, sending a trial request.")
+	}
+
+	if cbs.state == circuitHalfOpen {
+		if cbs.trialInFlight {
+			return false, cbs.stateChangedCh, 0
+		}
+		cbs.trialInFlight = true
+		return true, nil, 0
+	}
+
+	return false, nil, 0
+}
+
+// record updates the state of the circuit based on the result of the export attempt.
+// Permanent errors mean that the backend is reachable, so they are not counted as failures.
+func (cbs *circuitBreakerSender) record(isTrial bool, err error) {
+	failed := err != nil && !consumererror.IsPermanent(err)
+
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	if isTrial {
+		cbs.trialInFlight = false
+		if failed {
+			cbs.open(err)
+			return
+		}
+		cbs.successes++
+		if cbs.successes >= cbs.cfg.HalfOpenRequests {
+			cbs.close()
+			return
+		}
+		cbs.notifyStateChanged()
+		return
+	}
+
+	if cbs.state != circuitClosed {
+		// The result of a request sent before the circuit was opened.
+		return
+	}
+	if !failed {
+		cbs.failures = 0
+		return
+	}
+	cbs.failures++
+	if cbs.failures >= cbs.cfg.FailureThreshold {
+		cbs.open(err)
+	}
+}
+
+func (cbs *circuitBreakerSender) open(err error) {
+	if cbs.state == circuitClosed {
+		cbs.logger.Warn("Too many consecutive export failures. Opening the circuit breaker.",
+			zap.Error(err), zap.Duration("open_duration", cbs.cfg.OpenDuration))
+		cbs.reportStatus(component.NewRecoverableErrorEvent(fmt.Errorf("%w: %w", errCircuitBreakerOpen, err)))
+	}
+	cbs.state = circuitOpen
+	cbs.openedAt = time.Now()
+	cbs.failures = 0
+	cbs.notifyStateChanged()
+}
+
+func (cbs *circuitBreakerSender) close() {
+	cbs.logger.Info("Trial requests succeeded. Closing the circuit breaker.")
+	cbs.state = circuitClosed
+	cbs.failures = 0
+	cbs.reportStatus(component.NewStatusEvent(component.StatusOK))
+	cbs.notifyStateChanged()
+}
+
+func (cbs *circuitBreakerSender) notifyStateChanged() {
+	close(cbs.stateChangedCh)
+	cbs.stateChangedCh = make(chan struct{})
+}
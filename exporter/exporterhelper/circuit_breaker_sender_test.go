@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// flakyBackend fails the exports while it's down and counts the export attempts.
+type flakyBackend struct {
+	down     atomic.Bool
+	err      error
+	attempts atomic.Int64
+}
+
+func (fb *flakyBackend) push(context.Context, ptrace.Traces) error {
+	fb.attempts.Add(1)
+	if fb.down.Load() {
+		return fb.err
+	}
+	return nil
+}
+
+// statusRecorder records the statuses reported by the exporter.
+type statusRecorder struct {
+	mu       sync.Mutex
+	statuses []component.Status
+}
+
+func (sr *statusRecorder) report(ev *component.StatusEvent) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.statuses = append(sr.statuses, ev.Status())
+}
+
+func (sr *statusRecorder) reported() []component.Status {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return append([]component.Status{}, sr.statuses...)
+}
+
+func newCircuitBreakerExporter(t *testing.T, cfg CircuitBreakerSettings, sr *statusRecorder) *baseExporter {
+	set := defaultSettings
+	set.TelemetrySettings.ReportStatus = sr.report
+	be, err := newBaseExporter(set, defaultType, false, nil, nil, newNoopObsrepSender, WithCircuitBreaker(cfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	return be
+}
+
+func TestCircuitBreakerSettings_Validate(t *testing.T) {
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	assert.NoError(t, cbCfg.Validate())
+
+	cbCfg.Enabled = true
+	assert.NoError(t, cbCfg.Validate())
+
+	cbCfg.FailureThreshold = 0
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker failure threshold must be positive")
+
+	cbCfg = NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.OpenDuration = 0
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker open duration must be positive")
+
+	cbCfg = NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.HalfOpenRequests = -1
+	assert.EqualError(t, cbCfg.Validate(), "circuit breaker half open requests must be positive")
+}
+
+func TestCircuitBreaker_OpenAndClose(t *testing.T) {
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.FailureThreshold = 2
+	cbCfg.OpenDuration = 50 * time.Millisecond
+	sr := &statusRecorder{}
+	be := newCircuitBreakerExporter(t, cbCfg, sr)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	backend := &flakyBackend{err: errors.New("transient error")}
+	backend.down.Store(true)
+	for i := 0; i < 2; i++ {
+		require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	}
+	assert.Equal(t, []component.Status{component.StatusRecoverableError}, sr.reported())
+
+	// The requests are rejected without reaching the backend while the circuit is open.
+	err := be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push))
+	assert.ErrorIs(t, err, errCircuitBreakerOpen)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, int64(2), backend.attempts.Load())
+
+	// The trial request closes the circuit once the backend is back.
+	backend.down.Store(false)
+	time.Sleep(cbCfg.OpenDuration)
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	assert.Equal(t, []component.Status{component.StatusRecoverableError, component.StatusOK}, sr.reported())
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	assert.Equal(t, int64(4), backend.attempts.Load())
+}
+
+func TestCircuitBreaker_FailedTrialReopens(t *testing.T) {
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.FailureThreshold = 1
+	cbCfg.OpenDuration = 20 * time.Millisecond
+	cbCfg.HalfOpenRequests = 2
+	sr := &statusRecorder{}
+	be := newCircuitBreakerExporter(t, cbCfg, sr)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	backend := &flakyBackend{err: errors.New("transient error")}
+	backend.down.Store(true)
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+
+	time.Sleep(cbCfg.OpenDuration)
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	assert.Equal(t, int64(2), backend.attempts.Load())
+	assert.ErrorIs(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)),
+		errCircuitBreakerOpen)
+
+	// Two successful trials are needed to close the circuit.
+	backend.down.Store(false)
+	time.Sleep(cbCfg.OpenDuration)
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	assert.Equal(t, []component.Status{component.StatusRecoverableError}, sr.reported())
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	assert.Equal(t, []component.Status{component.StatusRecoverableError, component.StatusOK}, sr.reported())
+}
+
+func TestCircuitBreaker_PermanentErrorsDoNotOpen(t *testing.T) {
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.FailureThreshold = 1
+	sr := &statusRecorder{}
+	be := newCircuitBreakerExporter(t, cbCfg, sr)
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	backend := &flakyBackend{err: consumererror.NewPermanent(errors.New("bad data"))}
+	backend.down.Store(true)
+	for i := 0; i < 3; i++ {
+		require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	}
+	assert.Equal(t, int64(3), backend.attempts.Load())
+	assert.Empty(t, sr.reported())
+}
+
+func TestCircuitBreaker_ParkWhileOpen(t *testing.T) {
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.FailureThreshold = 1
+	cbCfg.OpenDuration = 50 * time.Millisecond
+	cbCfg.ParkWhileOpen = true
+	be := newCircuitBreakerExporter(t, cbCfg, &statusRecorder{})
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	backend := &flakyBackend{err: errors.New("transient error")}
+	backend.down.Store(true)
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+
+	// The request waits for the circuit to be half-open instead of failing.
+	backend.down.Store(false)
+	start := time.Now()
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, int64(2), backend.attempts.Load())
+}
+
+func TestCircuitBreaker_ParkedRequestReleasedOnShutdown(t *testing.T) {
+	cbCfg := NewDefaultCircuitBreakerSettings()
+	cbCfg.Enabled = true
+	cbCfg.FailureThreshold = 1
+	cbCfg.OpenDuration = time.Hour
+	cbCfg.ParkWhileOpen = true
+	be := newCircuitBreakerExporter(t, cbCfg, &statusRecorder{})
+
+	backend := &flakyBackend{err: errors.New("transient error")}
+	backend.down.Store(true)
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push)))
+
+	done := make(chan error)
+	go func() {
+		done <- be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), backend.push))
+	}()
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.True(t, experr.IsShutdownErr(<-done))
+	assert.Equal(t, int64(1), backend.attempts.Load())
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	be := newCircuitBreakerExporter(t, NewDefaultCircuitBreakerSettings(), &statusRecorder{})
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+	_, ok := be.circuitBreakerSender.(*circuitBreakerSender)
+	assert.False(t, ok)
+}
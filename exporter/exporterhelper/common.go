@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
 )
 
@@ -80,6 +81,14 @@ func WithRetry(config configretry.BackOffConfig) Option {
 	}
 }
 
+// WithRetryBudget limits the retries across all the requests sent by the exporter, it applies only if the retries
+// are enabled with WithRetry. The default RetryBudgetSettings is to not limit the retries.
+func WithRetryBudget(config RetryBudgetSettings) Option {
+	return func(o *baseExporter) {
+		o.retryBudget = newRetryBudget(config)
+	}
+}
+
 // WithQueue overrides the default QueueSettings for an exporter.
 // The default QueueSettings is to disable queueing.
 // This option cannot be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
@@ -92,19 +101,30 @@ func WithQueue(config QueueSettings) Option {
 			o.exportFailureMessage += " Try enabling sending_queue to survive temporary failures."
 			return
 		}
-		qf := exporterqueue.NewPersistentQueueFactory[Request](config.StorageID, exporterqueue.PersistentQueueSettings[Request]{
-			Marshaler:   o.marshaler,
-			Unmarshaler: o.unmarshaler,
-		})
+		var qf exporterqueue.Factory[Request]
+		// The priority lanes are in-memory only, QueueSettings.Validate rejects them with a storage.
+		if len(config.Priority.Lanes) > 0 {
+			qf = exporterqueue.NewPriorityMemoryQueueFactory[Request](newAttributePriorityFunc(config.Priority))
+		} else {
+			qf = exporterqueue.NewPersistentQueueFactory[Request](config.StorageID, exporterqueue.PersistentQueueSettings[Request]{
+				Marshaler:   o.marshaler,
+				Unmarshaler: o.unmarshaler,
+			})
+		}
+		qCfg := exporterqueue.Config{
+			Enabled:             config.Enabled,
+			NumConsumers:        config.NumConsumers,
+			QueueSize:           config.QueueSize,
+			Sizer:               config.Sizer,
+			AdaptiveConcurrency: config.AdaptiveConcurrency,
+			Partition:           config.Partition,
+			Priority:            config.Priority,
+		}
 		q := qf(context.Background(), exporterqueue.Settings{
 			DataType:         o.signal,
 			ExporterSettings: o.set,
-		}, exporterqueue.Config{
-			Enabled:      config.Enabled,
-			NumConsumers: config.NumConsumers,
-			QueueSize:    config.QueueSize,
-		})
-		o.queueSender = newQueueSender(q, o.set, config.NumConsumers, o.exportFailureMessage)
+		}, qCfg)
+		o.queueSender = newQueueSender(q, o.set, qCfg, o.exportFailureMessage)
 	}
 }
 
@@ -122,7 +142,53 @@ func WithRequestQueue(cfg exporterqueue.Config, queueFactory exporterqueue.Facto
 			DataType:         o.signal,
 			ExporterSettings: o.set,
 		}
-		o.queueSender = newQueueSender(queueFactory(context.Background(), set, cfg), o.set, cfg.NumConsumers, o.exportFailureMessage)
+		o.queueSender = newQueueSender(queueFactory(context.Background(), set, cfg), o.set, cfg, o.exportFailureMessage)
+	}
+}
+
+// WithDeadLetter enables storing the requests that failed to be exported in a storage extension.
+// The default DeadLetterSettings is to drop the failed requests.
+// This option cannot be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
+func WithDeadLetter(config DeadLetterSettings) Option {
+	return func(o *baseExporter) {
+		if o.requestExporter {
+			panic("WithDeadLetter option is not available for the new request exporters")
+		}
+		if !config.Enabled {
+			return
+		}
+		o.deadLetterSender = newDeadLetterSender(config, o.set, o.signal, o.marshaler, o.unmarshaler)
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker that stops sending the data after a number of consecutive
+// failed export attempts and reports the exporter status as a recoverable error until the backend recovers.
+// The default CircuitBreakerSettings is to disable the circuit breaker.
+func WithCircuitBreaker(config CircuitBreakerSettings) Option {
+	return func(o *baseExporter) {
+		if !config.Enabled {
+			return
+		}
+		o.circuitBreakerSender = newCircuitBreakerSender(config, o.set)
+	}
+}
+
+// WithBatcher enables batching for an exporter based on the given configuration.
+// Only requests implementing RequestMergeSplitter are batched, which includes the requests created by
+// New[Traces|Metrics|Logs]Exporter. Their data is copied before being merged, while the requests of the new
+// request exporters are merged in place, so these exporters are reported as mutating the data unless the
+// capabilities are overridden with WithCapabilities.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithBatcher(cfg exporterbatcher.Config) Option {
+	return func(o *baseExporter) {
+		if !cfg.Enabled {
+			return
+		}
+		o.batchSender = newBatchSender(cfg)
+		if o.requestExporter {
+			o.consumerOptions = append(o.consumerOptions, consumer.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+		}
 	}
 }
 
@@ -151,13 +217,19 @@ type baseExporter struct {
 	// Message for the user to be added with an export failure message.
 	exportFailureMessage string
 
+	// retryBudget is set by WithRetryBudget and passed to the retrySender, nil if the retries are not limited.
+	retryBudget *retryBudget
+
 	// Chain of senders that the exporter helper applies before passing the data to the actual exporter.
 	// The data is handled by each sender in the respective order starting from the queueSender.
 	// Most of the senders are optional, and initialized with a no-op path-through sender.
-	queueSender   requestSender
-	obsrepSender  requestSender
-	retrySender   requestSender
-	timeoutSender *timeoutSender // timeoutSender is always initialized.
+	queueSender          requestSender
+	batchSender          requestSender
+	obsrepSender         requestSender
+	deadLetterSender     requestSender
+	retrySender          requestSender
+	circuitBreakerSender requestSender
+	timeoutSender        *timeoutSender // timeoutSender is always initialized.
 
 	consumerOptions []consumer.Option
 }
@@ -177,10 +249,13 @@ func newBaseExporter(set exporter.CreateSettings, signal component.DataType, req
 		unmarshaler:     unmarshaler,
 		signal:          signal,
 
-		queueSender:   &baseRequestSender{},
-		obsrepSender:  osf(obsReport),
-		retrySender:   &baseRequestSender{},
-		timeoutSender: &timeoutSender{cfg: NewDefaultTimeoutSettings()},
+		queueSender:          &baseRequestSender{},
+		batchSender:          &baseRequestSender{},
+		obsrepSender:         osf(obsReport),
+		deadLetterSender:     &baseRequestSender{},
+		retrySender:          &baseRequestSender{},
+		circuitBreakerSender: &baseRequestSender{},
+		timeoutSender:        &timeoutSender{cfg: NewDefaultTimeoutSettings()},
 
 		set:    set,
 		obsrep: obsReport,
@@ -189,14 +264,40 @@ func newBaseExporter(set exporter.CreateSettings, signal component.DataType, req
 	for _, op := range options {
 		op(be)
 	}
+
+	// Only the requests created by the exporters from pdata report their size in bytes, the queue cannot size
+	// the requests of the new request exporters.
+	if qs, ok := be.queueSender.(*queueSender); ok && qs.bytesSized && be.requestExporter {
+		return nil, errBytesSizerNotSupported
+	}
 	be.connectSenders()
 
+	if rs, ok := be.retrySender.(*retrySender); ok {
+		rs.budget = be.retryBudget
+		// The adaptive concurrency is adjusted after every attempt, not only once the retries are over.
+		if qs, ok := be.queueSender.(*queueSender); ok {
+			rs.recordAttempt = qs.consumers.AttemptRecorder()
+		}
+	}
+
+	// Make the batcher flush right away once all the queue consumers are blocked waiting for the batch.
+	if bs, ok := be.batchSender.(*batchSender); ok {
+		if qs, ok := be.queueSender.(*queueSender); ok {
+			bs.concurrencyLimit = qs.concurrency
+		}
+	}
+
+	// The stored failed requests are replayed through the whole chain of senders.
+	if ds, ok := be.deadLetterSender.(*deadLetterSender); ok {
+		ds.replaySender = be.queueSender
+	}
+
 	return be, nil
 }
 
 // send sends the request using the first sender in the chain.
 func (be *baseExporter) send(ctx context.Context, req Request) error {
-	err := be.queueSender.send(ctx, req)
+	err := be.queueSender.send(be.timeoutSender.contextWithExportDeadline(ctx), req)
 	if err != nil {
 		be.set.Logger.Error("Exporting failed. Rejecting data."+be.exportFailureMessage,
 			zap.Error(err), zap.Int("rejected_items", req.ItemsCount()))
@@ -206,9 +307,12 @@ func (be *baseExporter) send(ctx context.Context, req Request) error {
 
 // connectSenders connects the senders in the predefined order.
 func (be *baseExporter) connectSenders() {
-	be.queueSender.setNextSender(be.obsrepSender)
-	be.obsrepSender.setNextSender(be.retrySender)
-	be.retrySender.setNextSender(be.timeoutSender)
+	be.queueSender.setNextSender(be.batchSender)
+	be.batchSender.setNextSender(be.obsrepSender)
+	be.obsrepSender.setNextSender(be.deadLetterSender)
+	be.deadLetterSender.setNextSender(be.retrySender)
+	be.retrySender.setNextSender(be.circuitBreakerSender)
+	be.circuitBreakerSender.setNextSender(be.timeoutSender)
 }
 
 func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
@@ -217,16 +321,37 @@ func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
-	// If no error then start the queueSender.
-	return be.queueSender.Start(ctx, host)
+	// If no error then start the batchSender.
+	if err := be.batchSender.Start(ctx, host); err != nil {
+		return err
+	}
+
+	// Then start the queueSender.
+	if err := be.queueSender.Start(ctx, host); err != nil {
+		return err
+	}
+
+	// Last start the deadLetterSender, so the stored requests can be replayed through the started senders.
+	return be.deadLetterSender.Start(ctx, host)
 }
 
 func (be *baseExporter) Shutdown(ctx context.Context) error {
+	// First shutdown the retry sender, so the queue sender can flush the queue without retries.
+	err := be.retrySender.Shutdown(ctx)
+	// Then release the requests parked by the circuit breaker, so the queue can be drained.
+	err = multierr.Append(err, be.circuitBreakerSender.Shutdown(ctx))
+	// Then stop replaying the dead letter requests, so no more requests are sent to the queue.
+	if ds, ok := be.deadLetterSender.(*deadLetterSender); ok {
+		ds.stopReplay()
+	}
 	return multierr.Combine(
-		// First shutdown the retry sender, so the queue sender can flush the queue without retries.
-		be.retrySender.Shutdown(ctx),
+		err,
 		// Then shutdown the queue sender.
 		be.queueSender.Shutdown(ctx),
+		// Then shutdown the batch sender, the pending batch is flushed.
+		be.batchSender.Shutdown(ctx),
+		// Then close the dead letter storage, it can get the requests failed while draining the queue.
+		be.deadLetterSender.Shutdown(ctx),
 		// Last shutdown the wrapped exporter itself.
 		be.ShutdownFunc.Shutdown(ctx))
 }
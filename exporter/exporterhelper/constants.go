@@ -24,4 +24,6 @@ var (
 	errNilMetricsConverter = errors.New("nil RequestFromMetricsFunc")
 	// errNilLogsConverter is returned when a nil RequestFromLogsFunc is given.
 	errNilLogsConverter = errors.New("nil RequestFromLogsFunc")
+	// errInvalidBatchRequest is returned when requests of different types are merged into one batch.
+	errInvalidBatchRequest = errors.New("invalid request type for batching")
 )
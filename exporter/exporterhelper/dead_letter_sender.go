@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+)
+
+const defaultDeadLetterMaxRequests = 10_000
+
+var (
+	errDeadLetterDisabled = errors.New("dead letter storage is not enabled for the exporter")
+	errReplayStopped      = errors.New("dead letter replay stopped, the exporter is shutting down")
+)
+
+// DeadLetterReplayer is implemented by the exporters created by the exporterhelper to send the requests kept in
+// the dead letter storage through the exporter again on demand, e.g. from an extension once the backend is fixed.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type DeadLetterReplayer interface {
+	// ReplayDeadLetters sends the requests stored before the call through the exporter again, the requests
+	// failing again are stored back. It blocks until the stored requests are replayed or the context is done,
+	// and returns an error if the exporter is not configured with the WithDeadLetter option.
+	ReplayDeadLetters(ctx context.Context) error
+}
+
+// DeadLetterSettings defines configuration for storing the data that failed to be exported.
+type DeadLetterSettings struct {
+	// Enabled indicates whether to store the requests that failed permanently or ran out of retries.
+	Enabled bool `mapstructure:"enabled"`
+	// StorageID is the storage extension used to keep the failed requests.
+	StorageID *component.ID `mapstructure:"storage"`
+	// MaxRequests is the maximum number of requests kept in the storage. Requests failing after that are dropped.
+	MaxRequests int `mapstructure:"max_requests"`
+	// ReplayOnStart indicates whether to send the stored requests through the exporter again when it's started.
+	// The replayed requests that fail again are put back into the storage.
+	ReplayOnStart bool `mapstructure:"replay_on_start"`
+}
+
+// NewDefaultDeadLetterSettings returns the default settings for DeadLetterSettings.
+func NewDefaultDeadLetterSettings() DeadLetterSettings {
+	return DeadLetterSettings{
+		Enabled:     false,
+		MaxRequests: defaultDeadLetterMaxRequests,
+	}
+}
+
+// Validate checks if the DeadLetterSettings configuration is valid
+func (dlCfg *DeadLetterSettings) Validate() error {
+	if !dlCfg.Enabled {
+		return nil
+	}
+
+	if dlCfg.StorageID == nil {
+		return errors.New("dead letter storage must be set")
+	}
+
+	if dlCfg.MaxRequests <= 0 {
+		return errors.New("dead letter max requests must be positive")
+	}
+
+	return nil
+}
+
+// deadLetterSender is a requestSender that stores the requests failed in the downstream senders
+// in a persistent storage, so they can be replayed later.
+type deadLetterSender struct {
+	baseRequestSender
+	storage       queue.Queue[Request]
+	replayOnStart bool
+	logger        *zap.Logger
+
+	// replaySender is the first sender of the exporter used to replay the stored requests.
+	replaySender requestSender
+	replayMu     sync.Mutex
+	replayStopCh chan struct{}
+	replayWG     sync.WaitGroup
+}
+
+func newDeadLetterSender(cfg DeadLetterSettings, set exporter.CreateSettings, signal component.DataType,
+	marshaler func(Request) ([]byte, error), unmarshaler func([]byte) (Request, error)) *deadLetterSender {
+	return &deadLetterSender{
+		storage: queue.NewPersistentQueue[Request](queue.PersistentQueueSettings[Request]{
+			Sizer:            &queue.RequestSizer[Request]{},
+			Capacity:         cfg.MaxRequests,
+			DataType:         signal,
+			StorageID:        *cfg.StorageID,
+			Marshaler:        marshaler,
+			Unmarshaler:      unmarshaler,
+			ExporterSettings: set,
+			StorageName:      "dead_letter_" + signal.String(),
+		}),
+		replayOnStart: cfg.ReplayOnStart,
+		logger:        set.Logger,
+		replayStopCh:  make(chan struct{}),
+	}
+}
+
+// Start opens the dead letter storage and starts replaying the stored requests if configured.
+func (ds *deadLetterSender) Start(ctx context.Context, host component.Host) error {
+	if err := ds.storage.Start(ctx, host); err != nil {
+		return err
+	}
+	if !ds.replayOnStart {
+		return nil
+	}
+
+	ds.replayWG.Add(1)
+	go func() {
+		defer ds.replayWG.Done()
+		_ = ds.replayStored(context.Background())
+	}()
+	return nil
+}
+
+// replayStored sends the requests stored before the call through the exporter again, the requests failing again
+// are stored back. It stops when the context is done or the exporter is shutting down.
+func (ds *deadLetterSender) replayStored(ctx context.Context) error {
+	// Replays are serialized, so every consumed request is one of the requests counted as stored.
+	ds.replayMu.Lock()
+	defer ds.replayMu.Unlock()
+
+	numStored := ds.storage.Size()
+	if numStored == 0 {
+		return nil
+	}
+	ds.logger.Info("Replaying requests from the dead letter storage", zap.Int("requests", numStored))
+	for i := 0; i < numStored; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ds.replayStopCh:
+			return errReplayStopped
+		default:
+		}
+		if !ds.storage.Consume(ds.replay) {
+			return errReplayStopped
+		}
+	}
+	return nil
+}
+
+func (ds *deadLetterSender) replay(ctx context.Context, req Request) error {
+	err := ds.replaySender.send(ctx, req)
+	if experr.IsShutdownErr(err) {
+		// Keep the request in the storage, it's replayed again on the next start.
+		return err
+	}
+	// Otherwise the request is either delivered or stored again by this sender.
+	return nil
+}
+
+// stopReplay stops the replays of the stored requests and waits for the in-flight ones to finish.
+func (ds *deadLetterSender) stopReplay() {
+	close(ds.replayStopCh)
+	ds.replayWG.Wait()
+	// Wait for the replay requested on demand, the next ones return right away.
+	ds.replayMu.Lock()
+	defer ds.replayMu.Unlock()
+}
+
+// Shutdown closes the dead letter storage.
+func (ds *deadLetterSender) Shutdown(ctx context.Context) error {
+	return ds.storage.Shutdown(ctx)
+}
+
+// send implements the requestSender interface. It stores the request in the dead letter storage
+// if the downstream senders failed to export it permanently or ran out of retries. The other errors,
+// e.g. the ones returned when the request is cancelled or the exporter is shutting down, are returned as is.
+// The requests dropped after the deadline of the incoming request are not stored either, as nobody waits for them.
+func (ds *deadLetterSender) send(ctx context.Context, req Request) error {
+	err := ds.nextSender.send(ctx, req)
+	if err == nil || !(consumererror.IsPermanent(err) || experr.IsRetriesExhaustedErr(err)) || errors.Is(err, errDeadlineExceeded) {
+		return err
+	}
+
+	failedReq := extractPartialRequest(req, err)
+	if dlErr := ds.storage.Offer(context.Background(), failedReq); dlErr != nil {
+		ds.logger.Error("Failed to store the failed request in the dead letter storage. Dropping data.",
+			zap.Error(multierr.Append(err, dlErr)), zap.Int("dropped_items", failedReq.ItemsCount()))
+		return err
+	}
+	ds.logger.Warn("Exporting failed. The data is stored in the dead letter storage.",
+		zap.Error(err), zap.Int("stored_items", failedReq.ItemsCount()))
+	return err
+}
+
+// ReplayDeadLetters implements DeadLetterReplayer.
+func (be *baseExporter) ReplayDeadLetters(ctx context.Context) error {
+	ds, ok := be.deadLetterSender.(*deadLetterSender)
+	if !ok {
+		return errDeadLetterDisabled
+	}
+	return ds.replayStored(ctx)
+}
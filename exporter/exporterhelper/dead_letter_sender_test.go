@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func newDeadLetterTestExporter(t *testing.T, dlCfg DeadLetterSettings, pusher func(context.Context, ptrace.Traces) error,
+	options ...Option) *baseExporter {
+	be, err := newBaseExporter(defaultSettings, defaultType, false, tracesRequestMarshaler,
+		newTraceRequestUnmarshalerFunc(pusher), newNoopObsrepSender, append(options, WithDeadLetter(dlCfg))...)
+	require.NoError(t, err)
+	return be
+}
+
+func TestDeadLetterSettings_Validate(t *testing.T) {
+	dlCfg := NewDefaultDeadLetterSettings()
+	assert.NoError(t, dlCfg.Validate())
+
+	dlCfg.Enabled = true
+	assert.EqualError(t, dlCfg.Validate(), "dead letter storage must be set")
+
+	storageID := component.MustNewID("file_storage")
+	dlCfg.StorageID = &storageID
+	assert.NoError(t, dlCfg.Validate())
+
+	dlCfg.MaxRequests = 0
+	assert.EqualError(t, dlCfg.Validate(), "dead letter max requests must be positive")
+}
+
+func TestDeadLetter_StoreAndReplay(t *testing.T) {
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	dlCfg := NewDefaultDeadLetterSettings()
+	dlCfg.Enabled = true
+	dlCfg.StorageID = &storageID
+
+	// Permanent errors are stored right away.
+	failingPusher := func(context.Context, ptrace.Traces) error {
+		return consumererror.NewPermanent(errors.New("bad data"))
+	}
+	be := newDeadLetterTestExporter(t, dlCfg, failingPusher)
+	require.NoError(t, be.Start(context.Background(), host))
+	err := be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(3), failingPusher))
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, be.deadLetterSender.(*deadLetterSender).storage.Size())
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	// The stored requests are not replayed unless configured.
+	be = newDeadLetterTestExporter(t, dlCfg, failingPusher)
+	require.NoError(t, be.Start(context.Background(), host))
+	assert.Equal(t, 1, be.deadLetterSender.(*deadLetterSender).storage.Size())
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	// Replay through the exporter with a working backend.
+	sink := &tracesSink{}
+	dlCfg.ReplayOnStart = true
+	be = newDeadLetterTestExporter(t, dlCfg, sink.push)
+	require.NoError(t, be.Start(context.Background(), host))
+	assert.Eventually(t, func() bool {
+		return be.deadLetterSender.(*deadLetterSender).storage.Size() == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{3}, sink.exportedBatches())
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestDeadLetter_StoreAfterRetriesExhausted(t *testing.T) {
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	dlCfg := NewDefaultDeadLetterSettings()
+	dlCfg.Enabled = true
+	dlCfg.StorageID = &storageID
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 10 * time.Millisecond
+
+	// Only the failed part of the request is stored.
+	pusher := func(context.Context, ptrace.Traces) error {
+		return consumererror.NewTraces(errors.New("transient error"), testdata.GenerateTraces(1))
+	}
+	be := newDeadLetterTestExporter(t, dlCfg, pusher, WithRetry(rCfg))
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(5), pusher)))
+	ds := be.deadLetterSender.(*deadLetterSender)
+	require.Equal(t, 1, ds.storage.Size())
+	assert.True(t, ds.storage.Consume(func(_ context.Context, req Request) error {
+		assert.Equal(t, 1, req.ItemsCount())
+		return nil
+	}))
+}
+
+func TestDeadLetter_NotStoreRetryableErrors(t *testing.T) {
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	dlCfg := NewDefaultDeadLetterSettings()
+	dlCfg.Enabled = true
+	dlCfg.StorageID = &storageID
+
+	// Without retries, the retryable errors are returned without storing the request.
+	pusher := func(context.Context, ptrace.Traces) error {
+		return errors.New("transient error")
+	}
+	be := newDeadLetterTestExporter(t, dlCfg, pusher)
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(2), pusher)))
+	assert.Equal(t, 0, be.deadLetterSender.(*deadLetterSender).storage.Size())
+}
+
+func TestDeadLetter_NotStoreAfterDeadline(t *testing.T) {
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	dlCfg := NewDefaultDeadLetterSettings()
+	dlCfg.Enabled = true
+	dlCfg.StorageID = &storageID
+
+	pusher := func(context.Context, ptrace.Traces) error {
+		t.Fatal("the request must not be exported after the deadline")
+		return nil
+	}
+	be := newDeadLetterTestExporter(t, dlCfg, pusher)
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	ctx := context.WithValue(context.Background(), exportDeadlineKey{}, time.Now().Add(-time.Second))
+	require.ErrorIs(t, be.send(ctx, newTracesRequest(testdata.GenerateTraces(2), pusher)), errDeadlineExceeded)
+	assert.Equal(t, 0, be.deadLetterSender.(*deadLetterSender).storage.Size())
+}
+
+func TestDeadLetter_ReplayOnDemand(t *testing.T) {
+	storageID := component.MustNewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	dlCfg := NewDefaultDeadLetterSettings()
+	dlCfg.Enabled = true
+	dlCfg.StorageID = &storageID
+
+	sink := &tracesSink{}
+	var backendFixed atomic.Bool
+	pusher := func(ctx context.Context, td ptrace.Traces) error {
+		if !backendFixed.Load() {
+			return consumererror.NewPermanent(errors.New("bad configuration"))
+		}
+		return sink.push(ctx, td)
+	}
+	be := newDeadLetterTestExporter(t, dlCfg, pusher)
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(2), pusher)))
+	require.Error(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(3), pusher)))
+	ds := be.deadLetterSender.(*deadLetterSender)
+	require.Equal(t, 2, ds.storage.Size())
+
+	// The requests failing again are stored back.
+	var replayer DeadLetterReplayer = be
+	require.NoError(t, replayer.ReplayDeadLetters(context.Background()))
+	assert.Equal(t, 2, ds.storage.Size())
+	assert.Empty(t, sink.exportedBatches())
+
+	backendFixed.Store(true)
+	require.NoError(t, replayer.ReplayDeadLetters(context.Background()))
+	assert.Equal(t, 0, ds.storage.Size())
+	assert.Equal(t, []int{2, 3}, sink.exportedBatches())
+}
+
+func TestDeadLetter_DisabledAndRequestExporter(t *testing.T) {
+	be := newDeadLetterTestExporter(t, NewDefaultDeadLetterSettings(), nil)
+	_, ok := be.deadLetterSender.(*deadLetterSender)
+	assert.False(t, ok)
+	assert.ErrorIs(t, be.ReplayDeadLetters(context.Background()), errDeadLetterDisabled)
+
+	require.Panics(t, func() {
+		_, _ = newBaseExporter(defaultSettings, defaultType, true, nil, nil, newNoopObsrepSender,
+			WithDeadLetter(NewDefaultDeadLetterSettings()))
+	})
+}
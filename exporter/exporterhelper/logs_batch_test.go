@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestMergeSplitLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      exporterbatcher.MaxSizeConfig
+		lr1      Request
+		lr2      Request
+		expected []int
+	}{
+		{
+			name:     "merge_only",
+			cfg:      exporterbatcher.MaxSizeConfig{},
+			lr1:      newLogsRequest(testdata.GenerateLogs(4), nil),
+			lr2:      newLogsRequest(testdata.GenerateLogs(6), nil),
+			expected: []int{10},
+		},
+		{
+			name:     "split_only",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 4},
+			lr1:      newLogsRequest(testdata.GenerateLogs(10), nil),
+			expected: []int{4, 4, 2},
+		},
+		{
+			name:     "merge_and_split",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 5},
+			lr1:      newLogsRequest(testdata.GenerateLogs(4), nil),
+			lr2:      newLogsRequest(testdata.GenerateLogs(7), nil),
+			expected: []int{5, 5, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.lr1.(RequestMergeSplitter).MergeSplit(context.Background(), tt.cfg, tt.lr2)
+			require.NoError(t, err)
+			sizes := make([]int, 0, len(res))
+			for _, r := range res {
+				sizes = append(sizes, r.ItemsCount())
+			}
+			assert.Equal(t, tt.expected, sizes)
+		})
+	}
+}
+
+func TestMergeSplitLogsInvalidInput(t *testing.T) {
+	lr := newLogsRequest(testdata.GenerateLogs(2), nil).(RequestMergeSplitter)
+	_, err := lr.MergeSplit(context.Background(), exporterbatcher.MaxSizeConfig{}, newTracesRequest(testdata.GenerateTraces(2), nil))
+	assert.ErrorIs(t, err, errInvalidBatchRequest)
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestMergeSplitMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      exporterbatcher.MaxSizeConfig
+		mr1      Request
+		mr2      Request
+		expected []int
+	}{
+		{
+			name:     "merge_only",
+			cfg:      exporterbatcher.MaxSizeConfig{},
+			mr1:      newMetricsRequest(testdata.GenerateMetrics(2), nil),
+			mr2:      newMetricsRequest(testdata.GenerateMetrics(3), nil),
+			expected: []int{10},
+		},
+		{
+			name:     "split_only",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 4},
+			mr1:      newMetricsRequest(testdata.GenerateMetrics(5), nil),
+			expected: []int{4, 4, 2},
+		},
+		{
+			name:     "merge_and_split",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 5},
+			mr1:      newMetricsRequest(testdata.GenerateMetrics(2), nil),
+			mr2:      newMetricsRequest(testdata.GenerateMetrics(4), nil),
+			expected: []int{5, 5, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.mr1.(RequestMergeSplitter).MergeSplit(context.Background(), tt.cfg, tt.mr2)
+			require.NoError(t, err)
+			sizes := make([]int, 0, len(res))
+			for _, r := range res {
+				sizes = append(sizes, r.ItemsCount())
+			}
+			assert.Equal(t, tt.expected, sizes)
+		})
+	}
+}
+
+func TestMergeSplitMetricsInvalidInput(t *testing.T) {
+	mr := newMetricsRequest(testdata.GenerateMetrics(2), nil).(RequestMergeSplitter)
+	_, err := mr.MergeSplit(context.Background(), exporterbatcher.MaxSizeConfig{}, newTracesRequest(testdata.GenerateTraces(2), nil))
+	assert.ErrorIs(t, err, errInvalidBatchRequest)
+}
+
+func TestSplitMetricsAllTypes(t *testing.T) {
+	md := testdata.GenerateMetricsAllTypes()
+	total := md.DataPointCount()
+	split := splitMetrics(3, md)
+	assert.Equal(t, 3, split.DataPointCount())
+	assert.Equal(t, total-3, md.DataPointCount())
+}
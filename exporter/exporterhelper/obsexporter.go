@@ -5,6 +5,7 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -30,16 +31,19 @@ type ObsReport struct {
 	tracer         trace.Tracer
 	logger         *zap.Logger
 
-	otelAttrs                   []attribute.KeyValue
-	sentSpans                   metric.Int64Counter
-	failedToSendSpans           metric.Int64Counter
-	failedToEnqueueSpans        metric.Int64Counter
-	sentMetricPoints            metric.Int64Counter
-	failedToSendMetricPoints    metric.Int64Counter
-	failedToEnqueueMetricPoints metric.Int64Counter
-	sentLogRecords              metric.Int64Counter
-	failedToSendLogRecords      metric.Int64Counter
-	failedToEnqueueLogRecords   metric.Int64Counter
+	otelAttrs                    []attribute.KeyValue
+	sentSpans                    metric.Int64Counter
+	failedToSendSpans            metric.Int64Counter
+	failedToEnqueueSpans         metric.Int64Counter
+	deadlineExceededSpans        metric.Int64Counter
+	sentMetricPoints             metric.Int64Counter
+	failedToSendMetricPoints     metric.Int64Counter
+	failedToEnqueueMetricPoints  metric.Int64Counter
+	deadlineExceededMetricPoints metric.Int64Counter
+	sentLogRecords               metric.Int64Counter
+	failedToSendLogRecords       metric.Int64Counter
+	failedToEnqueueLogRecords    metric.Int64Counter
+	deadlineExceededLogRecords   metric.Int64Counter
 }
 
 // ObsReportSettings are settings for creating an ObsReport.
@@ -95,6 +99,12 @@ func (or *ObsReport) createOtelMetrics(cfg ObsReportSettings) error {
 		metric.WithUnit("1"))
 	errors = multierr.Append(errors, err)
 
+	or.deadlineExceededSpans, err = meter.Int64Counter(
+		obsmetrics.ExporterPrefix+obsmetrics.DeadlineExceededSpansKey,
+		metric.WithDescription("Number of spans failed to be sent to destination before the request deadline."),
+		metric.WithUnit("1"))
+	errors = multierr.Append(errors, err)
+
 	or.sentMetricPoints, err = meter.Int64Counter(
 		obsmetrics.ExporterPrefix+obsmetrics.SentMetricPointsKey,
 		metric.WithDescription("Number of metric points successfully sent to destination."),
@@ -113,6 +123,12 @@ func (or *ObsReport) createOtelMetrics(cfg ObsReportSettings) error {
 		metric.WithUnit("1"))
 	errors = multierr.Append(errors, err)
 
+	or.deadlineExceededMetricPoints, err = meter.Int64Counter(
+		obsmetrics.ExporterPrefix+obsmetrics.DeadlineExceededMetricPointsKey,
+		metric.WithDescription("Number of metric points failed to be sent to destination before the request deadline."),
+		metric.WithUnit("1"))
+	errors = multierr.Append(errors, err)
+
 	or.sentLogRecords, err = meter.Int64Counter(
 		obsmetrics.ExporterPrefix+obsmetrics.SentLogRecordsKey,
 		metric.WithDescription("Number of log record successfully sent to destination."),
@@ -131,6 +147,12 @@ func (or *ObsReport) createOtelMetrics(cfg ObsReportSettings) error {
 		metric.WithUnit("1"))
 	errors = multierr.Append(errors, err)
 
+	or.deadlineExceededLogRecords, err = meter.Int64Counter(
+		obsmetrics.ExporterPrefix+obsmetrics.DeadlineExceededLogRecordsKey,
+		metric.WithDescription("Number of log records failed to be sent to destination before the request deadline."),
+		metric.WithUnit("1"))
+	errors = multierr.Append(errors, err)
+
 	return errors
 }
 
@@ -144,7 +166,8 @@ func (or *ObsReport) StartTracesOp(ctx context.Context) context.Context {
 // EndTracesOp completes the export operation that was started with StartTracesOp.
 func (or *ObsReport) EndTracesOp(ctx context.Context, numSpans int, err error) {
 	numSent, numFailedToSend := toNumItems(numSpans, err)
-	or.recordMetrics(noCancellationContext{Context: ctx}, component.DataTypeTraces, numSent, numFailedToSend)
+	or.recordMetrics(noCancellationContext{Context: ctx}, component.DataTypeTraces, numSent, numFailedToSend,
+		errors.Is(err, errDeadlineExceeded))
 	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentSpansKey, obsmetrics.FailedToSendSpansKey)
 }
 
@@ -159,7 +182,8 @@ func (or *ObsReport) StartMetricsOp(ctx context.Context) context.Context {
 // StartMetricsOp.
 func (or *ObsReport) EndMetricsOp(ctx context.Context, numMetricPoints int, err error) {
 	numSent, numFailedToSend := toNumItems(numMetricPoints, err)
-	or.recordMetrics(noCancellationContext{Context: ctx}, component.DataTypeMetrics, numSent, numFailedToSend)
+	or.recordMetrics(noCancellationContext{Context: ctx}, component.DataTypeMetrics, numSent, numFailedToSend,
+		errors.Is(err, errDeadlineExceeded))
 	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentMetricPointsKey, obsmetrics.FailedToSendMetricPointsKey)
 }
 
@@ -173,7 +197,8 @@ func (or *ObsReport) StartLogsOp(ctx context.Context) context.Context {
 // EndLogsOp completes the export operation that was started with StartLogsOp.
 func (or *ObsReport) EndLogsOp(ctx context.Context, numLogRecords int, err error) {
 	numSent, numFailedToSend := toNumItems(numLogRecords, err)
-	or.recordMetrics(noCancellationContext{Context: ctx}, component.DataTypeLogs, numSent, numFailedToSend)
+	or.recordMetrics(noCancellationContext{Context: ctx}, component.DataTypeLogs, numSent, numFailedToSend,
+		errors.Is(err, errDeadlineExceeded))
 	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentLogRecordsKey, obsmetrics.FailedToSendLogRecordsKey)
 }
 
@@ -185,25 +210,33 @@ func (or *ObsReport) startOp(ctx context.Context, operationSuffix string) contex
 	return ctx
 }
 
-func (or *ObsReport) recordMetrics(ctx context.Context, dataType component.DataType, sent, failed int64) {
+// recordMetrics records the number of sent and failed items. The failed items are also recorded as failed
+// because of the request deadline if deadlineExceeded is true.
+func (or *ObsReport) recordMetrics(ctx context.Context, dataType component.DataType, sent, failed int64, deadlineExceeded bool) {
 	if or.level == configtelemetry.LevelNone {
 		return
 	}
-	var sentMeasure, failedMeasure metric.Int64Counter
+	var sentMeasure, failedMeasure, deadlineExceededMeasure metric.Int64Counter
 	switch dataType {
 	case component.DataTypeTraces:
 		sentMeasure = or.sentSpans
 		failedMeasure = or.failedToSendSpans
+		deadlineExceededMeasure = or.deadlineExceededSpans
 	case component.DataTypeMetrics:
 		sentMeasure = or.sentMetricPoints
 		failedMeasure = or.failedToSendMetricPoints
+		deadlineExceededMeasure = or.deadlineExceededMetricPoints
 	case component.DataTypeLogs:
 		sentMeasure = or.sentLogRecords
 		failedMeasure = or.failedToSendLogRecords
+		deadlineExceededMeasure = or.deadlineExceededLogRecords
 	}
 
 	sentMeasure.Add(ctx, sent, metric.WithAttributes(or.otelAttrs...))
 	failedMeasure.Add(ctx, failed, metric.WithAttributes(or.otelAttrs...))
+	if deadlineExceeded {
+		deadlineExceededMeasure.Add(ctx, failed, metric.WithAttributes(or.otelAttrs...))
+	}
 }
 
 func endSpan(ctx context.Context, err error, numSent, numFailedToSend int64, sentItemsKey, failedToSendItemsKey string) {
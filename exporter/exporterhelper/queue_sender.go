@@ -17,14 +17,19 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
 	"go.opentelemetry.io/collector/exporter/internal/queue"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 )
 
 const defaultQueueSize = 1000
 
 var (
 	scopeName = "go.opentelemetry.io/collector/exporterhelper"
+
+	errBytesSizerNotSupported = errors.New("the queue sized in bytes is only supported by the exporters created " +
+		"with New[Traces|Metrics|Logs]Exporter, the requests of the other exporters cannot report their size in bytes")
 )
 
 // QueueSettings defines configuration for queueing batches before sending to the consumerSender.
@@ -33,8 +38,20 @@ type QueueSettings struct {
 	Enabled bool `mapstructure:"enabled"`
 	// NumConsumers is the number of consumers from the queue.
 	NumConsumers int `mapstructure:"num_consumers"`
-	// QueueSize is the maximum number of batches allowed in queue at a given time.
+	// QueueSize is the maximum size of the queue at a given time, measured in units defined by Sizer.
 	QueueSize int `mapstructure:"queue_size"`
+	// Sizer defines how the queue size is measured: as the number of batches ("requests", the default),
+	// the number of spans, metric data points or log records ("items"), or serialized bytes ("bytes").
+	Sizer exporterqueue.SizerType `mapstructure:"sizer"`
+	// AdaptiveConcurrency enables adjusting the number of consumers exporting at the same time.
+	// When enabled, NumConsumers is the initial number of consumers.
+	AdaptiveConcurrency exporterqueue.AdaptiveConcurrencyConfig `mapstructure:"adaptive_concurrency"`
+	// Partition enables keeping the requests from different clients in separate partitions of the queue.
+	// It's not supported together with StorageID.
+	Partition exporterqueue.PartitionConfig `mapstructure:"partition"`
+	// Priority enables keeping the requests of different priorities in separate lanes of the queue,
+	// selected by a resource or scope attribute. It's not supported together with StorageID and Partition.
+	Priority exporterqueue.PriorityConfig `mapstructure:"priority"`
 	// StorageID if not empty, enables the persistent storage and uses the component specified
 	// as a storage extension for the persistent queue
 	StorageID *component.ID `mapstructure:"storage"`
@@ -48,7 +65,11 @@ func NewDefaultQueueSettings() QueueSettings {
 		// By default, batches are 8192 spans, for a total of up to 8 million spans in the queue
 		// This can be estimated at 1-4 GB worth of maximum memory usage
 		// This default is probably still too high, and may be adjusted further down in a future release
-		QueueSize: defaultQueueSize,
+		QueueSize:           defaultQueueSize,
+		Sizer:               exporterqueue.SizerTypeRequests,
+		AdaptiveConcurrency: exporterqueue.NewDefaultAdaptiveConcurrencyConfig(),
+		Partition:           exporterqueue.NewDefaultPartitionConfig(),
+		Priority:            exporterqueue.NewDefaultPriorityConfig(),
 	}
 }
 
@@ -66,7 +87,83 @@ func (qCfg *QueueSettings) Validate() error {
 		return errors.New("number of queue consumers must be positive")
 	}
 
-	return nil
+	if err := qCfg.Sizer.Validate(); err != nil {
+		return err
+	}
+
+	if err := qCfg.AdaptiveConcurrency.Validate(); err != nil {
+		return err
+	}
+
+	if qCfg.StorageID != nil && len(qCfg.Partition.MetadataKeys) > 0 {
+		return experr.ErrPartitionedPersistentQueue
+	}
+
+	if qCfg.StorageID != nil && len(qCfg.Priority.Lanes) > 0 {
+		return experr.ErrPriorityPersistentQueue
+	}
+
+	if len(qCfg.Partition.MetadataKeys) > 0 && len(qCfg.Priority.Lanes) > 0 {
+		return experr.ErrPartitionedPriorityQueue
+	}
+
+	if err := qCfg.Partition.Validate(); err != nil {
+		return err
+	}
+
+	return qCfg.Priority.Validate()
+}
+
+// newAttributePriorityFunc returns the function selecting the priority lane of the request by the value of
+// the configured resource or scope attribute. The request containing the data of several priorities is put
+// in the highest priority lane, so the high priority data is never delayed by the bulk data sent along.
+func newAttributePriorityFunc(cfg exporterqueue.PriorityConfig) exporterqueue.PriorityFunc[Request] {
+	lanes := map[string]int{}
+	for i, l := range cfg.Lanes {
+		for _, v := range l.Values {
+			if _, ok := lanes[v]; !ok {
+				lanes[v] = i
+			}
+		}
+	}
+	lowest := len(cfg.Lanes) - 1
+	return func(_ context.Context, req Request) int {
+		prio := lowest
+		visit := func(attrs pcommon.Map) {
+			if v, ok := attrs.Get(cfg.AttributeKey); ok {
+				if i, ok := lanes[v.AsString()]; ok && i < prio {
+					prio = i
+				}
+			}
+		}
+		switch r := req.(type) {
+		case *tracesRequest:
+			for i := 0; i < r.td.ResourceSpans().Len(); i++ {
+				rs := r.td.ResourceSpans().At(i)
+				visit(rs.Resource().Attributes())
+				for j := 0; j < rs.ScopeSpans().Len(); j++ {
+					visit(rs.ScopeSpans().At(j).Scope().Attributes())
+				}
+			}
+		case *metricsRequest:
+			for i := 0; i < r.md.ResourceMetrics().Len(); i++ {
+				rm := r.md.ResourceMetrics().At(i)
+				visit(rm.Resource().Attributes())
+				for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+					visit(rm.ScopeMetrics().At(j).Scope().Attributes())
+				}
+			}
+		case *logsRequest:
+			for i := 0; i < r.ld.ResourceLogs().Len(); i++ {
+				rl := r.ld.ResourceLogs().At(i)
+				visit(rl.Resource().Attributes())
+				for j := 0; j < rl.ScopeLogs().Len(); j++ {
+					visit(rl.ScopeLogs().At(j).Scope().Attributes())
+				}
+			}
+		}
+		return prio
+	}
 }
 
 type queueSender struct {
@@ -77,19 +174,28 @@ type queueSender struct {
 	logger         *zap.Logger
 	meter          otelmetric.Meter
 	consumers      *queue.Consumers[Request]
+	// adaptiveConcurrency indicates whether the number of consumers exporting at the same time is adjusted.
+	adaptiveConcurrency bool
+	// bytesSized indicates whether the queue is sized in bytes.
+	bytesSized bool
 
-	metricCapacity otelmetric.Int64ObservableGauge
-	metricSize     otelmetric.Int64ObservableGauge
+	metricCapacity    otelmetric.Int64ObservableGauge
+	metricSize        otelmetric.Int64ObservableGauge
+	metricConcurrency otelmetric.Int64ObservableGauge
+	metricPartition   otelmetric.Int64ObservableGauge
+	metricLane        otelmetric.Int64ObservableGauge
 }
 
-func newQueueSender(q exporterqueue.Queue[Request], set exporter.CreateSettings, numConsumers int,
+func newQueueSender(q exporterqueue.Queue[Request], set exporter.CreateSettings, cfg exporterqueue.Config,
 	exportFailureMessage string) *queueSender {
 	qs := &queueSender{
-		fullName:       set.ID.String(),
-		queue:          q,
-		traceAttribute: attribute.String(obsmetrics.ExporterKey, set.ID.String()),
-		logger:         set.TelemetrySettings.Logger,
-		meter:          set.TelemetrySettings.MeterProvider.Meter(scopeName),
+		fullName:            set.ID.String(),
+		queue:               q,
+		adaptiveConcurrency: cfg.AdaptiveConcurrency.Enabled,
+		bytesSized:          cfg.Sizer == exporterqueue.SizerTypeBytes,
+		traceAttribute:      attribute.String(obsmetrics.ExporterKey, set.ID.String()),
+		logger:              set.TelemetrySettings.Logger,
+		meter:               set.TelemetrySettings.MeterProvider.Meter(scopeName),
 	}
 	consumeFunc := func(ctx context.Context, req Request) error {
 		err := qs.nextSender.send(ctx, req)
@@ -99,10 +205,23 @@ func newQueueSender(q exporterqueue.Queue[Request], set exporter.CreateSettings,
 		}
 		return err
 	}
-	qs.consumers = queue.NewQueueConsumers[Request](q, numConsumers, consumeFunc)
+	if qs.adaptiveConcurrency {
+		qs.consumers = queue.NewAdaptiveQueueConsumers[Request](q, cfg.NumConsumers, queue.AdaptiveConcurrencySettings{
+			MinConsumers:     cfg.AdaptiveConcurrency.MinConsumers,
+			MaxConsumers:     cfg.AdaptiveConcurrency.MaxConsumers,
+			LatencyThreshold: cfg.AdaptiveConcurrency.LatencyThreshold,
+		}, consumeFunc)
+	} else {
+		qs.consumers = queue.NewQueueConsumers[Request](q, cfg.NumConsumers, consumeFunc)
+	}
 	return qs
 }
 
+// concurrency returns the number of queue consumers allowed to export at the same time.
+func (qs *queueSender) concurrency() uint64 {
+	return uint64(qs.consumers.Concurrency())
+}
+
 // Start is invoked during service startup.
 func (qs *queueSender) Start(ctx context.Context, host component.Host) error {
 	if err := qs.consumers.Start(ctx, host); err != nil {
@@ -113,10 +232,16 @@ func (qs *queueSender) Start(ctx context.Context, host component.Host) error {
 
 	attrs := otelmetric.WithAttributeSet(attribute.NewSet(attribute.String(obsmetrics.ExporterKey, qs.fullName)))
 
+	// The queue size and capacity are reported in the units of the configured sizer.
+	unit := "1"
+	if qs.bytesSized {
+		unit = "By"
+	}
+
 	qs.metricSize, err = qs.meter.Int64ObservableGauge(
 		obsmetrics.ExporterKey+"/queue_size",
-		otelmetric.WithDescription("Current size of the retry queue (in batches)"),
-		otelmetric.WithUnit("1"),
+		otelmetric.WithDescription("Current size of the retry queue (in batches, items or bytes depending on the sizer)"),
+		otelmetric.WithUnit(unit),
 		otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
 			o.Observe(int64(qs.queue.Size()), attrs)
 			return nil
@@ -126,14 +251,56 @@ func (qs *queueSender) Start(ctx context.Context, host component.Host) error {
 
 	qs.metricCapacity, err = qs.meter.Int64ObservableGauge(
 		obsmetrics.ExporterKey+"/queue_capacity",
-		otelmetric.WithDescription("Fixed capacity of the retry queue (in batches)"),
-		otelmetric.WithUnit("1"),
+		otelmetric.WithDescription("Fixed capacity of the retry queue (in batches, items or bytes depending on the sizer)"),
+		otelmetric.WithUnit(unit),
 		otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
 			o.Observe(int64(qs.queue.Capacity()), attrs)
 			return nil
 		}))
-
 	errs = multierr.Append(errs, err)
+
+	if qs.adaptiveConcurrency {
+		qs.metricConcurrency, err = qs.meter.Int64ObservableGauge(
+			obsmetrics.ExporterKey+"/queue_concurrency",
+			otelmetric.WithDescription("Current number of queue consumers allowed to export at the same time"),
+			otelmetric.WithUnit("1"),
+			otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
+				o.Observe(int64(qs.concurrency()), attrs)
+				return nil
+			}))
+		errs = multierr.Append(errs, err)
+	}
+
+	if pq, ok := qs.queue.(queue.PartitionedQueue); ok {
+		qs.metricPartition, err = qs.meter.Int64ObservableGauge(
+			obsmetrics.ExporterKey+"/queue_partition_size",
+			otelmetric.WithDescription("Current size of the retry queue partition (in batches, items or bytes depending on the sizer)"),
+			otelmetric.WithUnit(unit),
+			otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
+				pq.VisitPartitions(func(md attribute.Set, size int) {
+					kvs := append(md.ToSlice(), attribute.String(obsmetrics.ExporterKey, qs.fullName))
+					o.Observe(int64(size), otelmetric.WithAttributes(kvs...))
+				})
+				return nil
+			}))
+		errs = multierr.Append(errs, err)
+	}
+
+	if lq, ok := qs.queue.(queue.LanedQueue); ok {
+		qs.metricLane, err = qs.meter.Int64ObservableGauge(
+			obsmetrics.ExporterKey+"/queue_lane_size",
+			otelmetric.WithDescription("Current size of the retry queue priority lane (in batches, items or bytes depending on the sizer)"),
+			otelmetric.WithUnit(unit),
+			otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
+				lq.VisitLanes(func(name string, size int) {
+					o.Observe(int64(size), otelmetric.WithAttributes(
+						attribute.String(obsmetrics.ExporterKey, qs.fullName), attribute.String("lane", name)))
+				})
+				return nil
+			}))
+		errs = multierr.Append(errs, err)
+	}
+
 	return errs
 }
 
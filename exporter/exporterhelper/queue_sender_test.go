@@ -11,9 +11,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configretry"
@@ -21,6 +24,11 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/internal/queue"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 func TestQueuedRetry_StopWhileWaiting(t *testing.T) {
@@ -394,7 +402,7 @@ func TestQueuedRetryPersistentEnabled_NoDataLossOnShutdown(t *testing.T) {
 
 func TestQueueSenderNoStartShutdown(t *testing.T) {
 	queue := queue.NewBoundedMemoryQueue[Request](queue.MemoryQueueSettings[Request]{})
-	qs := newQueueSender(queue, exportertest.NewNopCreateSettings(), 1, "")
+	qs := newQueueSender(queue, exportertest.NewNopCreateSettings(), exporterqueue.Config{NumConsumers: 1}, "")
 	assert.NoError(t, qs.Shutdown(context.Background()))
 }
 
@@ -406,3 +414,264 @@ type mockHost struct {
 func (nh *mockHost) GetExtensions() map[component.ID]component.Component {
 	return nh.ext
 }
+
+func TestQueuedRetry_BytesSizedQueue(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 0 // to make every request go straight to the queue
+	qCfg.Sizer = exporterqueue.SizerTypeBytes
+	be, err := newBaseExporter(defaultSettings, defaultType, false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	td := testdata.GenerateTraces(2)
+	req := newTracesRequest(td, nil)
+	require.NoError(t, be.send(context.Background(), req))
+	assert.Equal(t, tracesMarshaler.TracesSize(td), be.queueSender.(*queueSender).queue.Size())
+}
+
+func TestQueuedRetry_BytesSizedRequestQueue(t *testing.T) {
+	qCfg := exporterqueue.NewDefaultConfig()
+	qCfg.Sizer = exporterqueue.SizerTypeBytes
+	_, err := newBaseExporter(defaultSettings, defaultType, true, nil, nil, newNoopObsrepSender,
+		WithRequestQueue(qCfg, exporterqueue.NewMemoryQueueFactory[Request]()))
+	assert.ErrorIs(t, err, errBytesSizerNotSupported)
+}
+
+func TestQueueSettings_ValidateSizer(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Sizer = exporterqueue.SizerTypeBytes
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.Sizer = "unknown"
+	assert.EqualError(t, qCfg.Validate(), `unsupported sizer type "unknown", must be one of "requests", "items" or "bytes"`)
+}
+
+func TestQueuedRetry_AdaptiveConcurrencyMetricReported(t *testing.T) {
+	tt, err := componenttest.SetupTelemetry(defaultID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 4
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MaxConsumers = 8
+	set := exporter.CreateSettings{ID: defaultID, TelemetrySettings: tt.TelemetrySettings(), BuildInfo: component.NewDefaultBuildInfo()}
+	be, err := newBaseExporter(set, defaultType, false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	require.NoError(t, tt.CheckExporterMetricGauge("exporter_queue_concurrency", int64(4)))
+
+	// A failed export halves the concurrency.
+	require.NoError(t, be.send(context.Background(), newErrorRequest()))
+	assert.Eventually(t, func() bool {
+		return be.queueSender.(*queueSender).concurrency() == 2
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, tt.CheckExporterMetricGauge("exporter_queue_concurrency", int64(2)))
+}
+
+func TestQueuedRetry_AdaptiveConcurrencyRecordsAttempts(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 4
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MaxConsumers = 8
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = 0
+	be, err := newBaseExporter(defaultSettings, defaultType, false, nil, nil, newObservabilityConsumerSender, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	ocs := be.obsrepSender.(*observabilityConsumerSender)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	// The failed attempt halves the concurrency, even though the request succeeds once retried.
+	mockR := newMockRequest(2, errors.New("transient error"))
+	ocs.run(func() {
+		require.NoError(t, be.send(context.Background(), mockR))
+	})
+	ocs.awaitAsyncProcessing()
+	mockR.checkNumRequests(t, 2)
+	assert.Equal(t, uint64(2), be.queueSender.(*queueSender).concurrency())
+}
+
+func TestQueueSettings_ValidateAdaptiveConcurrency(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.AdaptiveConcurrency.MaxConsumers = 0
+	assert.EqualError(t, qCfg.Validate(), "maximum number of consumers must not be less than the minimum")
+}
+
+func TestQueuedRetry_PartitionedQueue(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	set := exportertest.NewNopCreateSettings()
+	set.ID = defaultID
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 0 // to make every request go straight to the queue
+	qCfg.Partition.MetadataKeys = []string{"x-tenant"}
+	qCfg.Partition.PartitionQueueSize = 2
+	be, err := newBaseExporter(set, defaultType, false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, be.Shutdown(context.Background())) })
+
+	tenantCtx := func(tenant string) context.Context {
+		return client.NewContext(context.Background(), client.Info{
+			Metadata: client.NewMetadata(map[string][]string{"x-tenant": {tenant}}),
+		})
+	}
+	require.NoError(t, be.send(tenantCtx("a"), newMockRequest(1, nil)))
+	require.NoError(t, be.send(tenantCtx("a"), newMockRequest(1, nil)))
+	assert.ErrorIs(t, be.send(tenantCtx("a"), newMockRequest(1, nil)), queue.ErrQueueIsFull)
+	require.NoError(t, be.send(tenantCtx("b"), newMockRequest(1, nil)))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	sizes := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "exporter/queue_partition_size" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+				tenant, _ := dp.Attributes.Value("x-tenant")
+				exporterID, _ := dp.Attributes.Value(obsmetrics.ExporterKey)
+				assert.Equal(t, defaultID.String(), exporterID.AsString())
+				sizes[tenant.AsString()] = dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"a": 2, "b": 1}, sizes)
+}
+
+func TestQueueSettings_ValidatePartition(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Partition.MetadataKeys = []string{"x-tenant"}
+	assert.NoError(t, qCfg.Validate())
+
+	storageID := component.MustNewID("file_storage")
+	qCfg.StorageID = &storageID
+	assert.EqualError(t, qCfg.Validate(), "partitioning is not supported by the persistent queue")
+}
+
+func TestQueueSettings_ValidatePriority(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Priority.AttributeKey = "priority"
+	qCfg.Priority.Lanes = []exporterqueue.PriorityLaneConfig{{Name: "high", Values: []string{"high"}}, {Name: "low"}}
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.Partition.MetadataKeys = []string{"x-tenant"}
+	assert.EqualError(t, qCfg.Validate(), "partitioning cannot be used together with priority lanes")
+
+	qCfg.Partition = exporterqueue.NewDefaultPartitionConfig()
+	storageID := component.MustNewID("file_storage")
+	qCfg.StorageID = &storageID
+	assert.EqualError(t, qCfg.Validate(), "priority lanes are not supported by the persistent queue")
+}
+
+func TestAttributePriorityFunc(t *testing.T) {
+	priority := newAttributePriorityFunc(exporterqueue.PriorityConfig{
+		AttributeKey: "priority",
+		Lanes: []exporterqueue.PriorityLaneConfig{
+			{Name: "critical", Values: []string{"critical"}},
+			{Name: "high", Values: []string{"high", "error"}},
+			{Name: "bulk"},
+		},
+	})
+
+	traces := func(resourcePrio, scopePrio string) Request {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		if resourcePrio != "" {
+			rs.Resource().Attributes().PutStr("priority", resourcePrio)
+		}
+		ss := rs.ScopeSpans().AppendEmpty()
+		if scopePrio != "" {
+			ss.Scope().Attributes().PutStr("priority", scopePrio)
+		}
+		return newTracesRequest(td, nil)
+	}
+	assert.Equal(t, 0, priority(context.Background(), traces("critical", "")))
+	assert.Equal(t, 1, priority(context.Background(), traces("", "error")))
+	assert.Equal(t, 2, priority(context.Background(), traces("unknown", "")))
+	assert.Equal(t, 2, priority(context.Background(), traces("", "")))
+	// The highest priority found in the request is used.
+	assert.Equal(t, 0, priority(context.Background(), traces("high", "critical")))
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty()
+	md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr("priority", "high")
+	assert.Equal(t, 1, priority(context.Background(), newMetricsRequest(md, nil)))
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().Scope().Attributes().PutStr("priority", "critical")
+	assert.Equal(t, 0, priority(context.Background(), newLogsRequest(ld, nil)))
+
+	// The requests of the other types are put in the lowest priority lane.
+	assert.Equal(t, 2, priority(context.Background(), newMockRequest(1, nil)))
+}
+
+func TestQueuedRetry_PriorityQueue(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	set := exportertest.NewNopCreateSettings()
+	set.ID = defaultID
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.Priority.AttributeKey = "priority"
+	qCfg.Priority.Lanes = []exporterqueue.PriorityLaneConfig{{Name: "high", Values: []string{"high"}}, {Name: "low"}}
+	blocked := make(chan struct{})
+	firstExported := make(chan struct{})
+	var exported []string
+	pusher := func(_ context.Context, td ptrace.Traces) error {
+		if exported == nil {
+			close(firstExported)
+			<-blocked
+		}
+		prio, _ := td.ResourceSpans().At(0).Resource().Attributes().Get("priority")
+		exported = append(exported, prio.AsString())
+		return nil
+	}
+	be, err := newBaseExporter(set, defaultType, false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	traces := func(prio string) Request {
+		td := ptrace.NewTraces()
+		td.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("priority", prio)
+		return newTracesRequest(td, pusher)
+	}
+
+	// Block the only consumer, so the queue gets backed up.
+	require.NoError(t, be.send(context.Background(), traces("low")))
+	<-firstExported
+	require.NoError(t, be.send(context.Background(), traces("low")))
+	require.NoError(t, be.send(context.Background(), traces("low")))
+	require.NoError(t, be.send(context.Background(), traces("high")))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	sizes := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "exporter/queue_lane_size" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+				lane, _ := dp.Attributes.Value("lane")
+				sizes[lane.AsString()] = dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"high": 1, "low": 2}, sizes)
+
+	// The high priority request is exported before the backlog.
+	close(blocked)
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.Equal(t, []string{"low", "high", "low", "low"}, exported)
+}
@@ -5,6 +5,8 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 
 import (
 	"context"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 )
 
 // Request represents a single request that can be sent to an external endpoint.
@@ -32,6 +34,19 @@ type RequestErrorHandler interface {
 	OnError(error) Request
 }
 
+// RequestMergeSplitter is an optional interface that can be implemented by Request to support batching
+// in the exporter helper, see WithBatcher. Requests that don't implement it are sent without batching.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestMergeSplitter interface {
+	Request
+	// MergeSplit merges the optional other request into this one and splits the result into requests
+	// that contain no more than cfg.MaxSizeItems items each. If cfg.MaxSizeItems is zero, the result is not split.
+	// The other request can be nil, in which case only the splitting is applied.
+	// Both requests can be modified by the call, they must not be used after it.
+	MergeSplit(ctx context.Context, cfg exporterbatcher.MaxSizeConfig, other Request) ([]Request, error)
+}
+
 // RequestMarshaler is a function that can marshal a Request into bytes.
 // Deprecated: [v0.94.0] Use exporterqueue.Marshaler[Request] instead.
 type RequestMarshaler func(req Request) ([]byte, error)
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// RetryBudgetSettings defines configuration for limiting the ratio of retries to new requests across all the requests
+// sent by the exporter, so the retries cannot amplify the load on a recovering backend.
+type RetryBudgetSettings struct {
+	// MaxRetryRatio is the maximum ratio of retries to new requests sent during Window,
+	// e.g. 0.2 allows one retry per five new requests. If set to 0, the retries are not limited.
+	MaxRetryRatio float64 `mapstructure:"max_retry_ratio"`
+	// MinRetriesPerSecond is the number of retries per second allowed regardless of MaxRetryRatio,
+	// so an exporter sending few requests is still able to retry them.
+	MinRetriesPerSecond int `mapstructure:"min_retries_per_second"`
+	// Window is the duration the new requests and retries are counted over.
+	Window time.Duration `mapstructure:"window"`
+}
+
+// NewDefaultRetryBudgetSettings returns the default settings for RetryBudgetSettings.
+// The retry budget is disabled by default.
+func NewDefaultRetryBudgetSettings() RetryBudgetSettings {
+	return RetryBudgetSettings{
+		MaxRetryRatio:       0,
+		MinRetriesPerSecond: 10,
+		Window:              10 * time.Second,
+	}
+}
+
+// Validate checks if the RetryBudgetSettings configuration is valid.
+func (rbCfg *RetryBudgetSettings) Validate() error {
+	if rbCfg.MaxRetryRatio < 0 {
+		return errors.New("'max_retry_ratio' must be non-negative")
+	}
+	if rbCfg.MaxRetryRatio == 0 {
+		return nil
+	}
+	if rbCfg.MinRetriesPerSecond < 0 {
+		return errors.New("'min_retries_per_second' must be non-negative")
+	}
+	if rbCfg.Window <= 0 {
+		return errors.New("'window' must be positive")
+	}
+	return nil
+}
+
+// retryBudgetSlots is the number of slots the budget window is split into, the counts of the oldest slot
+// are dropped every Window/retryBudgetSlots.
+const retryBudgetSlots = 10
+
+// retryBudget limits the number of retries to a ratio of the new requests sent during a sliding window,
+// shared by all the requests of the exporter.
+type retryBudget struct {
+	ratio        float64
+	minRetries   float64
+	slotDuration time.Duration
+	now          func() time.Time
+
+	// mu guards everything declared below.
+	mu        sync.Mutex
+	requests  [retryBudgetSlots]int
+	retries   [retryBudgetSlots]int
+	slot      int
+	slotStart time.Time
+}
+
+// newRetryBudget returns a new retryBudget, or nil if the budget is disabled.
+func newRetryBudget(cfg RetryBudgetSettings) *retryBudget {
+	if cfg.MaxRetryRatio == 0 {
+		return nil
+	}
+	rb := &retryBudget{
+		ratio:        cfg.MaxRetryRatio,
+		minRetries:   float64(cfg.MinRetriesPerSecond) * cfg.Window.Seconds(),
+		slotDuration: cfg.Window / retryBudgetSlots,
+		now:          time.Now,
+	}
+	rb.slotStart = rb.now()
+	return rb
+}
+
+// recordRequest counts a new request sent by the exporter.
+func (rb *retryBudget) recordRequest() {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.advance()
+	rb.requests[rb.slot]++
+}
+
+// tryRetry counts a retry and returns true if it's allowed by the budget.
+func (rb *retryBudget) tryRetry() bool {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	rb.advance()
+	requests, retries := 0, 0
+	for i := 0; i < retryBudgetSlots; i++ {
+		requests += rb.requests[i]
+		retries += rb.retries[i]
+	}
+	if float64(retries+1) > rb.ratio*float64(requests)+rb.minRetries {
+		return false
+	}
+	rb.retries[rb.slot]++
+	return true
+}
+
+// advance moves the current slot to the current time, dropping the counts of the slots out of the window.
+// It must be called with mu held.
+func (rb *retryBudget) advance() {
+	n := int(rb.now().Sub(rb.slotStart) / rb.slotDuration)
+	if n == 0 {
+		return
+	}
+	for i := 0; i < min(n, retryBudgetSlots); i++ {
+		rb.slot = (rb.slot + 1) % retryBudgetSlots
+		rb.requests[rb.slot] = 0
+		rb.retries[rb.slot] = 0
+	}
+	rb.slotStart = rb.slotStart.Add(time.Duration(n) * rb.slotDuration)
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudget_Disabled(t *testing.T) {
+	assert.Nil(t, newRetryBudget(NewDefaultRetryBudgetSettings()))
+}
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Now()
+	rb := newRetryBudget(RetryBudgetSettings{
+		MaxRetryRatio:       0.5,
+		MinRetriesPerSecond: 0,
+		Window:              10 * time.Second,
+	})
+	require.NotNil(t, rb)
+	rb.now = func() time.Time { return now }
+	rb.slotStart = now
+
+	// No retries are allowed before the requests are sent.
+	assert.False(t, rb.tryRetry())
+
+	for i := 0; i < 4; i++ {
+		rb.recordRequest()
+	}
+	assert.True(t, rb.tryRetry())
+	assert.True(t, rb.tryRetry())
+	assert.False(t, rb.tryRetry())
+
+	// The requests sent later in the window add to the budget.
+	now = now.Add(5 * time.Second)
+	rb.recordRequest()
+	rb.recordRequest()
+	assert.True(t, rb.tryRetry())
+	assert.False(t, rb.tryRetry())
+
+	// The requests and retries out of the window are forgotten.
+	now = now.Add(6 * time.Second)
+	assert.False(t, rb.tryRetry())
+	rb.recordRequest()
+	rb.recordRequest()
+	assert.True(t, rb.tryRetry())
+
+	// Everything is forgotten after a long pause.
+	now = now.Add(time.Hour)
+	assert.False(t, rb.tryRetry())
+}
+
+func TestRetryBudget_MinRetries(t *testing.T) {
+	now := time.Now()
+	rb := newRetryBudget(RetryBudgetSettings{
+		MaxRetryRatio:       0.1,
+		MinRetriesPerSecond: 1,
+		Window:              2 * time.Second,
+	})
+	rb.now = func() time.Time { return now }
+	rb.slotStart = now
+
+	// An exporter sending few requests can still retry them.
+	rb.recordRequest()
+	assert.True(t, rb.tryRetry())
+	assert.True(t, rb.tryRetry())
+	assert.False(t, rb.tryRetry())
+}
+
+func TestRetryBudgetSettings_Validate(t *testing.T) {
+	cfg := NewDefaultRetryBudgetSettings()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MaxRetryRatio = -1
+	assert.EqualError(t, cfg.Validate(), "'max_retry_ratio' must be non-negative")
+
+	cfg.MaxRetryRatio = 0.2
+	assert.NoError(t, cfg.Validate())
+	cfg.MinRetriesPerSecond = -1
+	assert.EqualError(t, cfg.Validate(), "'min_retries_per_second' must be non-negative")
+
+	cfg = NewDefaultRetryBudgetSettings()
+	cfg.MaxRetryRatio = 0.2
+	cfg.Window = 0
+	assert.EqualError(t, cfg.Validate(), "'window' must be positive")
+
+	// The other settings are not validated when the budget is disabled.
+	cfg.MaxRetryRatio = 0
+	assert.NoError(t, cfg.Validate())
+}
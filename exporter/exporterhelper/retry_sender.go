@@ -47,8 +47,12 @@ type retrySender struct {
 	baseRequestSender
 	traceAttribute attribute.KeyValue
 	cfg            configretry.BackOffConfig
-	stopCh         chan struct{}
-	logger         *zap.Logger
+	// budget limits the retries across all the requests, nil if the retries are not limited.
+	budget *retryBudget
+	// recordAttempt records the result of every attempt in the adaptive concurrency of the queue, nil if unused.
+	recordAttempt func(start time.Time, err error)
+	stopCh        chan struct{}
+	logger        *zap.Logger
 }
 
 func newRetrySender(config configretry.BackOffConfig, set exporter.CreateSettings) *retrySender {
@@ -79,6 +83,9 @@ func (rs *retrySender) send(ctx context.Context, req Request) error {
 		Clock:               backoff.SystemClock,
 	}
 	expBackoff.Reset()
+	if rs.budget != nil {
+		rs.budget.recordRequest()
+	}
 	span := trace.SpanFromContext(ctx)
 	retryNum := int64(0)
 	for {
@@ -86,7 +93,11 @@ func (rs *retrySender) send(ctx context.Context, req Request) error {
 			"Sending request.",
 			trace.WithAttributes(rs.traceAttribute, attribute.Int64("retry_num", retryNum)))
 
+		start := time.Now()
 		err := rs.nextSender.send(ctx, req)
+		if rs.recordAttempt != nil {
+			rs.recordAttempt(start, err)
+		}
 		if err == nil {
 			return nil
 		}
@@ -100,14 +111,26 @@ func (rs *retrySender) send(ctx context.Context, req Request) error {
 
 		backoffDelay := expBackoff.NextBackOff()
 		if backoffDelay == backoff.Stop {
-			return fmt.Errorf("no more retries left: %w", err)
+			return experr.NewRetriesExhaustedErr(err)
 		}
 
 		throttleErr := throttleRetry{}
 		if errors.As(err, &throttleErr) {
+			// Don't wait for the delay requested by the server if the request cannot be retried after it anyway.
+			if rs.cfg.MaxElapsedTime != 0 && expBackoff.GetElapsedTime()+throttleErr.delay > rs.cfg.MaxElapsedTime {
+				return experr.NewRetriesExhaustedErr(err)
+			}
 			backoffDelay = max(backoffDelay, throttleErr.delay)
 		}
 
+		if deadline, ok := exportDeadline(ctx); ok && time.Now().Add(backoffDelay).After(deadline) {
+			return fmt.Errorf("%w, no more retries left: %w", errDeadlineExceeded, err)
+		}
+
+		if rs.budget != nil && !rs.budget.tryRetry() {
+			return experr.NewRetriesExhaustedErr(fmt.Errorf("retry budget exhausted: %w", err))
+		}
+
 		backoffDelayStr := backoffDelay.String()
 		span.AddEvent(
 			"Exporting failed. Will retry the request after interval.",
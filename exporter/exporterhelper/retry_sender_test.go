@@ -365,3 +365,67 @@ func (ocs *observabilityConsumerSender) checkSendItemsCount(t *testing.T, want i
 func (ocs *observabilityConsumerSender) checkDroppedItemsCount(t *testing.T, want int) {
 	assert.EqualValues(t, want, ocs.droppedItemsCount.Load())
 }
+
+func TestRetrySender_BudgetExhausted(t *testing.T) {
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = 0
+	rbCfg := NewDefaultRetryBudgetSettings()
+	rbCfg.MaxRetryRatio = 1
+	rbCfg.MinRetriesPerSecond = 0
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), defaultType, false, nil, nil, newNoopObsrepSender,
+		WithRetryBudget(rbCfg), WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// Every new request allows a single retry, so a request failing all the time is retried only
+	// until it uses its own budget and the budget of the request succeeded before.
+	require.NoError(t, be.send(context.Background(), newMockRequest(1, nil)))
+	assert.ErrorContains(t, be.send(context.Background(), newErrorRequest()), "retry budget exhausted")
+}
+
+func TestRetrySender_ThrottleBeyondMaxElapsedTime(t *testing.T) {
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = time.Minute
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), defaultType, false, nil, nil, newNoopObsrepSender, WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// The request is not held for the delay it cannot be retried after.
+	mockR := newMockRequest(1, NewThrottleRetry(errors.New("throttle error"), time.Hour))
+	start := time.Now()
+	require.ErrorContains(t, be.send(context.Background(), mockR), "no more retries left")
+	assert.Less(t, time.Since(start), time.Second)
+	mockR.checkNumRequests(t, 1)
+}
+
+func TestRetrySender_ExportDeadline(t *testing.T) {
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Second
+	rCfg.RandomizationFactor = 0
+	tCfg := NewDefaultTimeoutSettings()
+	tCfg.PropagateDeadline = true
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), defaultType, false, nil, nil, newNoopObsrepSender,
+		WithRetry(rCfg), WithTimeout(tCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// The request is not retried after the deadline of the incoming request.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	mockR := newMockRequest(1, errors.New("transient error"))
+	start := time.Now()
+	err = be.send(ctx, mockR)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	mockR.checkNumRequests(t, 1)
+}
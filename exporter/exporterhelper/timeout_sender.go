@@ -6,14 +6,28 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
 )
 
+// errDeadlineExceeded is returned when the data cannot be sent before the deadline of the incoming request.
+var errDeadlineExceeded = fmt.Errorf("request deadline exceeded: %w", context.DeadlineExceeded)
+
 // TimeoutSettings for timeout. The timeout applies to individual attempts to send data to the backend.
 type TimeoutSettings struct {
 	// Timeout is the timeout for every attempt to send data to the backend.
 	// A zero timeout means no timeout.
 	Timeout time.Duration `mapstructure:"timeout"`
+	// PropagateDeadline indicates whether the deadline of the incoming request, e.g. set by the gRPC client
+	// of the receiver, is applied to sending the data in addition to Timeout. The data that cannot be sent
+	// before the deadline is dropped, including the data waiting in the sending queue and the retries.
+	PropagateDeadline bool `mapstructure:"propagate_deadline"`
+	// DeadlineRatio is the fraction of the time left until the deadline of the incoming request given
+	// to sending the data, the rest is left for the response to get back to the client.
+	// It's used only if PropagateDeadline is true. Zero means the whole time.
+	DeadlineRatio float64 `mapstructure:"deadline_ratio"`
 }
 
 func (ts *TimeoutSettings) Validate() error {
@@ -21,6 +35,9 @@ func (ts *TimeoutSettings) Validate() error {
 	if ts.Timeout < 0 {
 		return errors.New("'timeout' must be non-negative")
 	}
+	if ts.DeadlineRatio < 0 || ts.DeadlineRatio > 1 {
+		return errors.New("'deadline_ratio' must be within [0, 1]")
+	}
 	return nil
 }
 
@@ -38,13 +55,49 @@ type timeoutSender struct {
 }
 
 func (ts *timeoutSender) send(ctx context.Context, req Request) error {
+	deadline, hasDeadline := exportDeadline(ctx)
+	if hasDeadline && !time.Now().Before(deadline) {
+		return consumererror.NewPermanent(errDeadlineExceeded)
+	}
 	// TODO: Remove this by avoiding to create the timeout sender if timeout is 0.
-	if ts.cfg.Timeout == 0 {
+	if ts.cfg.Timeout == 0 && !hasDeadline {
 		return req.Export(ctx)
 	}
 	// Intentionally don't overwrite the context inside the request, because in case of retries deadline will not be
 	// updated because this deadline most likely is before the next one.
-	tCtx, cancelFunc := context.WithTimeout(ctx, ts.cfg.Timeout)
-	defer cancelFunc()
+	tCtx := ctx
+	if ts.cfg.Timeout != 0 {
+		var cancelFunc context.CancelFunc
+		tCtx, cancelFunc = context.WithTimeout(tCtx, ts.cfg.Timeout)
+		defer cancelFunc()
+	}
+	if hasDeadline {
+		var cancelFunc context.CancelFunc
+		tCtx, cancelFunc = context.WithDeadline(tCtx, deadline)
+		defer cancelFunc()
+	}
 	return req.Export(tCtx)
 }
+
+type exportDeadlineKey struct{}
+
+// contextWithExportDeadline returns the context with the deadline of sending the request derived from the deadline
+// of the incoming request. The deadline is kept as a context value, so it's not lost when the queue sender removes
+// the cancellation from the context.
+func (ts *timeoutSender) contextWithExportDeadline(ctx context.Context) context.Context {
+	deadline, ok := ctx.Deadline()
+	if !ts.cfg.PropagateDeadline || !ok {
+		return ctx
+	}
+	if ts.cfg.DeadlineRatio > 0 && ts.cfg.DeadlineRatio < 1 {
+		now := time.Now()
+		deadline = now.Add(time.Duration(float64(deadline.Sub(now)) * ts.cfg.DeadlineRatio))
+	}
+	return context.WithValue(ctx, exportDeadlineKey{}, deadline)
+}
+
+// exportDeadline returns the deadline of sending the request set by contextWithExportDeadline.
+func exportDeadline(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(exportDeadlineKey{}).(time.Time)
+	return deadline, ok
+}
@@ -4,10 +4,22 @@
 package exporterhelper
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 func TestNewDefaultTimeoutSettings(t *testing.T) {
@@ -22,3 +34,124 @@ func TestInvalidTimeout(t *testing.T) {
 	cfg.Timeout = -1
 	assert.Error(t, cfg.Validate())
 }
+
+func TestInvalidDeadlineRatio(t *testing.T) {
+	cfg := NewDefaultTimeoutSettings()
+	cfg.PropagateDeadline = true
+	cfg.DeadlineRatio = 0.8
+	assert.NoError(t, cfg.Validate())
+	cfg.DeadlineRatio = -1
+	assert.EqualError(t, cfg.Validate(), "'deadline_ratio' must be within [0, 1]")
+	cfg.DeadlineRatio = 1.5
+	assert.EqualError(t, cfg.Validate(), "'deadline_ratio' must be within [0, 1]")
+}
+
+func TestContextWithExportDeadline(t *testing.T) {
+	deadline := time.Now().Add(10 * time.Second)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	// The deadline is not propagated by default.
+	ts := &timeoutSender{cfg: NewDefaultTimeoutSettings()}
+	_, ok := exportDeadline(ts.contextWithExportDeadline(ctx))
+	assert.False(t, ok)
+
+	ts.cfg.PropagateDeadline = true
+	got, ok := exportDeadline(ts.contextWithExportDeadline(ctx))
+	assert.True(t, ok)
+	assert.Equal(t, deadline, got)
+
+	// The deadline survives removing the cancellation from the context.
+	got, ok = exportDeadline(noCancellationContext{Context: ts.contextWithExportDeadline(ctx)})
+	assert.True(t, ok)
+	assert.Equal(t, deadline, got)
+
+	ts.cfg.DeadlineRatio = 0.5
+	got, ok = exportDeadline(ts.contextWithExportDeadline(ctx))
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(5*time.Second), got, time.Second)
+
+	// Nothing is propagated if the incoming request has no deadline.
+	_, ok = exportDeadline(ts.contextWithExportDeadline(context.Background()))
+	assert.False(t, ok)
+}
+
+func TestTimeoutSender_ExportDeadline(t *testing.T) {
+	ts := &timeoutSender{cfg: TimeoutSettings{Timeout: time.Minute}}
+
+	deadline := time.Now().Add(time.Second)
+	ctx := context.WithValue(context.Background(), exportDeadlineKey{}, deadline)
+	var exportCtx context.Context
+	require.NoError(t, ts.send(ctx, newTracesRequest(ptrace.NewTraces(), func(ctx context.Context, _ ptrace.Traces) error {
+		exportCtx = ctx
+		return nil
+	})))
+	got, ok := exportCtx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, got)
+
+	// The request is not exported after the deadline.
+	ctx = context.WithValue(context.Background(), exportDeadlineKey{}, time.Now().Add(-time.Second))
+	err := ts.send(ctx, newTracesRequest(ptrace.NewTraces(), func(context.Context, ptrace.Traces) error {
+		t.Fatal("the request must not be exported")
+		return nil
+	}))
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQueuedRetry_PropagateDeadline(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	set := exportertest.NewNopCreateSettings()
+	set.ID = defaultID
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	set.MetricsLevel = configtelemetry.LevelNormal
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	tCfg := NewDefaultTimeoutSettings()
+	tCfg.PropagateDeadline = true
+	be, err := newBaseExporter(set, component.DataTypeTraces, false, nil, nil,
+		newTracesExporterWithObservability, WithQueue(qCfg), WithTimeout(tCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Block the only consumer, so the request waits in the queue longer than its deadline.
+	blocked := make(chan struct{})
+	exporting := make(chan struct{})
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(1), func(context.Context, ptrace.Traces) error {
+		close(exporting)
+		<-blocked
+		return nil
+	})))
+	<-exporting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, be.send(ctx, newTracesRequest(testdata.GenerateTraces(2), func(context.Context, ptrace.Traces) error {
+		t.Fatal("the request must not be exported after the deadline")
+		return nil
+	})))
+	<-ctx.Done()
+	close(blocked)
+	// The timeouts of the export attempts are not counted as the requests dropped after the deadline.
+	require.NoError(t, be.send(context.Background(), newTracesRequest(testdata.GenerateTraces(3), func(context.Context, ptrace.Traces) error {
+		return context.DeadlineExceeded
+	})))
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range sum.DataPoints {
+					values[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	assert.Equal(t, int64(1), values["exporter/sent_spans"])
+	assert.Equal(t, int64(5), values["exporter/send_failed_spans"])
+	assert.Equal(t, int64(2), values["exporter/send_deadline_exceeded_spans"])
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// MergeSplit merges the other traces request into this one and splits the result by cfg.MaxSizeItems spans.
+func (req *tracesRequest) MergeSplit(_ context.Context, cfg exporterbatcher.MaxSizeConfig, other Request) ([]Request, error) {
+	if other != nil {
+		otherReq, ok := other.(*tracesRequest)
+		if !ok {
+			return nil, errInvalidBatchRequest
+		}
+		otherReq.td.ResourceSpans().MoveAndAppendTo(req.td.ResourceSpans())
+	}
+
+	if cfg.MaxSizeItems == 0 {
+		return []Request{req}, nil
+	}
+	var res []Request
+	for req.td.SpanCount() > cfg.MaxSizeItems {
+		res = append(res, newTracesRequest(splitTraces(cfg.MaxSizeItems, req.td), req.pusher))
+	}
+	return append(res, req), nil
+}
+
+// copyRequest returns a request with a copy of the traces, so the batcher can merge it without changing this one.
+func (req *tracesRequest) copyRequest() Request {
+	td := ptrace.NewTraces()
+	req.td.CopyTo(td)
+	return newTracesRequest(td, req.pusher)
+}
+
+// splitTraces removes spans from the input trace and returns a new trace of the specified size.
+func splitTraces(size int, src ptrace.Traces) ptrace.Traces {
+	if src.SpanCount() <= size {
+		return src
+	}
+	totalCopiedSpans := 0
+	dest := ptrace.NewTraces()
+
+	src.ResourceSpans().RemoveIf(func(srcRs ptrace.ResourceSpans) bool {
+		// If we are done skip everything else.
+		if totalCopiedSpans == size {
+			return false
+		}
+
+		// If it fully fits
+		srcRsSC := resourceSC(srcRs)
+		if (totalCopiedSpans + srcRsSC) <= size {
+			totalCopiedSpans += srcRsSC
+			srcRs.MoveTo(dest.ResourceSpans().AppendEmpty())
+			return true
+		}
+
+		destRs := dest.ResourceSpans().AppendEmpty()
+		srcRs.Resource().CopyTo(destRs.Resource())
+		srcRs.ScopeSpans().RemoveIf(func(srcIls ptrace.ScopeSpans) bool {
+			// If we are done skip everything else.
+			if totalCopiedSpans == size {
+				return false
+			}
+
+			// If possible to move all spans do that.
+			srcIlsSC := srcIls.Spans().Len()
+			if size-totalCopiedSpans >= srcIlsSC {
+				totalCopiedSpans += srcIlsSC
+				srcIls.MoveTo(destRs.ScopeSpans().AppendEmpty())
+				return true
+			}
+
+			destIls := destRs.ScopeSpans().AppendEmpty()
+			srcIls.Scope().CopyTo(destIls.Scope())
+			srcIls.Spans().RemoveIf(func(srcSpan ptrace.Span) bool {
+				// If we are done skip everything else.
+				if totalCopiedSpans == size {
+					return false
+				}
+				srcSpan.MoveTo(destIls.Spans().AppendEmpty())
+				totalCopiedSpans++
+				return true
+			})
+			return false
+		})
+		return srcRs.ScopeSpans().Len() == 0
+	})
+
+	return dest
+}
+
+// resourceSC calculates the total number of spans in the ptrace.ResourceSpans.
+func resourceSC(rs ptrace.ResourceSpans) (count int) {
+	for k := 0; k < rs.ScopeSpans().Len(); k++ {
+		count += rs.ScopeSpans().At(k).Spans().Len()
+	}
+	return
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestMergeSplitTraces(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      exporterbatcher.MaxSizeConfig
+		tr1      Request
+		tr2      Request
+		expected []int
+	}{
+		{
+			name:     "merge_only",
+			cfg:      exporterbatcher.MaxSizeConfig{},
+			tr1:      newTracesRequest(testdata.GenerateTraces(4), nil),
+			tr2:      newTracesRequest(testdata.GenerateTraces(6), nil),
+			expected: []int{10},
+		},
+		{
+			name:     "split_only",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 4},
+			tr1:      newTracesRequest(testdata.GenerateTraces(10), nil),
+			expected: []int{4, 4, 2},
+		},
+		{
+			name:     "merge_and_split",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 5},
+			tr1:      newTracesRequest(testdata.GenerateTraces(4), nil),
+			tr2:      newTracesRequest(testdata.GenerateTraces(7), nil),
+			expected: []int{5, 5, 1},
+		},
+		{
+			name:     "fits_max_size",
+			cfg:      exporterbatcher.MaxSizeConfig{MaxSizeItems: 10},
+			tr1:      newTracesRequest(testdata.GenerateTraces(3), nil),
+			tr2:      newTracesRequest(testdata.GenerateTraces(7), nil),
+			expected: []int{10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.tr1.(RequestMergeSplitter).MergeSplit(context.Background(), tt.cfg, tt.tr2)
+			require.NoError(t, err)
+			sizes := make([]int, 0, len(res))
+			for _, r := range res {
+				sizes = append(sizes, r.ItemsCount())
+			}
+			assert.Equal(t, tt.expected, sizes)
+		})
+	}
+}
+
+func TestMergeSplitTracesInvalidInput(t *testing.T) {
+	tr := newTracesRequest(testdata.GenerateTraces(2), nil).(RequestMergeSplitter)
+	_, err := tr.MergeSplit(context.Background(), exporterbatcher.MaxSizeConfig{}, newLogsRequest(testdata.GenerateLogs(2), nil))
+	assert.ErrorIs(t, err, errInvalidBatchRequest)
+}
+
+func TestSplitTraces(t *testing.T) {
+	td := testdata.GenerateTraces(20)
+	split := splitTraces(15, td)
+	assert.Equal(t, 15, split.SpanCount())
+	assert.Equal(t, 5, td.SpanCount())
+
+	// The data that fits the size is returned as is.
+	assert.Equal(t, td, splitTraces(5, td))
+}
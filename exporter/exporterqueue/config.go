@@ -5,8 +5,12 @@ package exporterqueue // import "go.opentelemetry.io/collector/exporter/exporter
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
 )
 
 // Config defines configuration for queueing requests before exporting.
@@ -18,8 +22,243 @@ type Config struct {
 	Enabled bool `mapstructure:"enabled"`
 	// NumConsumers is the number of consumers from the queue.
 	NumConsumers int `mapstructure:"num_consumers"`
-	// QueueSize is the maximum number of requests allowed in queue at any given time.
+	// QueueSize is the maximum size of the queue at any given time, measured in units defined by Sizer.
 	QueueSize int `mapstructure:"queue_size"`
+	// Sizer defines how the queue size is measured: as the number of requests, the number of items
+	// (spans, metric data points or log records) or the number of bytes in the serialized requests.
+	Sizer SizerType `mapstructure:"sizer"`
+	// AdaptiveConcurrency enables adjusting the number of consumers exporting at the same time.
+	// When enabled, NumConsumers is the initial number of consumers.
+	AdaptiveConcurrency AdaptiveConcurrencyConfig `mapstructure:"adaptive_concurrency"`
+	// Partition enables keeping the requests from different clients in separate partitions of the queue.
+	// It's supported by the memory queue only.
+	Partition PartitionConfig `mapstructure:"partition"`
+	// Priority enables keeping the requests of different priorities in separate lanes of the queue.
+	// It's supported by the memory queue only, and cannot be used together with Partition.
+	Priority PriorityConfig `mapstructure:"priority"`
+}
+
+// AdaptiveConcurrencyConfig defines configuration for adjusting the number of queue consumers exporting at the
+// same time: the number is increased while the exports succeed, and is cut in half when an export fails
+// or takes longer than LatencyThreshold.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type AdaptiveConcurrencyConfig struct {
+	// Enabled indicates whether to adjust the number of consumers exporting at the same time.
+	Enabled bool `mapstructure:"enabled"`
+	// MinConsumers is the lower bound of the number of consumers exporting at the same time.
+	MinConsumers int `mapstructure:"min_consumers"`
+	// MaxConsumers is the upper bound of the number of consumers exporting at the same time.
+	MaxConsumers int `mapstructure:"max_consumers"`
+	// LatencyThreshold is the duration of an export attempt above which the number of consumers is decreased
+	// as if the export failed. Zero means that only the export errors are taken into account.
+	LatencyThreshold time.Duration `mapstructure:"latency_threshold"`
+}
+
+// PartitionConfig defines configuration for partitioning the queue by the client metadata, so a single client
+// filling up its partition doesn't prevent the requests from the other clients from being queued and exported.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PartitionConfig struct {
+	// MetadataKeys is a list of client.Metadata keys that will be used to form distinct partitions.
+	// If this setting is empty, the queue is not partitioned. Entries are case-insensitive.
+	MetadataKeys []string `mapstructure:"metadata_keys"`
+	// MetadataCardinalityLimit indicates the maximum number of partitions that will be created through
+	// a distinct combination of MetadataKeys. The requests of new clients are rejected after that.
+	// The partitions are removed once emptied, so only the partitions with queued requests are counted.
+	MetadataCardinalityLimit uint32 `mapstructure:"metadata_cardinality_limit"`
+	// PartitionQueueSize is the maximum size of every partition, measured in units defined by Sizer.
+	// Zero means that the partitions are limited by the size of the whole queue only.
+	PartitionQueueSize int `mapstructure:"partition_queue_size"`
+	// Weights defines the number of requests consumed from the matching partitions before moving to the next one.
+	// Partitions not matching any of the weights have weight 1, so they are consumed in the round-robin order.
+	Weights []PartitionWeight `mapstructure:"weights"`
+}
+
+// PartitionWeight defines the weight of the partitions matching all the given metadata values.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PartitionWeight struct {
+	// Metadata is the values of the metadata keys the partition must have.
+	Metadata map[string]string `mapstructure:"metadata"`
+	// Weight is the number of requests consumed from the partition in a row.
+	Weight int `mapstructure:"weight"`
+}
+
+// Validate checks if the PartitionConfig configuration is valid
+func (pCfg *PartitionConfig) Validate() error {
+	uniq := map[string]bool{}
+	for _, k := range pCfg.MetadataKeys {
+		l := strings.ToLower(k)
+		if _, has := uniq[l]; has {
+			return fmt.Errorf("duplicate entry in metadata_keys: %q (case-insensitive)", l)
+		}
+		uniq[l] = true
+	}
+	if pCfg.PartitionQueueSize < 0 {
+		return errors.New("partition queue size must not be negative")
+	}
+	for _, w := range pCfg.Weights {
+		if w.Weight <= 0 {
+			return errors.New("partition weight must be positive")
+		}
+		for k := range w.Metadata {
+			if !uniq[strings.ToLower(k)] {
+				return fmt.Errorf("partition weight metadata key %q is not in metadata_keys", k)
+			}
+		}
+	}
+	return nil
+}
+
+// PriorityMode defines the order the priority lanes are consumed in.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PriorityMode string
+
+const (
+	// PriorityModeStrict consumes a lane only when all the higher priority lanes are empty.
+	PriorityModeStrict PriorityMode = "strict"
+	// PriorityModeWeighted consumes the lanes in the weighted round-robin order, so the lower priority
+	// lanes are not starved.
+	PriorityModeWeighted PriorityMode = "weighted"
+)
+
+// PriorityConfig defines configuration for keeping the requests of different priorities in separate lanes,
+// so the high priority requests are exported first when the queue is backed up by the bulk data.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PriorityConfig struct {
+	// Mode defines the order the lanes are consumed in: "strict" (the default) or "weighted".
+	Mode PriorityMode `mapstructure:"mode"`
+	// AttributeKey is the resource or scope attribute used to select the lane of the request.
+	// It's used by the exporters created with WithQueue, the request exporters provide the lane selection
+	// function to NewPriorityMemoryQueueFactory instead.
+	AttributeKey string `mapstructure:"attribute_key"`
+	// Lanes is the list of lanes ordered from the highest priority to the lowest. The requests not matching
+	// any lane are put in the last one. If this setting is empty, the queue has no priority lanes.
+	Lanes []PriorityLaneConfig `mapstructure:"lanes"`
+}
+
+// PriorityLaneConfig defines a lane of the priority queue.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PriorityLaneConfig struct {
+	// Name is the name of the lane reported in the telemetry.
+	Name string `mapstructure:"name"`
+	// Values is the list of AttributeKey values selecting the lane.
+	Values []string `mapstructure:"values"`
+	// Weight is the number of requests consumed from the lane in a row in the weighted mode.
+	// Zero is treated as 1.
+	Weight int `mapstructure:"weight"`
+	// QueueSize is the maximum size of the lane, measured in units defined by Sizer.
+	// Zero means that the lane is limited by the size of the whole queue only.
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// Validate checks if the PriorityConfig configuration is valid
+func (pCfg *PriorityConfig) Validate() error {
+	if len(pCfg.Lanes) == 0 {
+		return nil
+	}
+	switch pCfg.Mode {
+	case "", PriorityModeStrict, PriorityModeWeighted:
+	default:
+		return fmt.Errorf("unsupported priority mode %q, must be one of %q or %q",
+			pCfg.Mode, PriorityModeStrict, PriorityModeWeighted)
+	}
+	names := map[string]bool{}
+	for _, l := range pCfg.Lanes {
+		if l.Name == "" {
+			return errors.New("priority lane name must not be empty")
+		}
+		if names[l.Name] {
+			return fmt.Errorf("duplicate priority lane name %q", l.Name)
+		}
+		names[l.Name] = true
+		if l.Weight < 0 {
+			return errors.New("priority lane weight must not be negative")
+		}
+		if l.QueueSize < 0 {
+			return errors.New("priority lane queue size must not be negative")
+		}
+		if len(l.Values) > 0 && pCfg.AttributeKey == "" {
+			return errors.New("priority lane values require attribute_key to be set")
+		}
+	}
+	return nil
+}
+
+// NewDefaultPriorityConfig returns the default PriorityConfig.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewDefaultPriorityConfig() PriorityConfig {
+	return PriorityConfig{
+		Mode: PriorityModeStrict,
+	}
+}
+
+// NewDefaultPartitionConfig returns the default PartitionConfig.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewDefaultPartitionConfig() PartitionConfig {
+	return PartitionConfig{
+		MetadataCardinalityLimit: 1000,
+	}
+}
+
+// NewDefaultAdaptiveConcurrencyConfig returns the default AdaptiveConcurrencyConfig.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewDefaultAdaptiveConcurrencyConfig() AdaptiveConcurrencyConfig {
+	return AdaptiveConcurrencyConfig{
+		Enabled:      false,
+		MinConsumers: 1,
+		MaxConsumers: 100,
+	}
+}
+
+// Validate checks if the AdaptiveConcurrencyConfig configuration is valid
+func (acCfg *AdaptiveConcurrencyConfig) Validate() error {
+	if !acCfg.Enabled {
+		return nil
+	}
+	if acCfg.MinConsumers <= 0 {
+		return errors.New("minimum number of consumers must be positive")
+	}
+	if acCfg.MaxConsumers < acCfg.MinConsumers {
+		return errors.New("maximum number of consumers must not be less than the minimum")
+	}
+	if acCfg.LatencyThreshold < 0 {
+		return errors.New("latency threshold must not be negative")
+	}
+	return nil
+}
+
+// SizerType defines the unit used to measure the queue size.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type SizerType string
+
+const (
+	// SizerTypeRequests measures the queue size as the number of requests.
+	SizerTypeRequests SizerType = "requests"
+	// SizerTypeItems measures the queue size as the number of items in the requests.
+	SizerTypeItems SizerType = "items"
+	// SizerTypeBytes measures the queue size as the number of bytes in the serialized requests.
+	// Requests must implement the BytesSize() int method to be sized in bytes.
+	SizerTypeBytes SizerType = "bytes"
+)
+
+// Validate checks if the SizerType is one of the supported values.
+// An empty value is accepted and is interpreted as SizerTypeRequests.
+func (st SizerType) Validate() error {
+	switch st {
+	case "", SizerTypeRequests, SizerTypeItems, SizerTypeBytes:
+		return nil
+	}
+	return fmt.Errorf("unsupported sizer type %q, must be one of %q, %q or %q",
+		st, SizerTypeRequests, SizerTypeItems, SizerTypeBytes)
 }
 
 // NewDefaultConfig returns the default Config.
@@ -27,9 +266,13 @@ type Config struct {
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 func NewDefaultConfig() Config {
 	return Config{
-		Enabled:      true,
-		NumConsumers: 10,
-		QueueSize:    1_000,
+		Enabled:             true,
+		NumConsumers:        10,
+		QueueSize:           1_000,
+		Sizer:               SizerTypeRequests,
+		AdaptiveConcurrency: NewDefaultAdaptiveConcurrencyConfig(),
+		Partition:           NewDefaultPartitionConfig(),
+		Priority:            NewDefaultPriorityConfig(),
 	}
 }
 
@@ -44,7 +287,19 @@ func (qCfg *Config) Validate() error {
 	if qCfg.QueueSize <= 0 {
 		return errors.New("queue size must be positive")
 	}
-	return nil
+	if err := qCfg.Sizer.Validate(); err != nil {
+		return err
+	}
+	if err := qCfg.AdaptiveConcurrency.Validate(); err != nil {
+		return err
+	}
+	if err := qCfg.Partition.Validate(); err != nil {
+		return err
+	}
+	if len(qCfg.Partition.MetadataKeys) > 0 && len(qCfg.Priority.Lanes) > 0 {
+		return experr.ErrPartitionedPriorityQueue
+	}
+	return qCfg.Priority.Validate()
 }
 
 // PersistentQueueConfig defines configuration for queueing requests in a persistent storage.
@@ -59,3 +314,17 @@ type PersistentQueueConfig struct {
 	// as a storage extension for the persistent queue
 	StorageID *component.ID `mapstructure:"storage"`
 }
+
+// Validate checks if the PersistentQueueConfig configuration is valid
+func (pqCfg *PersistentQueueConfig) Validate() error {
+	if err := pqCfg.Config.Validate(); err != nil {
+		return err
+	}
+	if pqCfg.Enabled && pqCfg.StorageID != nil && len(pqCfg.Partition.MetadataKeys) > 0 {
+		return experr.ErrPartitionedPersistentQueue
+	}
+	if pqCfg.Enabled && pqCfg.StorageID != nil && len(pqCfg.Priority.Lanes) > 0 {
+		return experr.ErrPriorityPersistentQueue
+	}
+	return nil
+}
@@ -5,8 +5,11 @@ package exporterqueue
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/component"
 )
 
 func TestQueueConfig_Validate(t *testing.T) {
@@ -24,3 +27,112 @@ func TestQueueConfig_Validate(t *testing.T) {
 	qCfg.Enabled = false
 	assert.NoError(t, qCfg.Validate())
 }
+
+func TestQueueConfig_ValidateSizer(t *testing.T) {
+	qCfg := NewDefaultConfig()
+	for _, st := range []SizerType{"", SizerTypeRequests, SizerTypeItems, SizerTypeBytes} {
+		qCfg.Sizer = st
+		assert.NoError(t, qCfg.Validate())
+	}
+
+	qCfg.Sizer = "megabytes"
+	assert.EqualError(t, qCfg.Validate(), `unsupported sizer type "megabytes", must be one of "requests", "items" or "bytes"`)
+}
+
+func TestQueueConfig_ValidateAdaptiveConcurrency(t *testing.T) {
+	qCfg := NewDefaultConfig()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.AdaptiveConcurrency.MinConsumers = 0
+	assert.EqualError(t, qCfg.Validate(), "minimum number of consumers must be positive")
+
+	qCfg.AdaptiveConcurrency = NewDefaultAdaptiveConcurrencyConfig()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MaxConsumers = 0
+	assert.EqualError(t, qCfg.Validate(), "maximum number of consumers must not be less than the minimum")
+
+	qCfg.AdaptiveConcurrency = NewDefaultAdaptiveConcurrencyConfig()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.LatencyThreshold = -time.Second
+	assert.EqualError(t, qCfg.Validate(), "latency threshold must not be negative")
+
+	// The bounds are not validated when the adaptive concurrency is disabled.
+	qCfg.AdaptiveConcurrency.Enabled = false
+	assert.NoError(t, qCfg.Validate())
+}
+
+func TestQueueConfig_ValidatePartition(t *testing.T) {
+	qCfg := NewDefaultConfig()
+	qCfg.Partition.MetadataKeys = []string{"X-Tenant"}
+	qCfg.Partition.Weights = []PartitionWeight{{Metadata: map[string]string{"x-tenant": "a"}, Weight: 2}}
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.Partition.Weights[0].Weight = 0
+	assert.EqualError(t, qCfg.Validate(), "partition weight must be positive")
+
+	qCfg.Partition.Weights = []PartitionWeight{{Metadata: map[string]string{"x-other": "a"}, Weight: 1}}
+	assert.EqualError(t, qCfg.Validate(), `partition weight metadata key "x-other" is not in metadata_keys`)
+
+	qCfg.Partition = NewDefaultPartitionConfig()
+	qCfg.Partition.MetadataKeys = []string{"X-Tenant", "x-tenant"}
+	assert.EqualError(t, qCfg.Validate(), `duplicate entry in metadata_keys: "x-tenant" (case-insensitive)`)
+
+	qCfg.Partition = NewDefaultPartitionConfig()
+	qCfg.Partition.PartitionQueueSize = -1
+	assert.EqualError(t, qCfg.Validate(), "partition queue size must not be negative")
+}
+
+func TestPersistentQueueConfig_ValidatePartition(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	pqCfg := PersistentQueueConfig{Config: NewDefaultConfig(), StorageID: &storageID}
+	assert.NoError(t, pqCfg.Validate())
+
+	pqCfg.Partition.MetadataKeys = []string{"x-tenant"}
+	assert.EqualError(t, pqCfg.Validate(), "partitioning is not supported by the persistent queue")
+
+	pqCfg.StorageID = nil
+	assert.NoError(t, pqCfg.Validate())
+}
+
+func TestQueueConfig_ValidatePriority(t *testing.T) {
+	qCfg := NewDefaultConfig()
+	qCfg.Priority.AttributeKey = "priority"
+	qCfg.Priority.Lanes = []PriorityLaneConfig{{Name: "high", Values: []string{"high"}}, {Name: "low"}}
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.Priority.Mode = "random"
+	assert.EqualError(t, qCfg.Validate(), `unsupported priority mode "random", must be one of "strict" or "weighted"`)
+
+	qCfg.Priority.Mode = PriorityModeWeighted
+	qCfg.Priority.Lanes[0].Weight = -1
+	assert.EqualError(t, qCfg.Validate(), "priority lane weight must not be negative")
+
+	qCfg.Priority.Lanes[0].Weight = 2
+	qCfg.Priority.Lanes[1].QueueSize = -1
+	assert.EqualError(t, qCfg.Validate(), "priority lane queue size must not be negative")
+
+	qCfg.Priority.Lanes[1] = PriorityLaneConfig{Name: "high"}
+	assert.EqualError(t, qCfg.Validate(), `duplicate priority lane name "high"`)
+
+	qCfg.Priority.Lanes[1] = PriorityLaneConfig{}
+	assert.EqualError(t, qCfg.Validate(), "priority lane name must not be empty")
+
+	qCfg.Priority.Lanes[1] = PriorityLaneConfig{Name: "low"}
+	qCfg.Priority.AttributeKey = ""
+	assert.EqualError(t, qCfg.Validate(), "priority lane values require attribute_key to be set")
+
+	qCfg.Priority.AttributeKey = "priority"
+	qCfg.Partition.MetadataKeys = []string{"x-tenant"}
+	assert.EqualError(t, qCfg.Validate(), "partitioning cannot be used together with priority lanes")
+}
+
+func TestPersistentQueueConfig_ValidatePriority(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	pqCfg := PersistentQueueConfig{Config: NewDefaultConfig(), StorageID: &storageID}
+	pqCfg.Priority.Lanes = []PriorityLaneConfig{{Name: "high"}, {Name: "low"}}
+	assert.EqualError(t, pqCfg.Validate(), "priority lanes are not supported by the persistent queue")
+
+	pqCfg.StorageID = nil
+	assert.NoError(t, pqCfg.Validate())
+}
@@ -39,10 +39,14 @@ type Unmarshaler[T any] func([]byte) (T, error)
 type Factory[T any] func(context.Context, Settings, Config) Queue[T]
 
 // NewMemoryQueueFactory returns a factory to create a new memory queue.
+// The queue is partitioned by the client metadata if cfg.Partition.MetadataKeys is set.
 // This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 func NewMemoryQueueFactory[T itemsCounter]() Factory[T] {
 	return func(_ context.Context, _ Settings, cfg Config) Queue[T] {
+		if len(cfg.Partition.MetadataKeys) > 0 {
+			return newPartitionedMemoryQueue[T](cfg)
+		}
 		return queue.NewBoundedMemoryQueue[T](queue.MemoryQueueSettings[T]{
 			Sizer:    sizerFromConfig[T](cfg),
 			Capacity: capacityFromConfig(cfg),
@@ -50,6 +54,52 @@ func NewMemoryQueueFactory[T itemsCounter]() Factory[T] {
 	}
 }
 
+func newPartitionedMemoryQueue[T itemsCounter](cfg Config) Queue[T] {
+	weights := make([]queue.PartitionWeight, 0, len(cfg.Partition.Weights))
+	for _, w := range cfg.Partition.Weights {
+		weights = append(weights, queue.PartitionWeight{Metadata: w.Metadata, Weight: w.Weight})
+	}
+	return queue.NewPartitionedMemoryQueue[T](queue.PartitionedMemoryQueueSettings[T]{
+		Sizer:                    sizerFromConfig[T](cfg),
+		Capacity:                 capacityFromConfig(cfg),
+		MetadataKeys:             cfg.Partition.MetadataKeys,
+		MetadataCardinalityLimit: int(cfg.Partition.MetadataCardinalityLimit),
+		PartitionCapacity:        cfg.Partition.PartitionQueueSize,
+		Weights:                  weights,
+	})
+}
+
+// PriorityFunc returns the index of the priority lane from cfg.Priority.Lanes the request is put in.
+// The requests with the index out of the lanes range are put in the lowest priority lane.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PriorityFunc[T any] func(context.Context, T) int
+
+// NewPriorityMemoryQueueFactory returns a factory to create a new memory queue with the priority lanes
+// defined by cfg.Priority.Lanes, the lane of every request is selected by the given function.
+// If cfg.Priority.Lanes is empty, it creates the same queue as NewMemoryQueueFactory.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewPriorityMemoryQueueFactory[T itemsCounter](priority PriorityFunc[T]) Factory[T] {
+	memoryQueueFactory := NewMemoryQueueFactory[T]()
+	return func(ctx context.Context, set Settings, cfg Config) Queue[T] {
+		if len(cfg.Priority.Lanes) == 0 {
+			return memoryQueueFactory(ctx, set, cfg)
+		}
+		lanes := make([]queue.PriorityLane, 0, len(cfg.Priority.Lanes))
+		for _, l := range cfg.Priority.Lanes {
+			lanes = append(lanes, queue.PriorityLane{Name: l.Name, Weight: l.Weight, Capacity: l.QueueSize})
+		}
+		return queue.NewPriorityMemoryQueue[T](queue.PriorityMemoryQueueSettings[T]{
+			Sizer:    sizerFromConfig[T](cfg),
+			Capacity: capacityFromConfig(cfg),
+			Lanes:    lanes,
+			Strict:   cfg.Priority.Mode != PriorityModeWeighted,
+			Priority: priority,
+		})
+	}
+}
+
 // PersistentQueueSettings defines developer settings for the persistent queue factory.
 // This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
@@ -62,6 +112,7 @@ type PersistentQueueSettings[T any] struct {
 
 // NewPersistentQueueFactory returns a factory to create a new persistent queue.
 // If cfg.StorageID is nil then it falls back to memory queue.
+// The persistent queue has no partitions and priority lanes, cfg.Partition and cfg.Priority are ignored.
 // This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 func NewPersistentQueueFactory[T itemsCounter](storageID *component.ID, factorySettings PersistentQueueSettings[T]) Factory[T] {
@@ -85,12 +136,17 @@ type itemsCounter interface {
 	ItemsCount() int
 }
 
-func sizerFromConfig[T itemsCounter](Config) queue.Sizer[T] {
-	// TODO: Handle other ways to measure the queue size once they are added.
-	return &queue.RequestSizer[T]{}
+func sizerFromConfig[T itemsCounter](cfg Config) queue.Sizer[T] {
+	switch cfg.Sizer {
+	case SizerTypeItems:
+		return &queue.ItemsSizer[T]{}
+	case SizerTypeBytes:
+		return &queue.BytesSizer[T]{}
+	default:
+		return &queue.RequestSizer[T]{}
+	}
 }
 
 func capacityFromConfig(cfg Config) int {
-	// TODO: Handle other ways to measure the queue size once they are added.
 	return cfg.QueueSize
 }
@@ -5,6 +5,13 @@ package experr // import "go.opentelemetry.io/collector/exporter/internal/experr
 
 import "errors"
 
+// The errors of the queue configurations shared by exporterhelper.QueueSettings and exporterqueue.Config.
+var (
+	ErrPartitionedPersistentQueue = errors.New("partitioning is not supported by the persistent queue")
+	ErrPriorityPersistentQueue    = errors.New("priority lanes are not supported by the persistent queue")
+	ErrPartitionedPriorityQueue   = errors.New("partitioning cannot be used together with priority lanes")
+)
+
 type shutdownErr struct {
 	err error
 }
@@ -25,3 +32,25 @@ func IsShutdownErr(err error) bool {
 	var sdErr shutdownErr
 	return errors.As(err, &sdErr)
 }
+
+type retriesExhaustedErr struct {
+	err error
+}
+
+// NewRetriesExhaustedErr wraps the last error returned for a request that cannot be retried anymore.
+func NewRetriesExhaustedErr(err error) error {
+	return retriesExhaustedErr{err: err}
+}
+
+func (r retriesExhaustedErr) Error() string {
+	return "no more retries left: " + r.err.Error()
+}
+
+func (r retriesExhaustedErr) Unwrap() error {
+	return r.err
+}
+
+func IsRetriesExhaustedErr(err error) bool {
+	var reErr retriesExhaustedErr
+	return errors.As(err, &reErr)
+}
@@ -7,6 +7,7 @@ package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 
 import (
 	"context"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 )
@@ -14,10 +15,17 @@ import (
 // boundedMemoryQueue implements a producer-consumer exchange similar to a ring buffer queue,
 // where the queue is bounded and if it fills up due to slow consumers, the new items written by
 // the producer are dropped.
+// The elements are kept in a slice guarded by a mutex instead of a buffered channel, so the memory
+// is not preallocated for the whole capacity, which can be large if the queue is sized in items or bytes.
 type boundedMemoryQueue[T any] struct {
 	component.StartFunc
 	*queueCapacityLimiter[T]
-	items chan queueRequest[T]
+
+	// mu guards everything declared below.
+	mu          sync.Mutex
+	hasElements *sync.Cond
+	items       []queueRequest[T]
+	stopped     bool
 }
 
 // MemoryQueueSettings defines internal parameters for boundedMemoryQueue creation.
@@ -29,18 +37,23 @@ type MemoryQueueSettings[T any] struct {
 // NewBoundedMemoryQueue constructs the new queue of specified capacity, and with an optional
 // callback for dropped items (e.g. useful to emit metrics).
 func NewBoundedMemoryQueue[T any](set MemoryQueueSettings[T]) Queue[T] {
-	return &boundedMemoryQueue[T]{
+	q := &boundedMemoryQueue[T]{
 		queueCapacityLimiter: newQueueCapacityLimiter[T](set.Sizer, set.Capacity),
-		items:                make(chan queueRequest[T], set.Capacity),
 	}
+	q.hasElements = sync.NewCond(&q.mu)
+	return q
 }
 
-// Offer is used by the producer to submit new item to the queue. Calling this method on a stopped queue will panic.
+// Offer is used by the producer to submit new item to the queue.
 func (q *boundedMemoryQueue[T]) Offer(ctx context.Context, req T) error {
-	if !q.queueCapacityLimiter.claim(req) {
+	size := q.queueCapacityLimiter.sizeOf(req)
+	if !q.queueCapacityLimiter.claim(size) {
 		return ErrQueueIsFull
 	}
-	q.items <- queueRequest[T]{ctx: ctx, req: req}
+	q.mu.Lock()
+	q.items = append(q.items, queueRequest[T]{ctx: ctx, req: req, size: size})
+	q.mu.Unlock()
+	q.hasElements.Signal()
 	return nil
 }
 
@@ -48,23 +61,39 @@ func (q *boundedMemoryQueue[T]) Offer(ctx context.Context, req T) error {
 // The call blocks until there is an item available or the queue is stopped.
 // The function returns true when an item is consumed or false if the queue is stopped and emptied.
 func (q *boundedMemoryQueue[T]) Consume(consumeFunc func(context.Context, T) error) bool {
-	item, ok := <-q.items
-	if !ok {
+	q.mu.Lock()
+	for len(q.items) == 0 && !q.stopped {
+		q.hasElements.Wait()
+	}
+	if len(q.items) == 0 {
+		q.mu.Unlock()
 		return false
 	}
-	q.queueCapacityLimiter.release(item.req)
+	item := q.items[0]
+	// Clear the reference, so the request can be garbage collected once consumed.
+	q.items[0] = queueRequest[T]{}
+	q.items = q.items[1:]
+	q.mu.Unlock()
+
+	q.queueCapacityLimiter.release(item.size)
 	// the memory queue doesn't handle consume errors
 	_ = consumeFunc(item.ctx, item.req)
 	return true
 }
 
-// Shutdown closes the queue channel to initiate draining of the queue.
+// Shutdown marks the queue as stopped to initiate draining of the queue. Consumers keep getting the remaining
+// items and return false once the queue is emptied.
 func (q *boundedMemoryQueue[T]) Shutdown(context.Context) error {
-	close(q.items)
+	q.mu.Lock()
+	q.stopped = true
+	q.mu.Unlock()
+	q.hasElements.Broadcast()
 	return nil
 }
 
 type queueRequest[T any] struct {
 	req T
 	ctx context.Context
+	// size is the size of the request computed by the Sizer when it was offered.
+	size uint64
 }
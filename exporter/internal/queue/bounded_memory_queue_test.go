@@ -146,6 +146,31 @@ func queueUsage(tb testing.TB, sizer Sizer[fakeReq], requestsCount int) {
 	wg.Wait()
 }
 
+// The queue sized in bytes must not preallocate the buffer for the whole capacity.
+func TestBoundedQueueBytesSized(t *testing.T) {
+	q := NewBoundedMemoryQueue[fakeSizedReq](MemoryQueueSettings[fakeSizedReq]{Sizer: &BytesSizer[fakeSizedReq]{}, Capacity: 1 << 30})
+	assert.NoError(t, q.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, 1<<30, q.Capacity())
+
+	assert.NoError(t, q.Offer(context.Background(), fakeSizedReq{bytesSize: 1 << 29}))
+	assert.NoError(t, q.Offer(context.Background(), fakeSizedReq{bytesSize: 1 << 28}))
+	assert.Equal(t, 1<<29+1<<28, q.Size())
+	assert.ErrorIs(t, q.Offer(context.Background(), fakeSizedReq{bytesSize: 1 << 29}), ErrQueueIsFull)
+
+	assert.True(t, q.Consume(func(_ context.Context, item fakeSizedReq) error {
+		assert.Equal(t, 1<<29, item.bytesSize)
+		return nil
+	}))
+	assert.Equal(t, 1<<28, q.Size())
+	assert.NoError(t, q.Offer(context.Background(), fakeSizedReq{bytesSize: 1 << 29}))
+
+	assert.NoError(t, q.Shutdown(context.Background()))
+	assert.True(t, q.Consume(func(context.Context, fakeSizedReq) error { return nil }))
+	assert.True(t, q.Consume(func(context.Context, fakeSizedReq) error { return nil }))
+	assert.False(t, q.Consume(func(context.Context, fakeSizedReq) error { return nil }))
+	assert.Equal(t, 0, q.Size())
+}
+
 func TestZeroSizeNoConsumers(t *testing.T) {
 	q := NewBoundedMemoryQueue[string](MemoryQueueSettings[string]{Sizer: &RequestSizer[string]{}, Capacity: 0})
 
@@ -156,3 +181,26 @@ func TestZeroSizeNoConsumers(t *testing.T) {
 
 	assert.NoError(t, q.Shutdown(context.Background()))
 }
+
+func TestBoundedMemoryQueueReleasesClaimedSize(t *testing.T) {
+	q := NewBoundedMemoryQueue[*fakeMutableSizedReq](MemoryQueueSettings[*fakeMutableSizedReq]{
+		Sizer:    &BytesSizer[*fakeMutableSizedReq]{},
+		Capacity: 100,
+	})
+	req := &fakeMutableSizedReq{bytesSize: 40}
+	require.NoError(t, q.Offer(context.Background(), req))
+	assert.Equal(t, 40, q.Size())
+
+	// The size claimed when the request was offered is released, even if the request size changed since then.
+	req.bytesSize = 10
+	assert.True(t, q.Consume(func(context.Context, *fakeMutableSizedReq) error { return nil }))
+	assert.Equal(t, 0, q.Size())
+}
+
+type fakeMutableSizedReq struct {
+	bytesSize int
+}
+
+func (r *fakeMutableSizedReq) BytesSize() int {
+	return r.bytesSize
+}
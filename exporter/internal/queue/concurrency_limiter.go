@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// decreaseFactor is the factor applied to the concurrency limit on a failed or slow export.
+const decreaseFactor = 0.5
+
+// AdaptiveConcurrencySettings defines the bounds of the number of consumers exporting at the same time.
+type AdaptiveConcurrencySettings struct {
+	MinConsumers int
+	MaxConsumers int
+	// LatencyThreshold is the export attempt duration above which the attempt is treated as failed. Zero disables it.
+	LatencyThreshold time.Duration
+}
+
+// concurrencyLimiter limits the number of concurrent exports using the AIMD algorithm:
+// the limit is increased by about one after a full round of successful exports,
+// and is cut in half after an export fails or takes longer than the latency threshold.
+type concurrencyLimiter struct {
+	mu               sync.Mutex
+	hasSlots         *sync.Cond
+	minLimit         float64
+	maxLimit         float64
+	latencyThreshold time.Duration
+	limit            float64
+	inFlight         int
+	stopped          bool
+	// decreasedAt is used to decrease the limit only once for all the exports in flight at the time of the decrease.
+	decreasedAt time.Time
+}
+
+func newConcurrencyLimiter(initial int, set AdaptiveConcurrencySettings) *concurrencyLimiter {
+	cl := &concurrencyLimiter{
+		minLimit:         float64(set.MinConsumers),
+		maxLimit:         float64(set.MaxConsumers),
+		latencyThreshold: set.LatencyThreshold,
+	}
+	cl.limit = min(max(float64(initial), cl.minLimit), cl.maxLimit)
+	cl.hasSlots = sync.NewCond(&cl.mu)
+	return cl
+}
+
+// acquire blocks until the number of consumers in flight is below the limit. The limit is lifted once stopped,
+// so all the consumers can drain the queue.
+func (cl *concurrencyLimiter) acquire() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	for !cl.stopped && cl.inFlight >= int(cl.limit) {
+		cl.hasSlots.Wait()
+	}
+	cl.inFlight++
+}
+
+func (cl *concurrencyLimiter) release() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.inFlight--
+	cl.hasSlots.Signal()
+}
+
+// record adjusts the limit based on the result of the export started at the given time.
+// Permanent errors mean that the backend handled the request, so they are not counted as failures.
+func (cl *concurrencyLimiter) record(start time.Time, err error) {
+	now := time.Now()
+	slow := cl.latencyThreshold > 0 && now.Sub(start) > cl.latencyThreshold
+	failed := err != nil && !consumererror.IsPermanent(err)
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.stopped {
+		return
+	}
+
+	if failed || slow {
+		if start.After(cl.decreasedAt) {
+			cl.limit = max(cl.limit*decreaseFactor, cl.minLimit)
+			cl.decreasedAt = now
+		}
+		return
+	}
+
+	prevLimit := int(cl.limit)
+	cl.limit = min(cl.limit+1/cl.limit, cl.maxLimit)
+	if int(cl.limit) > prevLimit {
+		cl.hasSlots.Broadcast()
+	}
+}
+
+// current returns the current concurrency limit.
+func (cl *concurrencyLimiter) current() int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return int(cl.limit)
+}
+
+func (cl *concurrencyLimiter) stop() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.stopped = true
+	cl.hasSlots.Broadcast()
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestConcurrencyLimiter_AIMD(t *testing.T) {
+	cl := newConcurrencyLimiter(4, AdaptiveConcurrencySettings{MinConsumers: 1, MaxConsumers: 5})
+	assert.Equal(t, 4, cl.current())
+
+	// The limit is increased by about one after a full round of successful exports.
+	for i := 0; i < 5; i++ {
+		cl.record(time.Now(), nil)
+	}
+	assert.Equal(t, 5, cl.current())
+
+	// The limit doesn't go above the maximum.
+	for i := 0; i < 20; i++ {
+		cl.record(time.Now(), nil)
+	}
+	assert.Equal(t, 5, cl.current())
+
+	// The limit is cut in half once for all the exports in flight when it's decreased.
+	start := time.Now()
+	cl.record(start, errors.New("transient error"))
+	cl.record(start, errors.New("transient error"))
+	assert.Equal(t, 2, cl.current())
+
+	// The limit doesn't go below the minimum.
+	for i := 0; i < 5; i++ {
+		cl.record(time.Now(), errors.New("transient error"))
+	}
+	assert.Equal(t, 1, cl.current())
+}
+
+func TestConcurrencyLimiter_LatencyThreshold(t *testing.T) {
+	cl := newConcurrencyLimiter(4, AdaptiveConcurrencySettings{MinConsumers: 1, MaxConsumers: 10,
+		LatencyThreshold: time.Second})
+	cl.record(time.Now().Add(-500*time.Millisecond), nil)
+	assert.Equal(t, 4, cl.current())
+	cl.record(time.Now().Add(-2*time.Second), nil)
+	assert.Equal(t, 2, cl.current())
+}
+
+func TestConcurrencyLimiter_PermanentErrors(t *testing.T) {
+	cl := newConcurrencyLimiter(4, AdaptiveConcurrencySettings{MinConsumers: 1, MaxConsumers: 10})
+	// The permanent errors don't decrease the limit, the backend handled the requests.
+	for i := 0; i < 5; i++ {
+		cl.record(time.Now(), fmt.Errorf("not retryable error: %w", consumererror.NewPermanent(errors.New("bad data"))))
+	}
+	assert.Equal(t, 5, cl.current())
+}
+
+func TestConcurrencyLimiter_InitialLimitWithinBounds(t *testing.T) {
+	assert.Equal(t, 2, newConcurrencyLimiter(1, AdaptiveConcurrencySettings{MinConsumers: 2, MaxConsumers: 10}).current())
+	assert.Equal(t, 10, newConcurrencyLimiter(20, AdaptiveConcurrencySettings{MinConsumers: 2, MaxConsumers: 10}).current())
+}
+
+func TestAdaptiveQueueConsumers(t *testing.T) {
+	q := NewBoundedMemoryQueue[fakeReq](MemoryQueueSettings[fakeReq]{Sizer: &RequestSizer[fakeReq]{}, Capacity: 100})
+	inFlight, maxInFlight := atomic.Int64{}, atomic.Int64{}
+	release := make(chan struct{})
+	consumers := NewAdaptiveQueueConsumers[fakeReq](q, 2, AdaptiveConcurrencySettings{MinConsumers: 1, MaxConsumers: 10},
+		func(context.Context, fakeReq) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			<-release
+			return nil
+		})
+	require.NoError(t, consumers.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, 2, consumers.Concurrency())
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, q.Offer(context.Background(), fakeReq{itemsCount: 1}))
+	}
+
+	// Only the allowed number of consumers export at the same time.
+	assert.Eventually(t, func() bool { return inFlight.Load() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int64(2), maxInFlight.Load())
+
+	// The concurrency grows while the exports succeed.
+	close(release)
+	assert.Eventually(t, func() bool { return q.Size() == 0 && inFlight.Load() == 0 }, time.Second, time.Millisecond)
+	assert.Greater(t, consumers.Concurrency(), 2)
+	require.NoError(t, consumers.Shutdown(context.Background()))
+}
+
+func TestAdaptiveQueueConsumersAttemptRecorder(t *testing.T) {
+	q := NewBoundedMemoryQueue[fakeReq](MemoryQueueSettings[fakeReq]{Sizer: &RequestSizer[fakeReq]{}, Capacity: 100})
+	consumed := make(chan struct{}, 10)
+	consumers := NewAdaptiveQueueConsumers[fakeReq](q, 4, AdaptiveConcurrencySettings{MinConsumers: 1, MaxConsumers: 10},
+		func(context.Context, fakeReq) error {
+			consumed <- struct{}{}
+			return errors.New("transient error")
+		})
+	recordAttempt := consumers.AttemptRecorder()
+	require.NotNil(t, recordAttempt)
+	require.NoError(t, consumers.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The final results of the exports are not recorded once the attempts are.
+	require.NoError(t, q.Offer(context.Background(), fakeReq{itemsCount: 1}))
+	<-consumed
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 4, consumers.Concurrency())
+
+	recordAttempt(time.Now(), errors.New("transient error"))
+	assert.Equal(t, 2, consumers.Concurrency())
+	require.NoError(t, consumers.Shutdown(context.Background()))
+
+	assert.Nil(t, NewQueueConsumers[fakeReq](q, 4, nil).AttemptRecorder())
+}
@@ -6,6 +6,7 @@ package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 )
@@ -15,6 +16,11 @@ type Consumers[T any] struct {
 	numConsumers int
 	consumeFunc  func(context.Context, T) error
 	stopWG       sync.WaitGroup
+	// limiter adjusts the number of consumers exporting at the same time, nil if the number is fixed.
+	limiter *concurrencyLimiter
+	// recordAttempts indicates whether the limiter gets the result of every attempt of the exports
+	// from AttemptRecorder, instead of the final result of the exports.
+	recordAttempts bool
 }
 
 func NewQueueConsumers[T any](q Queue[T], numConsumers int, consumeFunc func(context.Context, T) error) *Consumers[T] {
@@ -26,6 +32,46 @@ func NewQueueConsumers[T any](q Queue[T], numConsumers int, consumeFunc func(con
 	}
 }
 
+// NewAdaptiveQueueConsumers returns consumers that start exporting with the given number of consumers
+// and adjust it within the given bounds based on the export latency and errors.
+func NewAdaptiveQueueConsumers[T any](q Queue[T], numConsumers int, set AdaptiveConcurrencySettings,
+	consumeFunc func(context.Context, T) error) *Consumers[T] {
+	qc := &Consumers[T]{
+		queue:        q,
+		numConsumers: set.MaxConsumers,
+		limiter:      newConcurrencyLimiter(numConsumers, set),
+		stopWG:       sync.WaitGroup{},
+	}
+	qc.consumeFunc = func(ctx context.Context, req T) error {
+		start := time.Now()
+		err := consumeFunc(ctx, req)
+		if !qc.recordAttempts {
+			qc.limiter.record(start, err)
+		}
+		return err
+	}
+	return qc
+}
+
+// AttemptRecorder returns the function recording the result of an export attempt started at the given time,
+// so the concurrency is adjusted after every attempt instead of after the final result of the exports, which
+// are retried. It returns nil if the number of consumers is fixed, and must be called before Start.
+func (qc *Consumers[T]) AttemptRecorder() func(start time.Time, err error) {
+	if qc.limiter == nil {
+		return nil
+	}
+	qc.recordAttempts = true
+	return qc.limiter.record
+}
+
+// Concurrency returns the number of consumers allowed to export at the same time.
+func (qc *Consumers[T]) Concurrency() int {
+	if qc.limiter == nil {
+		return qc.numConsumers
+	}
+	return qc.limiter.current()
+}
+
 // Start ensures that queue and all consumers are started.
 func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 	if err := qc.queue.Start(ctx, host); err != nil {
@@ -40,7 +86,7 @@ func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 			startWG.Done()
 			defer qc.stopWG.Done()
 			for {
-				if !qc.queue.Consume(qc.consumeFunc) {
+				if !qc.consume() {
 					return
 				}
 			}
@@ -51,11 +97,23 @@ func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+func (qc *Consumers[T]) consume() bool {
+	if qc.limiter == nil {
+		return qc.queue.Consume(qc.consumeFunc)
+	}
+	qc.limiter.acquire()
+	defer qc.limiter.release()
+	return qc.queue.Consume(qc.consumeFunc)
+}
+
 // Shutdown ensures that queue and all consumers are stopped.
 func (qc *Consumers[T]) Shutdown(ctx context.Context) error {
 	if err := qc.queue.Shutdown(ctx); err != nil {
 		return err
 	}
+	if qc.limiter != nil {
+		qc.limiter.stop()
+	}
 	qc.stopWG.Wait()
 	return nil
 }
@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// lanedMemoryQueue is an in-memory queue that keeps the requests in separate lanes. The lanes are consumed either
+// in their order, a lane being consumed only when all the previous lanes are empty, or in the weighted round-robin
+// order. It's the base of the queues partitioned by the client metadata and of the priority queues.
+type lanedMemoryQueue[T any] struct {
+	component.StartFunc
+	*queueCapacityLimiter[T]
+	strict bool
+	// onEmpty is called with the lock held when a lane is emptied by Consume, e.g. to remove the lane.
+	onEmpty func(*lane[T])
+
+	// mu guards everything declared below, including the lanes.
+	mu          sync.Mutex
+	hasElements *sync.Cond
+	lanes       []*lane[T]
+	// next is the index of the lane being consumed in the weighted mode,
+	// and served is the number of requests consumed from it in the current round.
+	next     int
+	served   int
+	numItems int
+	stopped  bool
+}
+
+type lane[T any] struct {
+	// name is the name of the priority lane.
+	name string
+	// md is the metadata of the partition.
+	md attribute.Set
+	// weight is the number of requests consumed from the lane in a row in the weighted mode.
+	weight int
+	// capacity is the capacity of the lane, zero means that the lane is limited by the queue capacity only.
+	capacity int
+	items    []queueRequest[T]
+	size     int
+}
+
+func newLanedMemoryQueue[T any](sizer Sizer[T], capacity int, strict bool) *lanedMemoryQueue[T] {
+	q := &lanedMemoryQueue[T]{
+		queueCapacityLimiter: newQueueCapacityLimiter[T](sizer, capacity),
+		strict:               strict,
+	}
+	q.hasElements = sync.NewCond(&q.mu)
+	return q
+}
+
+// put puts the request of the given size in the lane if it fits in the capacity of the lane and of the queue.
+// The lane doesn't have to be added yet, so it can be added only once it gets a request. The caller must hold the lock.
+func (q *lanedMemoryQueue[T]) put(ctx context.Context, l *lane[T], req T, reqSize uint64) error {
+	if l.capacity != 0 && l.size+int(reqSize) > l.capacity {
+		return ErrQueueIsFull
+	}
+	if !q.queueCapacityLimiter.claim(reqSize) {
+		return ErrQueueIsFull
+	}
+	l.items = append(l.items, queueRequest[T]{ctx: ctx, req: req, size: reqSize})
+	l.size += int(reqSize)
+	q.numItems++
+	q.hasElements.Signal()
+	return nil
+}
+
+// Consume applies the provided function on the head of the next lane: the first non-empty lane in the strict mode,
+// or the next non-empty lane in the weighted round-robin order otherwise.
+// The call blocks until there is an item available or the queue is stopped.
+// The function returns true when an item is consumed or false if the queue is stopped and emptied.
+func (q *lanedMemoryQueue[T]) Consume(consumeFunc func(context.Context, T) error) bool {
+	q.mu.Lock()
+	for q.numItems == 0 && !q.stopped {
+		q.hasElements.Wait()
+	}
+	if q.numItems == 0 {
+		q.mu.Unlock()
+		return false
+	}
+	l := q.nextLane()
+	item := l.items[0]
+	// Clear the reference, so the request can be garbage collected once consumed.
+	l.items[0] = queueRequest[T]{}
+	l.items = l.items[1:]
+	l.size -= int(item.size)
+	q.numItems--
+	if len(l.items) == 0 && q.onEmpty != nil {
+		q.onEmpty(l)
+	}
+	q.mu.Unlock()
+
+	q.queueCapacityLimiter.release(item.size)
+	// the memory queue doesn't handle consume errors
+	_ = consumeFunc(item.ctx, item.req)
+	return true
+}
+
+// nextLane returns the lane to consume from. The caller must hold the lock, and at least one item must be queued.
+func (q *lanedMemoryQueue[T]) nextLane() *lane[T] {
+	if q.strict {
+		for _, l := range q.lanes {
+			if len(l.items) > 0 {
+				return l
+			}
+		}
+	}
+	l := q.lanes[q.next]
+	for len(l.items) == 0 || q.served >= l.weight {
+		q.next = (q.next + 1) % len(q.lanes)
+		q.served = 0
+		l = q.lanes[q.next]
+	}
+	q.served++
+	return l
+}
+
+// removeLane removes the lane, the next lane in the round-robin order takes its place. The caller must hold the lock.
+func (q *lanedMemoryQueue[T]) removeLane(l *lane[T]) {
+	idx := slices.Index(q.lanes, l)
+	q.lanes = slices.Delete(q.lanes, idx, idx+1)
+	switch {
+	case idx < q.next:
+		q.next--
+	case idx == q.next:
+		q.served = 0
+	}
+	if q.next >= len(q.lanes) {
+		q.next = 0
+	}
+}
+
+// Shutdown marks the queue as stopped to initiate draining of the queue. Consumers keep getting the remaining
+// items and return false once the queue is emptied.
+func (q *lanedMemoryQueue[T]) Shutdown(context.Context) error {
+	q.mu.Lock()
+	q.stopped = true
+	q.mu.Unlock()
+	q.hasElements.Broadcast()
+	return nil
+}
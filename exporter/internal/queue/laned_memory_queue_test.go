@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanedQueue_RemoveLane(t *testing.T) {
+	q := newLanedMemoryQueue[string](&RequestSizer[string]{}, 100, false)
+	a := &lane[string]{name: "a", weight: 1}
+	b := &lane[string]{name: "b", weight: 1}
+	c := &lane[string]{name: "c", weight: 1}
+	q.lanes = []*lane[string]{a, b, c}
+	q.onEmpty = q.removeLane
+
+	q.mu.Lock()
+	for _, item := range []struct {
+		l   *lane[string]
+		req string
+	}{{a, "a1"}, {a, "a2"}, {b, "b1"}, {c, "c1"}, {c, "c2"}} {
+		require.NoError(t, q.put(context.Background(), item.l, item.req, 1))
+	}
+	q.mu.Unlock()
+
+	var consumed []string
+	for q.Size() > 0 {
+		require.True(t, q.Consume(func(_ context.Context, item string) error {
+			consumed = append(consumed, item)
+			return nil
+		}))
+	}
+	// The lane b is removed once emptied, and the round-robin order continues with the lane c.
+	assert.Equal(t, []string{"a1", "b1", "c1", "a2", "c2"}, consumed)
+	assert.Empty(t, q.lanes)
+}
+
+func TestLanedQueue_LaneCapacity(t *testing.T) {
+	q := newLanedMemoryQueue[string](&RequestSizer[string]{}, 3, true)
+	a := &lane[string]{name: "a", weight: 1, capacity: 1}
+	b := &lane[string]{name: "b", weight: 1}
+	q.lanes = []*lane[string]{a, b}
+
+	q.mu.Lock()
+	require.NoError(t, q.put(context.Background(), a, "a1", 1))
+	require.ErrorIs(t, q.put(context.Background(), a, "a2", 1), ErrQueueIsFull)
+	require.NoError(t, q.put(context.Background(), b, "b1", 1))
+	require.NoError(t, q.put(context.Background(), b, "b2", 1))
+	require.ErrorIs(t, q.put(context.Background(), b, "b3", 1), ErrQueueIsFull)
+	q.mu.Unlock()
+
+	assert.Equal(t, 3, q.Size())
+	require.NoError(t, q.Shutdown(context.Background()))
+}
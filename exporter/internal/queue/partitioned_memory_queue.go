@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+// errTooManyPartitions is returned when the MetadataCardinalityLimit has been reached.
+var errTooManyPartitions = errors.New("too many sending queue partitions, metadata cardinality limit reached")
+
+// PartitionedQueue is implemented by the queues keeping the requests from different clients in separate partitions.
+type PartitionedQueue interface {
+	// VisitPartitions calls the given function with the metadata values and the size of every partition.
+	VisitPartitions(func(md attribute.Set, size int))
+}
+
+// PartitionWeight defines the number of requests consumed from the partitions matching the metadata
+// before moving to the next partition.
+type PartitionWeight struct {
+	Metadata map[string]string
+	Weight   int
+}
+
+// PartitionedMemoryQueueSettings defines internal parameters for partitionedMemoryQueue creation.
+type PartitionedMemoryQueueSettings[T any] struct {
+	Sizer    Sizer[T]
+	Capacity int
+	// MetadataKeys is the list of client.Metadata keys used to form the partitions.
+	MetadataKeys []string
+	// MetadataCardinalityLimit is the maximum number of partitions, zero means no limit.
+	MetadataCardinalityLimit int
+	// PartitionCapacity is the capacity of every partition, zero means that partitions are limited by Capacity only.
+	PartitionCapacity int
+	// Weights defines the weights of the partitions. Partitions not matching any of them have weight 1.
+	Weights []PartitionWeight
+}
+
+// partitionedMemoryQueue is an in-memory queue that keeps the requests in a separate partition per distinct
+// combination of client metadata values, and consumes the partitions in a weighted round-robin order,
+// so a single client filling up its partition doesn't prevent the others from being exported.
+// The partitions are the lanes of the queue, they are created for the first accepted request of a client
+// and removed once emptied.
+type partitionedMemoryQueue[T any] struct {
+	*lanedMemoryQueue[T]
+	metadataKeys      []string
+	cardinalityLimit  int
+	partitionCapacity int
+	weights           []PartitionWeight
+
+	// partitions is guarded by the lock of the lanedMemoryQueue.
+	partitions map[attribute.Distinct]*lane[T]
+}
+
+// NewPartitionedMemoryQueue constructs a new in-memory queue partitioned by the client metadata.
+func NewPartitionedMemoryQueue[T any](set PartitionedMemoryQueueSettings[T]) Queue[T] {
+	q := &partitionedMemoryQueue[T]{
+		lanedMemoryQueue:  newLanedMemoryQueue[T](set.Sizer, set.Capacity, false),
+		metadataKeys:      set.MetadataKeys,
+		cardinalityLimit:  set.MetadataCardinalityLimit,
+		partitionCapacity: set.PartitionCapacity,
+		weights:           set.Weights,
+		partitions:        map[attribute.Distinct]*lane[T]{},
+	}
+	// Remove the emptied partitions, so the partitions of the clients that stopped sending
+	// don't count towards the metadata cardinality limit.
+	q.onEmpty = func(p *lane[T]) {
+		delete(q.partitions, p.md.Equivalent())
+		q.removeLane(p)
+	}
+	return q
+}
+
+// Offer puts the request in the partition of the client the request is received from.
+// The partition is created only once the request fits in the capacity of the queue and the partition.
+func (q *partitionedMemoryQueue[T]) Offer(ctx context.Context, req T) error {
+	md, attrs := q.partitionMetadata(ctx)
+	reqSize := q.queueCapacityLimiter.sizeOf(req)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	p, ok := q.partitions[attrs.Equivalent()]
+	if !ok {
+		if q.cardinalityLimit != 0 && len(q.partitions) >= q.cardinalityLimit {
+			return errTooManyPartitions
+		}
+		p = &lane[T]{md: attrs, weight: q.partitionWeight(md), capacity: q.partitionCapacity}
+	}
+	if err := q.put(ctx, p, req, reqSize); err != nil {
+		return err
+	}
+	if !ok {
+		q.partitions[attrs.Equivalent()] = p
+		q.lanes = append(q.lanes, p)
+	}
+	return nil
+}
+
+// partitionMetadata returns the values of the metadata keys of the client and the attribute set identifying
+// the partition, the same way the batch processor forms its batchers.
+func (q *partitionedMemoryQueue[T]) partitionMetadata(ctx context.Context) (client.Metadata, attribute.Set) {
+	info := client.FromContext(ctx)
+	md := map[string][]string{}
+	attrs := make([]attribute.KeyValue, 0, len(q.metadataKeys))
+	for _, k := range q.metadataKeys {
+		vs := info.Metadata.Get(k)
+		md[k] = vs
+		if len(vs) == 1 {
+			attrs = append(attrs, attribute.String(k, vs[0]))
+		} else {
+			attrs = append(attrs, attribute.StringSlice(k, vs))
+		}
+	}
+	return client.NewMetadata(md), attribute.NewSet(attrs...)
+}
+
+// partitionWeight returns the weight of the first PartitionWeight matching all the metadata values.
+func (q *partitionedMemoryQueue[T]) partitionWeight(md client.Metadata) int {
+	for _, w := range q.weights {
+		matches := true
+		for k, v := range w.Metadata {
+			if vs := md.Get(k); len(vs) != 1 || vs[0] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return w.Weight
+		}
+	}
+	return 1
+}
+
+// VisitPartitions implements PartitionedQueue.
+func (q *partitionedMemoryQueue[T]) VisitPartitions(visit func(md attribute.Set, size int)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, p := range q.lanes {
+		visit(p.md, p.size)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+func tenantContext(tenant string) context.Context {
+	return client.NewContext(context.Background(), client.Info{
+		Metadata: client.NewMetadata(map[string][]string{"X-Tenant": {tenant}}),
+	})
+}
+
+// consumeAll consumes the items from the queue until it's empty and returns them in the consumed order.
+func consumeAll(t *testing.T, q Queue[string]) []string {
+	var consumed []string
+	for q.Size() > 0 {
+		require.True(t, q.Consume(func(_ context.Context, item string) error {
+			consumed = append(consumed, item)
+			return nil
+		}))
+	}
+	return consumed
+}
+
+func TestPartitionedQueue_RoundRobin(t *testing.T) {
+	q := NewPartitionedMemoryQueue[string](PartitionedMemoryQueueSettings[string]{
+		Sizer:        &RequestSizer[string]{},
+		Capacity:     100,
+		MetadataKeys: []string{"x-tenant"},
+	})
+
+	for _, item := range []string{"a1", "a2", "a3"} {
+		require.NoError(t, q.Offer(tenantContext("a"), item))
+	}
+	for _, item := range []string{"b1", "b2"} {
+		require.NoError(t, q.Offer(tenantContext("b"), item))
+	}
+	require.NoError(t, q.Offer(context.Background(), "none1"))
+	assert.Equal(t, 6, q.Size())
+
+	assert.Equal(t, []string{"a1", "b1", "none1", "a2", "b2", "a3"}, consumeAll(t, q))
+}
+
+func TestPartitionedQueue_Weights(t *testing.T) {
+	q := NewPartitionedMemoryQueue[string](PartitionedMemoryQueueSettings[string]{
+		Sizer:        &RequestSizer[string]{},
+		Capacity:     100,
+		MetadataKeys: []string{"x-tenant"},
+		Weights:      []PartitionWeight{{Metadata: map[string]string{"x-tenant": "a"}, Weight: 2}},
+	})
+
+	for _, item := range []string{"a1", "a2", "a3", "a4"} {
+		require.NoError(t, q.Offer(tenantContext("a"), item))
+	}
+	for _, item := range []string{"b1", "b2", "b3"} {
+		require.NoError(t, q.Offer(tenantContext("b"), item))
+	}
+
+	assert.Equal(t, []string{"a1", "a2", "b1", "a3", "a4", "b2", "b3"}, consumeAll(t, q))
+}
+
+func TestPartitionedQueue_PartitionCapacity(t *testing.T) {
+	q := NewPartitionedMemoryQueue[string](PartitionedMemoryQueueSettings[string]{
+		Sizer:             &RequestSizer[string]{},
+		Capacity:          3,
+		MetadataKeys:      []string{"x-tenant"},
+		PartitionCapacity: 2,
+	})
+
+	// A noisy tenant cannot take the whole queue.
+	require.NoError(t, q.Offer(tenantContext("a"), "a1"))
+	require.NoError(t, q.Offer(tenantContext("a"), "a2"))
+	assert.ErrorIs(t, q.Offer(tenantContext("a"), "a3"), ErrQueueIsFull)
+	require.NoError(t, q.Offer(tenantContext("b"), "b1"))
+
+	// The whole queue capacity is still respected, without creating a partition for the rejected request.
+	assert.ErrorIs(t, q.Offer(tenantContext("c"), "c1"), ErrQueueIsFull)
+
+	sizes := map[string]int{}
+	q.(PartitionedQueue).VisitPartitions(func(md attribute.Set, size int) {
+		tenant, _ := md.Value("x-tenant")
+		sizes[tenant.AsString()] = size
+	})
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, sizes)
+}
+
+func TestPartitionedQueue_CardinalityLimit(t *testing.T) {
+	q := NewPartitionedMemoryQueue[string](PartitionedMemoryQueueSettings[string]{
+		Sizer:                    &RequestSizer[string]{},
+		Capacity:                 100,
+		MetadataKeys:             []string{"x-tenant"},
+		MetadataCardinalityLimit: 2,
+	})
+
+	require.NoError(t, q.Offer(tenantContext("a"), "a1"))
+	require.NoError(t, q.Offer(tenantContext("b"), "b1"))
+	assert.ErrorIs(t, q.Offer(tenantContext("c"), "c1"), errTooManyPartitions)
+	require.NoError(t, q.Offer(tenantContext("a"), "a2"))
+
+	// The emptied partitions are evicted and don't count towards the limit anymore.
+	assert.Equal(t, []string{"a1", "b1", "a2"}, consumeAll(t, q))
+	require.NoError(t, q.Offer(tenantContext("c"), "c1"))
+	require.NoError(t, q.Offer(tenantContext("d"), "d1"))
+	assert.ErrorIs(t, q.Offer(tenantContext("a"), "a3"), errTooManyPartitions)
+	assert.Equal(t, []string{"c1", "d1"}, consumeAll(t, q))
+}
+
+func TestPartitionedQueue_ShutdownDrains(t *testing.T) {
+	q := NewPartitionedMemoryQueue[string](PartitionedMemoryQueueSettings[string]{
+		Sizer:        &RequestSizer[string]{},
+		Capacity:     100,
+		MetadataKeys: []string{"x-tenant"},
+	})
+	require.NoError(t, q.Offer(tenantContext("a"), "a1"))
+	require.NoError(t, q.Offer(tenantContext("b"), "b1"))
+	require.NoError(t, q.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"a1", "b1"}, consumeAll(t, q))
+	assert.False(t, q.Consume(func(context.Context, string) error { return nil }))
+}
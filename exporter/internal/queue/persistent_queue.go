@@ -4,14 +4,21 @@
 package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/crc32"
+	"slices"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
 
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
@@ -19,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/internal/experr"
 	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
 )
 
 // persistentQueue provides a persistent queue implementation backed by file storage extension
@@ -43,6 +51,9 @@ import (
 //	 write          read    x     └── currently dispatched item
 //	 index          index   x
 //	                        xxxx deleted
+//
+// Every item is stored with a checksum, the items failing the check or the unmarshaling are dropped and counted.
+// On start, the indices are checked against the stored items and repaired if they are inconsistent or corrupted.
 type persistentQueue[T any] struct {
 	*queueCapacityLimiter[T]
 
@@ -55,12 +66,18 @@ type persistentQueue[T any] struct {
 
 	putChan chan struct{}
 
+	metricCorruptedItems otelmetric.Int64Counter
+	metricAttrs          otelmetric.MeasurementOption
+
 	// mu guards everything declared below.
-	mu                       sync.Mutex
-	readIndex                uint64
-	writeIndex               uint64
-	initIndexSize            uint64
-	initQueueSize            *atomic.Uint64
+	mu            sync.Mutex
+	readIndex     uint64
+	writeIndex    uint64
+	initIndexSize uint64
+	initQueueSize *atomic.Uint64
+	// itemSizes are the sizes of the items written since the start in the order of their indices,
+	// so the size claimed by an item is released even if the item cannot be read back.
+	itemSizes                []uint64
 	currentlyDispatchedItems []uint64
 	refClient                int64
 	stopped                  bool
@@ -77,9 +94,20 @@ const (
 	queueSizeKey                = "si"
 )
 
+// scopeName is the instrumentation scope of the persistent queue metrics, the same as for the other queue metrics.
+const scopeName = "go.opentelemetry.io/collector/exporterhelper"
+
+// crcTable is used to compute the checksums of the stored items.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// itemHeader prefixes the items stored with a checksum. The items without it were stored by the previous
+// versions of the queue, and are read as is.
+var itemHeader = []byte{0xff, 'o', 't', 'q'}
+
 var (
 	errValueNotSet        = errors.New("value not set")
 	errInvalidValue       = errors.New("invalid value")
+	errChecksumMismatch   = errors.New("item checksum mismatch")
 	errNoStorageClient    = errors.New("no storage client extension found")
 	errWrongExtensionType = errors.New("requested extension is not a storage extension")
 )
@@ -92,6 +120,9 @@ type PersistentQueueSettings[T any] struct {
 	Marshaler        func(req T) ([]byte, error)
 	Unmarshaler      func([]byte) (T, error)
 	ExporterSettings exporter.CreateSettings
+	// StorageName is the name of the storage client. If empty, the DataType is used as the name.
+	// It allows an exporter to keep several queues of the same data type in one storage extension.
+	StorageName string
 }
 
 // NewPersistentQueue creates a new queue backed by file storage; name and signal must be a unique combination that identifies the queue storage
@@ -104,15 +135,31 @@ func NewPersistentQueue[T any](set PersistentQueueSettings[T]) Queue[T] {
 		initQueueSize:        &atomic.Uint64{},
 		isRequestSized:       isRequestSized,
 		putChan:              make(chan struct{}, set.Capacity),
+		metricCorruptedItems: noop.Int64Counter{},
 	}
 }
 
 // Start starts the persistentQueue with the given number of consumers.
 func (pq *persistentQueue[T]) Start(ctx context.Context, host component.Host) error {
-	storageClient, err := toStorageClient(ctx, pq.set.StorageID, host, pq.set.ExporterSettings.ID, pq.set.DataType)
+	storageName := pq.set.StorageName
+	if storageName == "" {
+		storageName = pq.set.DataType.String()
+	}
+	storageClient, err := toStorageClient(ctx, pq.set.StorageID, host, pq.set.ExporterSettings.ID, storageName)
+	if err != nil {
+		return err
+	}
+
+	pq.metricAttrs = otelmetric.WithAttributeSet(attribute.NewSet(
+		attribute.String(obsmetrics.ExporterKey, pq.set.ExporterSettings.ID.String())))
+	pq.metricCorruptedItems, err = pq.set.ExporterSettings.MeterProvider.Meter(scopeName).Int64Counter(
+		obsmetrics.ExporterKey+"/queue_corrupted_items",
+		otelmetric.WithDescription("Number of corrupted items dropped from the persistent queue"),
+		otelmetric.WithUnit("1"))
 	if err != nil {
 		return err
 	}
+
 	pq.initClient(ctx, storageClient)
 	return nil
 }
@@ -129,24 +176,22 @@ func (pq *persistentQueue[T]) initClient(ctx context.Context, client storage.Cli
 func (pq *persistentQueue[T]) initPersistentContiguousStorage(ctx context.Context) {
 	riOp := storage.GetOperation(readIndexKey)
 	wiOp := storage.GetOperation(writeIndexKey)
+	diOp := storage.GetOperation(currentlyDispatchedItemsKey)
 
-	err := pq.client.Batch(ctx, riOp, wiOp)
-	if err == nil {
-		pq.readIndex, err = bytesToItemIndex(riOp.Value)
-	}
-
-	if err == nil {
-		pq.writeIndex, err = bytesToItemIndex(wiOp.Value)
-	}
-
-	if err != nil {
-		if errors.Is(err, errValueNotSet) {
-			pq.logger.Info("Initializing new persistent queue")
-		} else {
-			pq.logger.Error("Failed getting read/write index, starting with new ones", zap.Error(err))
-		}
+	err := pq.client.Batch(ctx, riOp, wiOp, diOp)
+	switch {
+	case err != nil:
+		pq.logger.Error("Failed getting read/write index, starting with new ones", zap.Error(err))
 		pq.readIndex = 0
 		pq.writeIndex = 0
+	case riOp.Value == nil && wiOp.Value == nil && diOp.Value == nil && !pq.itemExists(ctx, 0):
+		pq.logger.Info("Initializing new persistent queue")
+		pq.readIndex = 0
+		pq.writeIndex = 0
+	default:
+		// The dispatched items are only used to limit the repair, they are re-read and validated later.
+		dispatchedItems, _ := bytesToItemIndexArray(diOp.Value)
+		pq.recoverIndices(ctx, riOp.Value, wiOp.Value, dispatchedItems)
 	}
 	pq.initIndexSize = pq.writeIndex - pq.readIndex
 
@@ -183,6 +228,80 @@ func (pq *persistentQueue[T]) initPersistentContiguousStorage(ctx context.Contex
 	}
 }
 
+// recoverIndices restores the read and write indices from their stored values, and repairs them
+// by looking up the stored items if they are missing, corrupted, or inconsistent with each other.
+// Storage engines without transactions can keep an item written without the updated write index after a crash.
+// The items are stored contiguously between the indices, so they are looked up with a logarithmic number of reads.
+func (pq *persistentQueue[T]) recoverIndices(ctx context.Context, riBuf, wiBuf []byte, dispatchedItems []uint64) {
+	repaired := false
+	readIndex, riErr := bytesToItemIndex(riBuf)
+	writeIndex, wiErr := bytesToItemIndex(wiBuf)
+
+	if wiErr != nil {
+		// The items are written after the read index and the dispatched items.
+		writeIndex = 0
+		if riErr == nil {
+			writeIndex = readIndex
+		}
+		for _, di := range dispatchedItems {
+			writeIndex = max(writeIndex, di+1)
+		}
+		repaired = true
+	}
+
+	// Move the write index after the last stored item.
+	if lastIndex := pq.firstMissingItem(ctx, writeIndex); lastIndex != writeIndex {
+		writeIndex = lastIndex
+		repaired = true
+	}
+
+	// The read index is not stored until the first item is read, so the queue can have items without it.
+	if riErr != nil || readIndex > writeIndex {
+		repaired = repaired || !errors.Is(riErr, errValueNotSet)
+		// Move the read index back to the first not dispatched item of the items stored up to the write index.
+		// The items before it are either deleted or dispatched.
+		readIndex = uint64(sort.Search(int(writeIndex), func(i int) bool {
+			return !slices.Contains(dispatchedItems, uint64(i)) && pq.itemExists(ctx, uint64(i))
+		}))
+	}
+
+	if repaired {
+		pq.logger.Warn("Repaired the corrupted or inconsistent persistent queue indices",
+			zap.Uint64("readIndex", readIndex), zap.Uint64("writeIndex", writeIndex))
+	}
+	pq.readIndex = readIndex
+	pq.writeIndex = writeIndex
+}
+
+// firstMissingItem returns the index of the first item not stored starting from the given index.
+// The distance to the last stored item is doubled until it's passed, then the index is found by bisection.
+func (pq *persistentQueue[T]) firstMissingItem(ctx context.Context, index uint64) uint64 {
+	if !pq.itemExists(ctx, index) {
+		return index
+	}
+	stored, step := index, uint64(1)
+	for pq.itemExists(ctx, index+step) {
+		stored = index + step
+		step *= 2
+	}
+	missing := index + step
+	for missing-stored > 1 {
+		mid := stored + (missing-stored)/2
+		if pq.itemExists(ctx, mid) {
+			stored = mid
+		} else {
+			missing = mid
+		}
+	}
+	return missing
+}
+
+// itemExists returns true if the item with the given index is stored.
+func (pq *persistentQueue[T]) itemExists(ctx context.Context, index uint64) bool {
+	val, err := pq.client.Get(ctx, getItemKey(index))
+	return err == nil && val != nil
+}
+
 // Consume applies the provided function on the head of queue.
 // The call blocks until there is an item available or the queue is stopped.
 // The function returns true when an item is consumed or false if the queue is stopped.
@@ -258,7 +377,8 @@ func (pq *persistentQueue[T]) Offer(ctx context.Context, req T) error {
 
 // putInternal is the internal version that requires caller to hold the mutex lock.
 func (pq *persistentQueue[T]) putInternal(ctx context.Context, req T) error {
-	if !pq.queueCapacityLimiter.claim(req) {
+	reqSize := pq.queueCapacityLimiter.sizeOf(req)
+	if !pq.queueCapacityLimiter.claim(reqSize) {
 		pq.logger.Warn("Maximum queue capacity reached")
 		return ErrQueueIsFull
 	}
@@ -268,21 +388,22 @@ func (pq *persistentQueue[T]) putInternal(ctx context.Context, req T) error {
 
 	reqBuf, err := pq.set.Marshaler(req)
 	if err != nil {
-		pq.queueCapacityLimiter.release(req)
+		pq.queueCapacityLimiter.release(reqSize)
 		return err
 	}
 
 	// Carry out a transaction where we both add the item and update the write index
 	ops := []storage.Operation{
 		storage.SetOperation(writeIndexKey, itemIndexToBytes(newIndex)),
-		storage.SetOperation(itemKey, reqBuf),
+		storage.SetOperation(itemKey, itemWithChecksum(reqBuf)),
 	}
 	if storageErr := pq.client.Batch(ctx, ops...); storageErr != nil {
-		pq.queueCapacityLimiter.release(req)
+		pq.queueCapacityLimiter.release(reqSize)
 		return storageErr
 	}
 
 	pq.writeIndex = newIndex
+	pq.itemSizes = append(pq.itemSizes, reqSize)
 	// Inform the loop that there's some data to process
 	pq.putChan <- struct{}{}
 
@@ -324,9 +445,15 @@ func (pq *persistentQueue[T]) getNextItem(ctx context.Context) (T, func(error),
 		getOp)
 
 	if err == nil {
-		request, err = pq.set.Unmarshaler(getOp.Value)
+		request, err = pq.unmarshalItem(getOp.Value)
+		if err != nil {
+			pq.dropCorruptedItem(ctx, getOp.Key, err)
+		}
 	}
 
+	// The capacity of the item is released whether it's read or dropped.
+	pq.releaseCapacity(request, err == nil)
+
 	if err != nil {
 		pq.logger.Debug("Failed to dispatch item", zap.Error(err))
 		// We need to make sure that currently dispatched items list is cleaned
@@ -337,8 +464,6 @@ func (pq *persistentQueue[T]) getNextItem(ctx context.Context) (T, func(error),
 		return request, nil, false
 	}
 
-	pq.releaseCapacity(request)
-
 	// Back up the queue size to storage on every 10 reads. The stored value is used to recover the queue size
 	// in case if the collector is killed. The recovered queue size is allowed to be inaccurate.
 	if (pq.writeIndex % 10) == 0 {
@@ -374,16 +499,46 @@ func (pq *persistentQueue[T]) getNextItem(ctx context.Context) (T, func(error),
 	}, true
 }
 
-// releaseCapacity releases the capacity of the queue. The caller must hold the mutex.
-func (pq *persistentQueue[T]) releaseCapacity(req T) {
-	// If the recovered queue size is not emptied yet, decrease it first.
+// unmarshalItem verifies the checksum of the stored item and unmarshals it.
+func (pq *persistentQueue[T]) unmarshalItem(buf []byte) (T, error) {
+	if buf == nil {
+		var req T
+		return req, errValueNotSet
+	}
+	payload, err := itemPayload(buf)
+	if err != nil {
+		var req T
+		return req, err
+	}
+	return pq.set.Unmarshaler(payload)
+}
+
+// dropCorruptedItem records the corrupted item that cannot be read. The caller must hold the mutex.
+func (pq *persistentQueue[T]) dropCorruptedItem(ctx context.Context, key string, err error) {
+	pq.logger.Error("Dropping corrupted item from the persistent queue", zap.String(zapKey, key), zap.Error(err))
+	pq.metricCorruptedItems.Add(ctx, 1, pq.metricAttrs)
+}
+
+// releaseCapacity releases the capacity of the item read from the queue, read indicates whether the request was
+// read or the item was dropped. The caller must hold the mutex.
+func (pq *persistentQueue[T]) releaseCapacity(req T, read bool) {
+	// If the recovered queue size is not emptied yet, decrease it first. The sizes of the items written before
+	// the start are not known, so the size of the dropped items is released once the recovered items are drained.
 	if pq.initIndexSize > 0 {
 		pq.initIndexSize--
 		if pq.initIndexSize == 0 {
 			pq.initQueueSize.Store(0)
 			return
 		}
-		reqSize := pq.queueCapacityLimiter.sizeOf(req)
+		var reqSize uint64
+		switch {
+		case pq.isRequestSized:
+			reqSize = 1
+		case read:
+			reqSize = pq.queueCapacityLimiter.sizeOf(req)
+		default:
+			return
+		}
 		if pq.initQueueSize.Load() < reqSize {
 			pq.initQueueSize.Store(0)
 			return
@@ -392,8 +547,10 @@ func (pq *persistentQueue[T]) releaseCapacity(req T) {
 		return
 	}
 
-	// Otherwise, decrease the current queue size.
-	pq.queueCapacityLimiter.release(req)
+	// Otherwise, decrease the current queue size by the size claimed by the item when it was written.
+	reqSize := pq.itemSizes[0]
+	pq.itemSizes = pq.itemSizes[1:]
+	pq.queueCapacityLimiter.release(reqSize)
 }
 
 // retrieveAndEnqueueNotDispatchedReqs gets the items for which sending was not finished, cleans the storage
@@ -445,10 +602,11 @@ func (pq *persistentQueue[T]) retrieveAndEnqueueNotDispatchedReqs(ctx context.Co
 			pq.logger.Warn("Failed retrieving item", zap.String(zapKey, op.Key), zap.Error(errValueNotSet))
 			continue
 		}
-		req, err := pq.set.Unmarshaler(op.Value)
+		req, err := pq.unmarshalItem(op.Value)
 		// If error happened or item is nil, it will be efficiently ignored
 		if err != nil {
-			pq.logger.Warn("Failed unmarshalling item", zap.String(zapKey, op.Key), zap.Error(err))
+			pq.logger.Error("Dropping corrupted item from the persistent queue", zap.String(zapKey, op.Key), zap.Error(err))
+			pq.metricCorruptedItems.Add(ctx, 1, pq.metricAttrs)
 			continue
 		}
 		if pq.putInternal(ctx, req) != nil {
@@ -501,7 +659,7 @@ func (pq *persistentQueue[T]) itemDispatchingFinish(ctx context.Context, index u
 	return nil
 }
 
-func toStorageClient(ctx context.Context, storageID component.ID, host component.Host, ownerID component.ID, signal component.DataType) (storage.Client, error) {
+func toStorageClient(ctx context.Context, storageID component.ID, host component.Host, ownerID component.ID, name string) (storage.Client, error) {
 	ext, found := host.GetExtensions()[storageID]
 	if !found {
 		return nil, errNoStorageClient
@@ -512,13 +670,39 @@ func toStorageClient(ctx context.Context, storageID component.ID, host component
 		return nil, errWrongExtensionType
 	}
 
-	return storageExt.GetClient(ctx, component.KindExporter, ownerID, signal.String())
+	return storageExt.GetClient(ctx, component.KindExporter, ownerID, name)
 }
 
 func getItemKey(index uint64) string {
 	return strconv.FormatUint(index, 10)
 }
 
+// itemWithChecksum prefixes the marshaled item with the header and the CRC-32 checksum of the item.
+func itemWithChecksum(buf []byte) []byte {
+	res := make([]byte, 0, len(itemHeader)+4+len(buf))
+	res = append(res, itemHeader...)
+	res = binary.LittleEndian.AppendUint32(res, crc32.Checksum(buf, crcTable))
+	return append(res, buf...)
+}
+
+// itemPayload verifies the checksum of the stored item and returns the marshaled item.
+// The items stored without the header are returned as is.
+func itemPayload(buf []byte) ([]byte, error) {
+	if !bytes.HasPrefix(buf, itemHeader) {
+		return buf, nil
+	}
+	buf = buf[len(itemHeader):]
+	// The sizeof uint32 in binary is 4.
+	if len(buf) < 4 {
+		return nil, errInvalidValue
+	}
+	payload := buf[4:]
+	if binary.LittleEndian.Uint32(buf) != crc32.Checksum(payload, crcTable) {
+		return nil, errChecksumMismatch
+	}
+	return payload, nil
+}
+
 func itemIndexToBytes(value uint64) []byte {
 	return binary.LittleEndian.AppendUint64([]byte{}, value)
 }
@@ -15,6 +15,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter/exportertest"
@@ -33,6 +35,11 @@ func (tr tracesRequest) ItemsCount() int {
 	return tr.traces.SpanCount()
 }
 
+func (tr tracesRequest) BytesSize() int {
+	marshaler := &ptrace.ProtoMarshaler{}
+	return marshaler.TracesSize(tr.traces)
+}
+
 func marshalTracesRequest(tr tracesRequest) ([]byte, error) {
 	marshaler := &ptrace.ProtoMarshaler{}
 	return marshaler.MarshalTraces(tr.traces)
@@ -114,6 +121,7 @@ func createTestPersistentQueueWithCapacityLimiter(t testing.TB, ext storage.Exte
 }
 
 func TestPersistentQueue_FullCapacity(t *testing.T) {
+	reqBytesSize := newTracesRequest(1, 10).BytesSize()
 	tests := []struct {
 		name           string
 		sizer          Sizer[tracesRequest]
@@ -132,6 +140,12 @@ func TestPersistentQueue_FullCapacity(t *testing.T) {
 			capacity:       55,
 			sizeMultiplier: 10,
 		},
+		{
+			name:           "bytes_capacity",
+			sizer:          &BytesSizer[tracesRequest]{},
+			capacity:       5*reqBytesSize + reqBytesSize/2,
+			sizeMultiplier: reqBytesSize,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -304,7 +318,7 @@ func TestToStorageClient(t *testing.T) {
 			ownerID := component.MustNewID("foo_exporter")
 
 			// execute
-			client, err := toStorageClient(context.Background(), storageID, host, ownerID, component.DataTypeTraces)
+			client, err := toStorageClient(context.Background(), storageID, host, ownerID, component.DataTypeTraces.String())
 
 			// verify
 			if tC.expectedError != nil {
@@ -334,7 +348,7 @@ func TestInvalidStorageExtensionType(t *testing.T) {
 	ownerID := component.MustNewID("foo_exporter")
 
 	// execute
-	client, err := toStorageClient(context.Background(), storageID, host, ownerID, component.DataTypeTraces)
+	client, err := toStorageClient(context.Background(), storageID, host, ownerID, component.DataTypeTraces.String())
 
 	// we should get an error about the extension type
 	assert.ErrorIs(t, err, errWrongExtensionType)
@@ -381,12 +395,12 @@ func TestPersistentQueue_CorruptedData(t *testing.T) {
 		{
 			name:             "corrupted read index",
 			corruptReadIndex: true,
-			desiredQueueSize: 1, // The dispatched item.
+			desiredQueueSize: 3, // The read index is recovered from the stored items.
 		},
 		{
 			name:              "corrupted write index",
 			corruptWriteIndex: true,
-			desiredQueueSize:  1, // The dispatched item.
+			desiredQueueSize:  3, // The write index is recovered from the stored items.
 		},
 		{
 			name:                               "corrupted everything",
@@ -394,7 +408,7 @@ func TestPersistentQueue_CorruptedData(t *testing.T) {
 			corruptCurrentlyDispatchedItemsKey: true,
 			corruptReadIndex:                   true,
 			corruptWriteIndex:                  true,
-			desiredQueueSize:                   0,
+			desiredQueueSize:                   3, // The corrupted items are dropped once consumed.
 		},
 	}
 
@@ -543,8 +557,6 @@ func TestPersistentQueue_PutCloseReadClose(t *testing.T) {
 	assert.NoError(t, ps.Offer(context.Background(), req))
 	assert.NoError(t, ps.Offer(context.Background(), req))
 	assert.Equal(t, 2, ps.Size())
-	// TODO: Remove this, after the initialization writes the readIndex.
-	_, _, _ = ps.getNextItem(context.Background())
 	assert.NoError(t, ps.Shutdown(context.Background()))
 
 	newPs := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
@@ -565,6 +577,264 @@ func TestPersistentQueue_PutCloseReadClose(t *testing.T) {
 	assert.NoError(t, newPs.Shutdown(context.Background()))
 }
 
+func TestPersistentQueue_ChecksumMismatch(t *testing.T) {
+	req := newTracesRequest(5, 10)
+	reader := sdkmetric.NewManualReader()
+	set := exportertest.NewNopCreateSettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	pq := NewPersistentQueue[tracesRequest](PersistentQueueSettings[tracesRequest]{
+		Sizer:            &RequestSizer[tracesRequest]{},
+		Capacity:         1000,
+		DataType:         component.DataTypeTraces,
+		StorageID:        component.ID{},
+		Marshaler:        marshalTracesRequest,
+		Unmarshaler:      unmarshalTracesRequest,
+		ExporterSettings: set,
+	}).(*persistentQueue[tracesRequest])
+	require.NoError(t, pq.Start(context.Background(), &mockHost{ext: map[component.ID]component.Component{
+		{}: NewMockStorageExtension(nil),
+	}}))
+	t.Cleanup(func() { assert.NoError(t, pq.Shutdown(context.Background())) })
+
+	require.NoError(t, pq.Offer(context.Background(), req))
+	require.NoError(t, pq.Offer(context.Background(), req))
+
+	// Flip a bit in the first stored item.
+	val, err := pq.client.Get(context.Background(), "0")
+	require.NoError(t, err)
+	val[len(val)-1] ^= 1
+	require.NoError(t, pq.client.Set(context.Background(), "0", val))
+
+	// The corrupted item is dropped and the next one is consumed.
+	require.True(t, pq.Consume(func(_ context.Context, traces tracesRequest) error {
+		assert.Equal(t, req, traces)
+		return nil
+	}))
+	assert.Equal(t, 0, pq.Size())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, "exporter/queue_corrupted_items", rm.ScopeMetrics[0].Metrics[0].Name)
+	dps := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints
+	require.Len(t, dps, 1)
+	assert.Equal(t, int64(1), dps[0].Value)
+}
+
+func TestPersistentQueue_ReadItemWithoutChecksum(t *testing.T) {
+	req := newTracesRequest(5, 10)
+	ext := NewMockStorageExtension(nil)
+	client, err := ext.GetClient(context.Background(), component.KindExporter, component.ID{}, component.DataTypeTraces.String())
+	require.NoError(t, err)
+
+	// The item stored by the previous version of the queue.
+	buf, err := marshalTracesRequest(req)
+	require.NoError(t, err)
+	require.NoError(t, client.Batch(context.Background(),
+		storage.SetOperation(writeIndexKey, itemIndexToBytes(1)),
+		storage.SetOperation("0", buf)))
+
+	pq := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+	require.Equal(t, 1, pq.Size())
+	require.True(t, pq.Consume(func(_ context.Context, traces tracesRequest) error {
+		assert.Equal(t, req, traces)
+		return nil
+	}))
+	assert.NoError(t, pq.Shutdown(context.Background()))
+}
+
+func TestPersistentQueue_RecoverIndices(t *testing.T) {
+	cases := []struct {
+		name          string
+		storedItems   []uint64
+		ops           []storage.Operation
+		wantReadIdx   uint64
+		wantWriteIdx  uint64
+		wantQueueSize int
+	}{
+		{
+			name:        "write index not updated after the last write",
+			storedItems: []uint64{0, 1, 2},
+			ops: []storage.Operation{
+				storage.SetOperation(writeIndexKey, itemIndexToBytes(1)),
+			},
+			wantReadIdx:   0,
+			wantWriteIdx:  3,
+			wantQueueSize: 3,
+		},
+		{
+			name:        "read index ahead of write index",
+			storedItems: []uint64{1, 2},
+			ops: []storage.Operation{
+				storage.SetOperation(readIndexKey, itemIndexToBytes(10)),
+				storage.SetOperation(writeIndexKey, itemIndexToBytes(3)),
+			},
+			wantReadIdx:   1,
+			wantWriteIdx:  3,
+			wantQueueSize: 2,
+		},
+		{
+			name:          "indices lost",
+			storedItems:   []uint64{0, 1},
+			wantReadIdx:   0,
+			wantWriteIdx:  2,
+			wantQueueSize: 2,
+		},
+		{
+			name:        "read index corrupted with dispatched item",
+			storedItems: []uint64{4, 5, 6},
+			ops: []storage.Operation{
+				storage.SetOperation(readIndexKey, []byte{1, 2}),
+				storage.SetOperation(writeIndexKey, itemIndexToBytes(7)),
+				storage.SetOperation(currentlyDispatchedItemsKey, itemIndexArrayToBytes([]uint64{4})),
+			},
+			wantReadIdx:   5,
+			wantWriteIdx:  8, // The dispatched item is put back to the queue.
+			wantQueueSize: 3,
+		},
+		{
+			name:        "consistent indices",
+			storedItems: []uint64{3, 4},
+			ops: []storage.Operation{
+				storage.SetOperation(readIndexKey, itemIndexToBytes(3)),
+				storage.SetOperation(writeIndexKey, itemIndexToBytes(5)),
+			},
+			wantReadIdx:   3,
+			wantWriteIdx:  5,
+			wantQueueSize: 2,
+		},
+	}
+
+	req := newTracesRequest(1, 1)
+	buf, err := marshalTracesRequest(req)
+	require.NoError(t, err)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ext := NewMockStorageExtension(nil)
+			client, err := ext.GetClient(context.Background(), component.KindExporter, component.ID{},
+				component.DataTypeTraces.String())
+			require.NoError(t, err)
+			ops := c.ops
+			for _, idx := range c.storedItems {
+				ops = append(ops, storage.SetOperation(getItemKey(idx), itemWithChecksum(buf)))
+			}
+			require.NoError(t, client.Batch(context.Background(), ops...))
+
+			pq := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+			assert.Equal(t, c.wantReadIdx, pq.readIndex)
+			assert.Equal(t, c.wantWriteIdx, pq.writeIndex)
+			assert.Equal(t, c.wantQueueSize, pq.Size())
+			for i := 0; i < c.wantQueueSize; i++ {
+				require.True(t, pq.Consume(func(_ context.Context, traces tracesRequest) error {
+					assert.Equal(t, req, traces)
+					return nil
+				}))
+			}
+			assert.Equal(t, 0, pq.Size())
+			assert.NoError(t, pq.Shutdown(context.Background()))
+		})
+	}
+}
+
+// countingStorageClient counts the items looked up with the Get method.
+type countingStorageClient struct {
+	storage.Client
+	gets int
+}
+
+func (c *countingStorageClient) Get(ctx context.Context, key string) ([]byte, error) {
+	c.gets++
+	return c.Client.Get(ctx, key)
+}
+
+func TestPersistentQueue_RecoverIndicesLookups(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	client, err := ext.GetClient(context.Background(), component.KindExporter, component.ID{},
+		component.DataTypeTraces.String())
+	require.NoError(t, err)
+	buf, err := marshalTracesRequest(newTracesRequest(1, 1))
+	require.NoError(t, err)
+
+	// The read index is corrupted, it's recovered without reading all the stored items.
+	ops := []storage.Operation{
+		storage.SetOperation(readIndexKey, []byte{1}),
+		storage.SetOperation(writeIndexKey, itemIndexToBytes(1_100)),
+	}
+	for i := uint64(100); i < 1_100; i++ {
+		ops = append(ops, storage.SetOperation(getItemKey(i), itemWithChecksum(buf)))
+	}
+	require.NoError(t, client.Batch(context.Background(), ops...))
+
+	countingClient := &countingStorageClient{Client: client}
+	pq := createTestPersistentQueueWithClient(countingClient)
+	assert.Equal(t, uint64(100), pq.readIndex)
+	assert.Equal(t, uint64(1_100), pq.writeIndex)
+	assert.Less(t, countingClient.gets, 100)
+	assert.NoError(t, pq.Shutdown(context.Background()))
+}
+
+func TestPersistentQueue_CorruptedItemReleasesCapacity(t *testing.T) {
+	req := newTracesRequest(5, 10)
+	pq := createTestPersistentQueueWithItemsCapacity(t, NewMockStorageExtension(nil), 1000)
+	require.NoError(t, pq.Offer(context.Background(), req))
+	require.NoError(t, pq.Offer(context.Background(), req))
+	assert.Equal(t, 100, pq.Size())
+
+	// Flip a bit in the first stored item.
+	val, err := pq.client.Get(context.Background(), "0")
+	require.NoError(t, err)
+	val[len(val)-1] ^= 1
+	require.NoError(t, pq.client.Set(context.Background(), "0", val))
+
+	// The size of the dropped item is released as well as the size of the consumed one.
+	require.True(t, pq.Consume(func(context.Context, tracesRequest) error { return nil }))
+	assert.Equal(t, 0, pq.Size())
+	assert.NoError(t, pq.Shutdown(context.Background()))
+}
+
+func TestPersistentQueue_MissingItemDropped(t *testing.T) {
+	req := newTracesRequest(5, 10)
+	ext := NewMockStorageExtension(nil)
+	pq := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, pq.Offer(context.Background(), req))
+	}
+	require.NoError(t, pq.client.Delete(context.Background(), "1"))
+
+	numConsumed := 0
+	for i := 0; i < 2; i++ {
+		require.True(t, pq.Consume(func(context.Context, tracesRequest) error {
+			numConsumed++
+			return nil
+		}))
+	}
+	assert.Equal(t, 2, numConsumed)
+	assert.Equal(t, 0, pq.Size())
+	assert.NoError(t, pq.Shutdown(context.Background()))
+}
+
+func TestItemChecksum(t *testing.T) {
+	payload := []byte("payload")
+	buf := itemWithChecksum(payload)
+	res, err := itemPayload(buf)
+	require.NoError(t, err)
+	assert.Equal(t, payload, res)
+
+	// The items without the header are returned as is.
+	res, err = itemPayload(payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, res)
+
+	buf[len(buf)-1] = 'x'
+	_, err = itemPayload(buf)
+	assert.ErrorIs(t, err, errChecksumMismatch)
+
+	_, err = itemPayload(itemHeader)
+	assert.ErrorIs(t, err, errInvalidValue)
+}
+
 func BenchmarkPersistentQueue_TraceSpans(b *testing.B) {
 	cases := []struct {
 		numTraces        int
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"context"
+)
+
+// LanedQueue is implemented by the queues keeping the requests of different priorities in separate lanes.
+type LanedQueue interface {
+	// VisitLanes calls the given function with the name and the size of every lane.
+	VisitLanes(func(name string, size int))
+}
+
+// PriorityLane defines a lane of the priority queue.
+type PriorityLane struct {
+	Name string
+	// Weight is the number of requests consumed from the lane in a row in the weighted mode.
+	Weight int
+	// Capacity is the capacity of the lane, zero means that the lane is limited by the queue capacity only.
+	Capacity int
+}
+
+// PriorityMemoryQueueSettings defines internal parameters for priorityMemoryQueue creation.
+type PriorityMemoryQueueSettings[T any] struct {
+	Sizer    Sizer[T]
+	Capacity int
+	// Lanes is the list of lanes ordered from the highest priority to the lowest.
+	Lanes []PriorityLane
+	// Strict indicates whether a lane is consumed only when all the higher priority lanes are empty.
+	// Otherwise, the lanes are consumed in the weighted round-robin order.
+	Strict bool
+	// Priority returns the index of the lane the request is put in. The requests with the index
+	// out of the lanes range are put in the lowest priority lane.
+	Priority func(context.Context, T) int
+}
+
+// priorityMemoryQueue is an in-memory queue that keeps the requests in lanes of different priorities,
+// so the high priority requests are exported first when the queue is backed up by the bulk data.
+type priorityMemoryQueue[T any] struct {
+	*lanedMemoryQueue[T]
+	priority func(context.Context, T) int
+}
+
+// NewPriorityMemoryQueue constructs a new in-memory queue with the given priority lanes.
+func NewPriorityMemoryQueue[T any](set PriorityMemoryQueueSettings[T]) Queue[T] {
+	q := &priorityMemoryQueue[T]{
+		lanedMemoryQueue: newLanedMemoryQueue[T](set.Sizer, set.Capacity, set.Strict),
+		priority:         set.Priority,
+	}
+	for _, l := range set.Lanes {
+		q.lanes = append(q.lanes, &lane[T]{name: l.Name, weight: max(l.Weight, 1), capacity: l.Capacity})
+	}
+	return q
+}
+
+// Offer puts the request in the lane selected by the priority function.
+func (q *priorityMemoryQueue[T]) Offer(ctx context.Context, req T) error {
+	idx := q.priority(ctx, req)
+	if idx < 0 || idx >= len(q.lanes) {
+		idx = len(q.lanes) - 1
+	}
+	reqSize := q.queueCapacityLimiter.sizeOf(req)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.put(ctx, q.lanes[idx], req, reqSize)
+}
+
+// VisitLanes implements LanedQueue.
+func (q *priorityMemoryQueue[T]) VisitLanes(visit func(name string, size int)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, l := range q.lanes {
+		visit(l.name, l.size)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// priorityByPrefix puts the items starting with "h" in the first lane and the others in the second one.
+func priorityByPrefix(_ context.Context, item string) int {
+	if strings.HasPrefix(item, "h") {
+		return 0
+	}
+	return 1
+}
+
+func TestPriorityQueue_Strict(t *testing.T) {
+	q := NewPriorityMemoryQueue[string](PriorityMemoryQueueSettings[string]{
+		Sizer:    &RequestSizer[string]{},
+		Capacity: 100,
+		Lanes:    []PriorityLane{{Name: "high"}, {Name: "low"}},
+		Strict:   true,
+		Priority: priorityByPrefix,
+	})
+
+	for _, item := range []string{"l1", "l2", "h1", "l3", "h2"} {
+		require.NoError(t, q.Offer(context.Background(), item))
+	}
+	assert.Equal(t, 5, q.Size())
+
+	assert.Equal(t, []string{"h1", "h2", "l1", "l2", "l3"}, consumeAll(t, q))
+}
+
+func TestPriorityQueue_Weighted(t *testing.T) {
+	q := NewPriorityMemoryQueue[string](PriorityMemoryQueueSettings[string]{
+		Sizer:    &RequestSizer[string]{},
+		Capacity: 100,
+		Lanes:    []PriorityLane{{Name: "high", Weight: 2}, {Name: "low"}},
+		Priority: priorityByPrefix,
+	})
+
+	for _, item := range []string{"l1", "l2", "l3", "h1", "h2", "h3"} {
+		require.NoError(t, q.Offer(context.Background(), item))
+	}
+
+	// The low priority lane is not starved.
+	assert.Equal(t, []string{"h1", "h2", "l1", "h3", "l2", "l3"}, consumeAll(t, q))
+}
+
+func TestPriorityQueue_OutOfRangePriority(t *testing.T) {
+	q := NewPriorityMemoryQueue[string](PriorityMemoryQueueSettings[string]{
+		Sizer:    &RequestSizer[string]{},
+		Capacity: 100,
+		Lanes:    []PriorityLane{{Name: "high"}, {Name: "low"}},
+		Strict:   true,
+		Priority: func(_ context.Context, item string) int {
+			if item == "h" {
+				return 0
+			}
+			return -1
+		},
+	})
+	require.NoError(t, q.Offer(context.Background(), "x"))
+	require.NoError(t, q.Offer(context.Background(), "h"))
+
+	sizes := map[string]int{}
+	q.(LanedQueue).VisitLanes(func(name string, size int) {
+		sizes[name] = size
+	})
+	assert.Equal(t, map[string]int{"high": 1, "low": 1}, sizes)
+	assert.Equal(t, []string{"h", "x"}, consumeAll(t, q))
+}
+
+func TestPriorityQueue_LaneCapacity(t *testing.T) {
+	q := NewPriorityMemoryQueue[string](PriorityMemoryQueueSettings[string]{
+		Sizer:    &RequestSizer[string]{},
+		Capacity: 3,
+		Lanes:    []PriorityLane{{Name: "high"}, {Name: "low", Capacity: 2}},
+		Strict:   true,
+		Priority: priorityByPrefix,
+	})
+
+	// The bulk data cannot take the capacity reserved for the high priority data.
+	require.NoError(t, q.Offer(context.Background(), "l1"))
+	require.NoError(t, q.Offer(context.Background(), "l2"))
+	assert.ErrorIs(t, q.Offer(context.Background(), "l3"), ErrQueueIsFull)
+	require.NoError(t, q.Offer(context.Background(), "h1"))
+
+	// The whole queue capacity is still respected.
+	assert.ErrorIs(t, q.Offer(context.Background(), "h2"), ErrQueueIsFull)
+}
+
+func TestPriorityQueue_ShutdownDrains(t *testing.T) {
+	q := NewPriorityMemoryQueue[string](PriorityMemoryQueueSettings[string]{
+		Sizer:    &RequestSizer[string]{},
+		Capacity: 100,
+		Lanes:    []PriorityLane{{Name: "high"}, {Name: "low"}},
+		Priority: priorityByPrefix,
+	})
+	require.NoError(t, q.Offer(context.Background(), "l1"))
+	require.NoError(t, q.Offer(context.Background(), "h1"))
+	require.NoError(t, q.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"h1", "l1"}, consumeAll(t, q))
+	assert.False(t, q.Consume(func(context.Context, string) error { return nil }))
+}
@@ -11,6 +11,10 @@ type itemsCounter interface {
 	ItemsCount() int
 }
 
+type bytesCounter interface {
+	BytesSize() int
+}
+
 // Sizer is an interface that returns the size of the given element.
 type Sizer[T any] interface {
 	SizeOf(T) uint64
@@ -23,6 +27,18 @@ func (is *ItemsSizer[T]) SizeOf(el T) uint64 {
 	return uint64(el.ItemsCount())
 }
 
+// BytesSizer is a Sizer implementation that returns the size of a queue element as the number of bytes it takes
+// in the serialized form. Elements that don't implement the BytesSize method are sized as zero bytes,
+// so the queue creator must make sure that only elements reporting their size can be offered to the queue.
+type BytesSizer[T any] struct{}
+
+func (bs *BytesSizer[T]) SizeOf(el T) uint64 {
+	if bc, ok := any(el).(bytesCounter); ok {
+		return uint64(bc.BytesSize())
+	}
+	return 0
+}
+
 // RequestSizer is a Sizer implementation that returns the size of a queue element as one request.
 type RequestSizer[T any] struct{}
 
@@ -44,20 +60,19 @@ func (bcl queueCapacityLimiter[T]) Size() int {
 	return int(bcl.used.Load())
 }
 
-func (bcl queueCapacityLimiter[T]) claim(el T) bool {
-	size := bcl.sizeOf(el)
+// claim claims the capacity for an element of the given size, as returned by sizeOf, and returns false
+// if the queue doesn't have enough capacity left. The size is computed once by the caller and kept with the
+// element, so the same size is released even if the size of the element changes while it is in the queue.
+func (bcl queueCapacityLimiter[T]) claim(size uint64) bool {
 	if bcl.used.Add(size) > bcl.cap {
-		bcl.releaseSize(size)
+		bcl.release(size)
 		return false
 	}
 	return true
 }
 
-func (bcl queueCapacityLimiter[T]) release(el T) {
-	bcl.releaseSize(bcl.sizeOf(el))
-}
-
-func (bcl queueCapacityLimiter[T]) releaseSize(size uint64) {
+// release releases the capacity claimed for an element of the given size.
+func (bcl queueCapacityLimiter[T]) release(size uint64) {
 	bcl.used.Add(^(size - 1))
 }
 
@@ -16,16 +16,16 @@ func TestRequestsCapacityLimiter(t *testing.T) {
 
 	req := fakeReq{itemsCount: 5}
 
-	assert.True(t, rl.claim(req))
+	assert.True(t, rl.claim(rl.sizeOf(req)))
 	assert.Equal(t, 1, rl.Size())
 
-	assert.True(t, rl.claim(req))
+	assert.True(t, rl.claim(rl.sizeOf(req)))
 	assert.Equal(t, 2, rl.Size())
 
-	assert.False(t, rl.claim(req))
+	assert.False(t, rl.claim(rl.sizeOf(req)))
 	assert.Equal(t, 2, rl.Size())
 
-	rl.release(req)
+	rl.release(rl.sizeOf(req))
 	assert.Equal(t, 1, rl.Size())
 }
 
@@ -36,16 +36,16 @@ func TestItemsCapacityLimiter(t *testing.T) {
 
 	req := fakeReq{itemsCount: 3}
 
-	assert.True(t, rl.claim(req))
+	assert.True(t, rl.claim(rl.sizeOf(req)))
 	assert.Equal(t, 3, rl.Size())
 
-	assert.True(t, rl.claim(req))
+	assert.True(t, rl.claim(rl.sizeOf(req)))
 	assert.Equal(t, 6, rl.Size())
 
-	assert.False(t, rl.claim(req))
+	assert.False(t, rl.claim(rl.sizeOf(req)))
 	assert.Equal(t, 6, rl.Size())
 
-	rl.release(req)
+	rl.release(rl.sizeOf(req))
 	assert.Equal(t, 3, rl.Size())
 }
 
@@ -56,3 +56,31 @@ type fakeReq struct {
 func (r fakeReq) ItemsCount() int {
 	return r.itemsCount
 }
+
+func TestBytesCapacityLimiter(t *testing.T) {
+	rl := newQueueCapacityLimiter[fakeSizedReq](&BytesSizer[fakeSizedReq]{}, 100)
+	assert.Equal(t, 0, rl.Size())
+	assert.Equal(t, 100, rl.Capacity())
+
+	req := fakeSizedReq{bytesSize: 40}
+
+	assert.True(t, rl.claim(rl.sizeOf(req)))
+	assert.Equal(t, 40, rl.Size())
+
+	assert.True(t, rl.claim(rl.sizeOf(req)))
+	assert.Equal(t, 80, rl.Size())
+
+	assert.False(t, rl.claim(rl.sizeOf(req)))
+	assert.Equal(t, 80, rl.Size())
+
+	rl.release(rl.sizeOf(req))
+	assert.Equal(t, 40, rl.Size())
+}
+
+type fakeSizedReq struct {
+	bytesSize int
+}
+
+func (r fakeSizedReq) BytesSize() int {
+	return r.bytesSize
+}
@@ -17,6 +17,8 @@ type Config struct {
 	exporterhelper.TimeoutSettings `mapstructure:",squash"`     // squash ensures fields are correctly decoded in embedded struct.
 	QueueConfig                    exporterhelper.QueueSettings `mapstructure:"sending_queue"`
 	RetryConfig                    configretry.BackOffConfig    `mapstructure:"retry_on_failure"`
+	// RetryBudgetConfig limits the retries across all the requests sent by the exporter.
+	RetryBudgetConfig exporterhelper.RetryBudgetSettings `mapstructure:"retry_budget"`
 
 	configgrpc.ClientConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 }
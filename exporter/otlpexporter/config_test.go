@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
 )
 
 func TestUnmarshalDefaultConfig(t *testing.T) {
@@ -48,10 +49,19 @@ func TestUnmarshalConfig(t *testing.T) {
 				MaxInterval:         1 * time.Minute,
 				MaxElapsedTime:      10 * time.Minute,
 			},
+			RetryBudgetConfig: exporterhelper.RetryBudgetSettings{
+				MaxRetryRatio:       0.2,
+				MinRetriesPerSecond: 5,
+				Window:              30 * time.Second,
+			},
 			QueueConfig: exporterhelper.QueueSettings{
-				Enabled:      true,
-				NumConsumers: 2,
-				QueueSize:    10,
+				Enabled:             true,
+				NumConsumers:        2,
+				QueueSize:           10,
+				Sizer:               exporterqueue.SizerTypeRequests,
+				AdaptiveConcurrency: exporterqueue.NewDefaultAdaptiveConcurrencyConfig(),
+				Partition:           exporterqueue.NewDefaultPartitionConfig(),
+				Priority:            exporterqueue.NewDefaultPriorityConfig(),
 			},
 			ClientConfig: configgrpc.ClientConfig{
 				Headers: map[string]configopaque.String{
@@ -322,6 +322,42 @@ func TestSendTraces(t *testing.T) {
 	assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
 }
 
+func TestSendTracesOnUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	// Start an OTLP-compatible receiver on a Unix domain socket.
+	socketPath := filepath.Join(t.TempDir(), "otlp.sock")
+	ln, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	rcv, _ := otlpTracesReceiverOnGRPCServer(ln, false)
+	// Also closes the connection.
+	defer rcv.srv.GracefulStop()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.QueueConfig.Enabled = false
+	cfg.ClientConfig = configgrpc.ClientConfig{
+		Endpoint: "unix://" + socketPath,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	exp, err := factory.CreateTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exp)
+	defer func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	}()
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	td := testdata.GenerateTraces(2)
+	require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+	assert.EqualValues(t, 1, rcv.requestCount.Load())
+	assert.EqualValues(t, 2, rcv.totalItems.Load())
+	assert.EqualValues(t, td, rcv.getLastRequest())
+}
+
 func TestSendTracesWhenEndpointHasHttpScheme(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -48,6 +48,8 @@ type Config struct {
 	confighttp.ClientConfig `mapstructure:",squash"`     // squash ensures fields are correctly decoded in embedded struct.
 	QueueConfig             exporterhelper.QueueSettings `mapstructure:"sending_queue"`
 	RetryConfig             configretry.BackOffConfig    `mapstructure:"retry_on_failure"`
+	// RetryBudgetConfig limits the retries across all the requests sent by the exporter.
+	RetryBudgetConfig exporterhelper.RetryBudgetSettings `mapstructure:"retry_budget"`
 
 	// The URL to send traces to. If omitted the Endpoint + "/v1/traces" will be used.
 	TracesEndpoint string `mapstructure:"traces_endpoint"`
@@ -34,9 +34,10 @@ func NewFactory() exporter.Factory {
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		RetryConfig: configretry.NewDefaultBackOffConfig(),
-		QueueConfig: exporterhelper.NewDefaultQueueSettings(),
-		Encoding:    EncodingProto,
+		RetryConfig:       configretry.NewDefaultBackOffConfig(),
+		RetryBudgetConfig: exporterhelper.NewDefaultRetryBudgetSettings(),
+		QueueConfig:       exporterhelper.NewDefaultQueueSettings(),
+		Encoding:          EncodingProto,
 		ClientConfig: confighttp.ClientConfig{
 			Endpoint: "",
 			Timeout:  30 * time.Second,
@@ -90,6 +91,7 @@ func createTracesExporter(
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithRetryBudget(oCfg.RetryBudgetConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig))
 }
 
@@ -116,6 +118,7 @@ func createMetricsExporter(
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithRetryBudget(oCfg.RetryBudgetConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig))
 }
 
@@ -142,5 +145,6 @@ func createLogsExporter(
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithRetryBudget(oCfg.RetryBudgetConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig))
 }
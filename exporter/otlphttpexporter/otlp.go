@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/protobuf/proto"
 
@@ -198,23 +199,43 @@ func (e *baseExporter) export(ctx context.Context, url string, request []byte, p
 	if isRetryableStatusCode(resp.StatusCode) {
 		// A retry duration of 0 seconds will trigger the default backoff policy
 		// of our caller (retry handler).
-		retryAfter := 0
-
-		// Check if the server is overwhelmed.
-		// See spec https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/protocol/otlp.md#otlphttp-throttling
-		isThrottleError := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
-		if val := resp.Header.Get(headerRetryAfter); isThrottleError && val != "" {
-			if seconds, err2 := strconv.Atoi(val); err2 == nil {
-				retryAfter = seconds
-			}
-		}
-
-		return exporterhelper.NewThrottleRetry(formattedErr, time.Duration(retryAfter)*time.Second)
+		return exporterhelper.NewThrottleRetry(formattedErr, getThrottleDuration(resp, respStatus))
 	}
 
 	return consumererror.NewPermanent(formattedErr)
 }
 
+// getThrottleDuration returns the delay requested by the server with the Retry-After header, or with
+// the RetryInfo detail of the response status the same way as the gRPC servers do, or 0 if none is set.
+func getThrottleDuration(resp *http.Response, respStatus *status.Status) time.Duration {
+	// Check if the server is overwhelmed.
+	// See spec https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/protocol/otlp.md#otlphttp-throttling
+	isThrottleError := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+	if val := resp.Header.Get(headerRetryAfter); isThrottleError && val != "" {
+		// The header value is either a number of seconds or an HTTP date.
+		if seconds, err := strconv.Atoi(val); err == nil {
+			return time.Duration(max(seconds, 0)) * time.Second
+		}
+		if date, err := http.ParseTime(val); err == nil {
+			return max(time.Until(date), 0)
+		}
+	}
+
+	if respStatus == nil {
+		return 0
+	}
+	for _, detail := range respStatus.Details {
+		retryInfo := &errdetails.RetryInfo{}
+		if !detail.MessageIs(retryInfo) || detail.UnmarshalTo(retryInfo) != nil {
+			continue
+		}
+		if delay := retryInfo.GetRetryDelay().AsDuration(); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
 // Determine if the status code is retryable according to the specification.
 // For more, see https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/protocol/otlp.md#failures-1
 func isRetryableStatusCode(code int) bool {
@@ -9,8 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -19,9 +22,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
 	codes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -217,6 +223,82 @@ func TestErrorResponseInvalidResponseBody(t *testing.T) {
 	assert.Nil(t, status)
 }
 
+func TestThrottleDuration(t *testing.T) {
+	retryInfoStatus, err := status.New(codes.Unavailable, "Server overloaded").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(10 * time.Second)})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		respStatus *spb.Status
+		wantMin    time.Duration
+		wantMax    time.Duration
+	}{
+		{
+			name:       "no throttling information",
+			statusCode: http.StatusServiceUnavailable,
+			respStatus: status.New(codes.Unavailable, "Server overloaded").Proto(),
+		},
+		{
+			name:       "Retry-After seconds",
+			statusCode: http.StatusTooManyRequests,
+			retryAfter: "30",
+			wantMin:    30 * time.Second,
+			wantMax:    30 * time.Second,
+		},
+		{
+			name:       "Retry-After date",
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat),
+			wantMin:    58 * time.Second,
+			wantMax:    time.Minute,
+		},
+		{
+			name:       "Retry-After date in the past",
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat),
+		},
+		{
+			name:       "Retry-After invalid",
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: "soon",
+		},
+		{
+			name:       "Retry-After ignored for not throttling status",
+			statusCode: http.StatusBadGateway,
+			retryAfter: "30",
+		},
+		{
+			name:       "RetryInfo",
+			statusCode: http.StatusBadGateway,
+			respStatus: retryInfoStatus.Proto(),
+			wantMin:    10 * time.Second,
+			wantMax:    10 * time.Second,
+		},
+		{
+			name:       "Retry-After takes precedence over RetryInfo",
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: "30",
+			respStatus: retryInfoStatus.Proto(),
+			wantMin:    30 * time.Second,
+			wantMax:    30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set(headerRetryAfter, tt.retryAfter)
+			}
+			delay := getThrottleDuration(resp, tt.respStatus)
+			assert.GreaterOrEqual(t, delay, tt.wantMin)
+			assert.LessOrEqual(t, delay, tt.wantMax)
+		})
+	}
+}
+
 func TestUserAgent(t *testing.T) {
 	set := exportertest.NewNopCreateSettings()
 	set.BuildInfo.Description = "Collector"
@@ -348,6 +430,36 @@ func TestUserAgent(t *testing.T) {
 	})
 }
 
+func TestUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	socketPath := filepath.Join(t.TempDir(), "otlp.sock")
+	ln, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/v1/traces", request.URL.Path)
+		writer.WriteHeader(200)
+	}))
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	cfg := &Config{
+		Encoding: EncodingProto,
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: "unix://" + socketPath,
+		},
+	}
+	exp, err := createTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, exp.Shutdown(context.Background()))
+	})
+	assert.NoError(t, exp.ConsumeTraces(context.Background(), ptrace.NewTraces()))
+}
+
 func TestPartialSuccessInvalidBody(t *testing.T) {
 	cfg := createDefaultConfig()
 	set := exportertest.NewNopCreateSettings()
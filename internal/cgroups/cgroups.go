@@ -53,6 +53,12 @@ const (
 	// _cgroupv2MemoryMax is the file name for the CGroup-V2 Memory max
 	// parameter.
 	_cgroupv2MemoryMax = "memory.max"
+	// _cgroupv2MemoryCurrent is the file name for the CGroup-V2 Memory current
+	// usage parameter.
+	_cgroupv2MemoryCurrent = "memory.current"
+	// _cgroupv2MemoryEvents is the file name for the CGroup-V2 Memory events
+	// counters.
+	_cgroupv2MemoryEvents = "memory.events"
 	// _cgroupFSType is the Linux CGroup-V2 file system type used in
 	// `/proc/$PID/mountinfo`.
 	_cgroupv2FSType = "cgroup2"
@@ -180,3 +186,58 @@ func memoryQuotaV2(cgroupv2MountPoint, cgroupv2MemoryMax string) (int64, bool, e
 	}
 	return -1, false, io.ErrUnexpectedEOF
 }
+
+// MemoryCurrentV2 returns the total memory usage of the cgroup of the process,
+// including the page cache. It is a result of cgroupv2 `memory.current`. If the
+// file doesn't exist, e.g. for the root cgroup or with cgroup v1, the method
+// returns `(-1, false, nil)`.
+func MemoryCurrentV2() (int64, bool, error) {
+	return memoryCurrentV2(_cgroupv2MountPoint, _cgroupv2MemoryCurrent)
+}
+
+func memoryCurrentV2(cgroupv2MountPoint, cgroupv2MemoryCurrent string) (int64, bool, error) {
+	current, err := NewCGroup(cgroupv2MountPoint).readInt(cgroupv2MemoryCurrent)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, false, nil
+		}
+		return -1, false, err
+	}
+	return current, true, nil
+}
+
+// MemoryEventsV2 returns the memory events counters of the cgroup of the process,
+// e.g. `high`, `max`, `oom` and `oom_kill`. It is a result of cgroupv2 `memory.events`.
+// If the file doesn't exist, the method returns `(nil, false, nil)`.
+func MemoryEventsV2() (map[string]int64, bool, error) {
+	return memoryEventsV2(_cgroupv2MountPoint, _cgroupv2MemoryEvents)
+}
+
+func memoryEventsV2(cgroupv2MountPoint, cgroupv2MemoryEvents string) (map[string]int64, bool, error) {
+	memoryEvents, err := os.Open(filepath.Clean(filepath.Join(cgroupv2MountPoint, cgroupv2MemoryEvents)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	defer memoryEvents.Close()
+
+	events := map[string]int64{}
+	scanner := bufio.NewScanner(memoryEvents)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			return nil, false, cgroupMemoryEventsFormatInvalidError{scanner.Text()}
+		}
+		count, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, false, err
+		}
+		events[fields[0]] = count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
+	return events, true, nil
+}
@@ -225,3 +225,106 @@ func TestCGroupsMemoryQuotaV2(t *testing.T) {
 		}
 	}
 }
+
+func TestCGroupsMemoryCurrentV2(t *testing.T) {
+	testTable := []struct {
+		name            string
+		expectedCurrent int64
+		expectedDefined bool
+		shouldHaveError bool
+	}{
+		{
+			name:            "memory",
+			expectedCurrent: int64(123456789),
+			expectedDefined: true,
+			shouldHaveError: false,
+		},
+		{
+			name:            "undefined",
+			expectedCurrent: int64(-1),
+			expectedDefined: false,
+			shouldHaveError: false,
+		},
+		{
+			name:            "invalid",
+			expectedCurrent: int64(-1),
+			expectedDefined: false,
+			shouldHaveError: true,
+		},
+		{
+			name:            "empty",
+			expectedCurrent: int64(-1),
+			expectedDefined: false,
+			shouldHaveError: true,
+		},
+	}
+
+	cgroupBasePath := filepath.Join(testDataCGroupsPath, "v2")
+	for _, tt := range testTable {
+		cgroupPath := filepath.Join(cgroupBasePath, tt.name)
+		current, defined, err := memoryCurrentV2(cgroupPath, "memory.current")
+		assert.Equal(t, tt.expectedCurrent, current, tt.name)
+		assert.Equal(t, tt.expectedDefined, defined, tt.name)
+
+		if tt.shouldHaveError {
+			assert.Error(t, err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+}
+
+func TestCGroupsMemoryEventsV2(t *testing.T) {
+	testTable := []struct {
+		name            string
+		expectedEvents  map[string]int64
+		expectedDefined bool
+		shouldHaveError bool
+	}{
+		{
+			name: "memory",
+			expectedEvents: map[string]int64{
+				"low":            0,
+				"high":           12,
+				"max":            3,
+				"oom":            1,
+				"oom_kill":       0,
+				"oom_group_kill": 0,
+			},
+			expectedDefined: true,
+			shouldHaveError: false,
+		},
+		{
+			name:            "undefined",
+			expectedEvents:  nil,
+			expectedDefined: false,
+			shouldHaveError: false,
+		},
+		{
+			name:            "invalid",
+			expectedEvents:  nil,
+			expectedDefined: false,
+			shouldHaveError: true,
+		},
+		{
+			name:            "empty",
+			expectedEvents:  map[string]int64{},
+			expectedDefined: true,
+			shouldHaveError: false,
+		},
+	}
+
+	cgroupBasePath := filepath.Join(testDataCGroupsPath, "v2")
+	for _, tt := range testTable {
+		cgroupPath := filepath.Join(cgroupBasePath, tt.name)
+		events, defined, err := memoryEventsV2(cgroupPath, "memory.events")
+		assert.Equal(t, tt.expectedEvents, events, tt.name)
+		assert.Equal(t, tt.expectedDefined, defined, tt.name)
+
+		if tt.shouldHaveError {
+			assert.Error(t, err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+}
@@ -37,6 +37,10 @@ type mountPointFormatInvalidError struct {
 	line string
 }
 
+type cgroupMemoryEventsFormatInvalidError struct {
+	line string
+}
+
 type pathNotExposedFromMountPointError struct {
 	mountPoint string
 	root       string
@@ -51,6 +55,10 @@ func (err mountPointFormatInvalidError) Error() string {
 	return fmt.Sprintf("invalid format for MountPoint: %q", err.line)
 }
 
+func (err cgroupMemoryEventsFormatInvalidError) Error() string {
+	return fmt.Sprintf("invalid format for memory events: %q", err.line)
+}
+
 func (err pathNotExposedFromMountPointError) Error() string {
 	return fmt.Sprintf("path %q is not a descendant of mount point root %q and cannot be exposed from %q", err.path, err.root, err.mountPoint)
 }
@@ -0,0 +1,14 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iruntime // import "go.opentelemetry.io/collector/internal/iruntime"
+
+// CgroupMemoryStats is the memory usage and pressure of the cgroup of the process.
+type CgroupMemoryStats struct {
+	// Current is the total memory usage of the cgroup, including the page cache.
+	Current uint64
+	// Limit is the memory limit of the cgroup, zero if the memory is not limited.
+	Limit uint64
+	// Events are the cumulative memory events counters of the cgroup, e.g. "high", "max" and "oom".
+	Events map[string]uint64
+}
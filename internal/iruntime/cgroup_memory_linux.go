@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package iruntime // import "go.opentelemetry.io/collector/internal/iruntime"
+
+import "go.opentelemetry.io/collector/internal/cgroups"
+
+// ReadCgroupMemoryStats returns the memory usage and pressure of the cgroup of the process.
+// This implementation is meant for linux and reads the cgroup v2 `memory.current`,
+// `memory.max` and `memory.events` files, the returned bool is false if they are not
+// available, e.g. with cgroup v1.
+func ReadCgroupMemoryStats() (CgroupMemoryStats, bool, error) {
+	current, defined, err := cgroups.MemoryCurrentV2()
+	if err != nil || !defined {
+		return CgroupMemoryStats{}, false, err
+	}
+	events, defined, err := cgroups.MemoryEventsV2()
+	if err != nil || !defined {
+		return CgroupMemoryStats{}, false, err
+	}
+
+	limit, defined, err := cgroups.MemoryQuotaV2()
+	if err != nil {
+		return CgroupMemoryStats{}, false, err
+	}
+
+	stats := CgroupMemoryStats{
+		Current: uint64(current),
+		Events:  make(map[string]uint64, len(events)),
+	}
+	if defined {
+		stats.Limit = uint64(limit)
+	}
+	for k, v := range events {
+		stats.Events[k] = uint64(v)
+	}
+	return stats, true, nil
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package iruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCgroupMemoryStats(t *testing.T) {
+	stats, ok, err := ReadCgroupMemoryStats()
+	require.NoError(t, err)
+	if !ok {
+		t.Skip("cgroup v2 memory stats are not available")
+	}
+	assert.True(t, stats.Current > 0)
+	assert.NotNil(t, stats.Events)
+}
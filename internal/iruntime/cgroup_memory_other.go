@@ -0,0 +1,12 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package iruntime // import "go.opentelemetry.io/collector/internal/iruntime"
+
+// ReadCgroupMemoryStats returns the memory usage and pressure of the cgroup of the process.
+// Cgroups are not available on non-linux platforms, so the returned bool is always false.
+func ReadCgroupMemoryStats() (CgroupMemoryStats, bool, error) {
+	return CgroupMemoryStats{}, false, nil
+}
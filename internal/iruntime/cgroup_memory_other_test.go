@@ -0,0 +1,18 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package iruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCgroupMemoryStats(t *testing.T) {
+	_, ok, err := ReadCgroupMemoryStats()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -17,6 +17,10 @@ var (
 	errSpikeLimitPercentageOutOfRange = errors.New("'spike_limit_percentage' must be smaller than 'limit_percentage'")
 	errLimitPercentageOutOfRange      = errors.New(
 		"'limit_percentage' and 'spike_limit_percentage' must be greater than zero and less than or equal to hundred")
+	errShedLowWatermarkOutOfRange           = errors.New("'shed_low_watermark_mib' must be smaller than 'limit_mib'")
+	errShedLowWatermarkPercentageOutOfRange = errors.New("'shed_low_watermark_percentage' must be smaller than 'limit_percentage'")
+	errShedLowWatermarkWithLimitPercentage  = errors.New("'shed_low_watermark_mib' must be used with 'limit_mib'")
+	errShedLowWatermarkPercentageWithLimit  = errors.New("'shed_low_watermark_percentage' must be used with 'limit_percentage', not with 'limit_mib'")
 )
 
 // Config defines configuration for memory memoryLimiter processor.
@@ -41,6 +45,30 @@ type Config struct {
 	// MemorySpikePercentage is the maximum, in percents against the total memory,
 	// spike expected between the measurements of memory usage.
 	MemorySpikePercentage uint32 `mapstructure:"spike_limit_percentage"`
+
+	// UseGoMemLimit enables the mode where the Go runtime soft memory limit (GOMEMLIMIT)
+	// is set to the soft limit and adjusted when the limits change, so the garbage
+	// collector keeps the memory usage under the soft limit instead of being forced.
+	// In this mode, the memory pressure of the cgroup v2 of the process, when available,
+	// is also used to refuse data.
+	UseGoMemLimit bool `mapstructure:"use_gomemlimit"`
+
+	// ShedLowWatermarkMiB enables the gradual load shedding: above this memory usage, in MiB,
+	// the processor refuses a growing fraction of the data, from none at the watermark to all
+	// of it at the hard limit, instead of refusing everything once the soft limit is reached.
+	// The soft limit still forces a GC. It must be smaller than MemoryLimitMiB. Defaults to
+	// zero, so the gradual load shedding is disabled.
+	ShedLowWatermarkMiB uint32 `mapstructure:"shed_low_watermark_mib"`
+
+	// ShedLowWatermarkPercentage is the low watermark of the gradual load shedding, in % of
+	// the total memory, used with MemoryLimitPercentage.
+	ShedLowWatermarkPercentage uint32 `mapstructure:"shed_low_watermark_percentage"`
+
+	// ShedPriorityAttribute is the resource attribute holding the priority of the data, from
+	// 0 to 100, when the gradual load shedding is enabled. The data with the lowest priorities
+	// is refused first. When unset or missing, the data is refused with a probability equal
+	// to the fraction to refuse.
+	ShedPriorityAttribute string `mapstructure:"shed_priority_attribute"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -62,5 +90,21 @@ func (cfg *Config) Validate() error {
 	if cfg.MemoryLimitPercentage > 0 && cfg.MemoryLimitPercentage <= cfg.MemorySpikePercentage {
 		return errSpikeLimitPercentageOutOfRange
 	}
+	if cfg.ShedLowWatermarkMiB > 0 {
+		if cfg.MemoryLimitMiB == 0 {
+			return errShedLowWatermarkWithLimitPercentage
+		}
+		if cfg.ShedLowWatermarkMiB >= cfg.MemoryLimitMiB {
+			return errShedLowWatermarkOutOfRange
+		}
+	}
+	if cfg.ShedLowWatermarkPercentage > 0 {
+		if cfg.MemoryLimitMiB > 0 {
+			return errShedLowWatermarkPercentageWithLimit
+		}
+		if cfg.ShedLowWatermarkPercentage >= cfg.MemoryLimitPercentage {
+			return errShedLowWatermarkPercentageOutOfRange
+		}
+	}
 	return nil
 }
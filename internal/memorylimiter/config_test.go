@@ -85,6 +85,70 @@ func TestConfigValidate(t *testing.T) {
 			},
 			err: errSpikeLimitPercentageOutOfRange,
 		},
+		{
+			name: "invalid shed low watermark",
+			cfg: &Config{
+				CheckInterval:       1 * time.Second,
+				MemoryLimitMiB:      100,
+				ShedLowWatermarkMiB: 100,
+			},
+			err: errShedLowWatermarkOutOfRange,
+		},
+		{
+			name: "shed low watermark above the soft limit",
+			cfg: &Config{
+				CheckInterval:       1 * time.Second,
+				MemoryLimitMiB:      100,
+				MemorySpikeLimitMiB: 30,
+				ShedLowWatermarkMiB: 80,
+			},
+			err: nil,
+		},
+		{
+			name: "shed low watermark above the limit",
+			cfg: &Config{
+				CheckInterval:       1 * time.Second,
+				MemoryLimitMiB:      100,
+				ShedLowWatermarkMiB: 120,
+			},
+			err: errShedLowWatermarkOutOfRange,
+		},
+		{
+			name: "shed low watermark with limit percentage",
+			cfg: &Config{
+				CheckInterval:         1 * time.Second,
+				MemoryLimitPercentage: 50,
+				ShedLowWatermarkMiB:   10,
+			},
+			err: errShedLowWatermarkWithLimitPercentage,
+		},
+		{
+			name: "invalid shed low watermark percentage",
+			cfg: &Config{
+				CheckInterval:              1 * time.Second,
+				MemoryLimitPercentage:      50,
+				ShedLowWatermarkPercentage: 60,
+			},
+			err: errShedLowWatermarkPercentageOutOfRange,
+		},
+		{
+			name: "shed low watermark percentage above the soft limit",
+			cfg: &Config{
+				CheckInterval:              1 * time.Second,
+				MemoryLimitPercentage:      50,
+				ShedLowWatermarkPercentage: 45,
+			},
+			err: nil,
+		},
+		{
+			name: "shed low watermark percentage with limit mib",
+			cfg: &Config{
+				CheckInterval:              1 * time.Second,
+				MemoryLimitMiB:             100,
+				ShedLowWatermarkPercentage: 30,
+			},
+			err: errShedLowWatermarkPercentageWithLimit,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
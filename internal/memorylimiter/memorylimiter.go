@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"runtime"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,6 +28,10 @@ const (
 	minGCIntervalWhenSoftLimited = 10 * time.Second
 )
 
+// cgroupPressureEvents are the cgroup memory events reporting memory pressure: the usage
+// reached the memory.high or memory.max boundaries, or the OOM killer was invoked.
+var cgroupPressureEvents = []string{"high", "max", "oom"}
+
 var (
 	// ErrDataRefused will be returned to callers of ConsumeTraceData to indicate
 	// that data is being refused due to high memory usage.
@@ -34,9 +40,11 @@ var (
 	// ErrShutdownNotStarted indicates no memorylimiter has not start when shutdown
 	ErrShutdownNotStarted = errors.New("no existing monitoring routine is running")
 
-	// GetMemoryFn and ReadMemStatsFn make it overridable by tests
-	GetMemoryFn    = iruntime.TotalMemory
-	ReadMemStatsFn = runtime.ReadMemStats
+	// GetMemoryFn, ReadMemStatsFn, SetMemoryLimitFn and ReadCgroupMemoryStatsFn make it overridable by tests
+	GetMemoryFn             = iruntime.TotalMemory
+	ReadMemStatsFn          = runtime.ReadMemStats
+	SetMemoryLimitFn        = debug.SetMemoryLimit
+	ReadCgroupMemoryStatsFn = iruntime.ReadCgroupMemoryStats
 )
 
 // MemoryLimiter is used to prevent out of memory situations on the collector.
@@ -49,13 +57,36 @@ type MemoryLimiter struct {
 	// mustRefuse is used to indicate when data should be refused.
 	mustRefuse *atomic.Bool
 
+	// shedRatio holds the bits of the fraction of the data to refuse when the
+	// gradual load shedding is enabled.
+	shedRatio atomic.Uint64
+	shedding  bool
+
 	ticker *time.Ticker
 
 	lastGCDone time.Time
 
-	// The function to read the mem values is set as a reference to help with
-	// testing different values.
-	readMemStatsFn func(m *runtime.MemStats)
+	// useGoMemLimit indicates whether the memory limiter manages GOMEMLIMIT and
+	// uses the cgroup memory pressure instead of forcing garbage collections.
+	useGoMemLimit bool
+
+	// limitPercentage and spikePercentage are set when the limits are percentages
+	// of the total memory, to adjust them when the total memory changes.
+	limitPercentage uint64
+	spikePercentage uint64
+	shedPercentage  uint64
+
+	// goMemLimit is the GOMEMLIMIT requested by the memory limiter, see goMemLimits.
+	goMemLimit int64
+
+	// cgroupEvents are the cgroup memory events counters read at the previous check.
+	cgroupEvents map[string]uint64
+
+	// The functions to read the mem values and to set GOMEMLIMIT are set as a
+	// reference to help with testing different values.
+	readMemStatsFn          func(m *runtime.MemStats)
+	setMemoryLimitFn        func(limit int64) int64
+	readCgroupMemoryStatsFn func() (iruntime.CgroupMemoryStats, bool, error)
 
 	// Fields used for logging.
 	logger                 *zap.Logger
@@ -77,16 +108,27 @@ func NewMemoryLimiter(cfg *Config, logger *zap.Logger) (*MemoryLimiter, error) {
 	logger.Info("Memory limiter configured",
 		zap.Uint64("limit_mib", usageChecker.memAllocLimit/mibBytes),
 		zap.Uint64("spike_limit_mib", usageChecker.memSpikeLimit/mibBytes),
-		zap.Duration("check_interval", cfg.CheckInterval))
-
-	return &MemoryLimiter{
-		usageChecker:   *usageChecker,
-		memCheckWait:   cfg.CheckInterval,
-		ticker:         time.NewTicker(cfg.CheckInterval),
-		readMemStatsFn: ReadMemStatsFn,
-		logger:         logger,
-		mustRefuse:     &atomic.Bool{},
-	}, nil
+		zap.Duration("check_interval", cfg.CheckInterval),
+		zap.Bool("use_gomemlimit", cfg.UseGoMemLimit))
+
+	ml := &MemoryLimiter{
+		usageChecker:            *usageChecker,
+		memCheckWait:            cfg.CheckInterval,
+		ticker:                  time.NewTicker(cfg.CheckInterval),
+		useGoMemLimit:           cfg.UseGoMemLimit,
+		readMemStatsFn:          ReadMemStatsFn,
+		setMemoryLimitFn:        SetMemoryLimitFn,
+		readCgroupMemoryStatsFn: ReadCgroupMemoryStatsFn,
+		logger:                  logger,
+		mustRefuse:              &atomic.Bool{},
+		shedding:                usageChecker.shedLowWatermark != 0,
+	}
+	if cfg.MemoryLimitMiB == 0 {
+		ml.limitPercentage = uint64(cfg.MemoryLimitPercentage)
+		ml.spikePercentage = uint64(cfg.MemorySpikePercentage)
+		ml.shedPercentage = uint64(cfg.ShedLowWatermarkPercentage)
+	}
+	return ml, nil
 }
 
 // startMonitoring starts a single ticker'd goroutine per instance
@@ -97,6 +139,10 @@ func (ml *MemoryLimiter) startMonitoring() {
 
 	ml.refCounter++
 	if ml.refCounter == 1 {
+		if ml.useGoMemLimit {
+			ml.goMemLimit = ml.goMemLimitTarget()
+			goMemLimits.acquire(ml, ml.goMemLimit)
+		}
 		ml.closed = make(chan struct{})
 		ml.waitGroup.Add(1)
 		go func() {
@@ -137,6 +183,9 @@ func (ml *MemoryLimiter) Shutdown(context.Context) error {
 		ml.ticker.Stop()
 		close(ml.closed)
 		ml.waitGroup.Wait()
+		if ml.useGoMemLimit {
+			goMemLimits.release(ml)
+		}
 	}
 	ml.refCounter--
 	return nil
@@ -147,11 +196,24 @@ func (ml *MemoryLimiter) MustRefuse() bool {
 	return ml.mustRefuse.Load()
 }
 
+// SheddingEnabled returns if the gradual load shedding is configured.
+func (ml *MemoryLimiter) SheddingEnabled() bool {
+	return ml.shedding
+}
+
+// ShedRatio returns the fraction of the data the caller should deny when the gradual load
+// shedding is enabled, growing from 0 at the low watermark to 1 at the hard limit.
+func (ml *MemoryLimiter) ShedRatio() float64 {
+	return math.Float64frombits(ml.shedRatio.Load())
+}
+
 func getMemUsageChecker(cfg *Config, logger *zap.Logger) (*memUsageChecker, error) {
 	memAllocLimit := uint64(cfg.MemoryLimitMiB) * mibBytes
 	memSpikeLimit := uint64(cfg.MemorySpikeLimitMiB) * mibBytes
 	if cfg.MemoryLimitMiB != 0 {
-		return newFixedMemUsageChecker(memAllocLimit, memSpikeLimit), nil
+		usageChecker := newFixedMemUsageChecker(memAllocLimit, memSpikeLimit)
+		usageChecker.shedLowWatermark = uint64(cfg.ShedLowWatermarkMiB) * mibBytes
+		return usageChecker, nil
 	}
 	totalMemory, err := GetMemoryFn()
 	if err != nil {
@@ -161,8 +223,10 @@ func getMemUsageChecker(cfg *Config, logger *zap.Logger) (*memUsageChecker, erro
 		zap.Uint64("total_memory_mib", totalMemory/mibBytes),
 		zap.Uint32("limit_percentage", cfg.MemoryLimitPercentage),
 		zap.Uint32("spike_limit_percentage", cfg.MemorySpikePercentage))
-	return newPercentageMemUsageChecker(totalMemory, uint64(cfg.MemoryLimitPercentage),
-		uint64(cfg.MemorySpikePercentage)), nil
+	usageChecker := newPercentageMemUsageChecker(totalMemory, uint64(cfg.MemoryLimitPercentage),
+		uint64(cfg.MemorySpikePercentage))
+	usageChecker.shedLowWatermark = uint64(cfg.ShedLowWatermarkPercentage) * totalMemory / 100
+	return usageChecker, nil
 }
 
 func (ml *MemoryLimiter) readMemStats() *runtime.MemStats {
@@ -193,13 +257,156 @@ func (ml *MemoryLimiter) doGCandReadMemStats() *runtime.MemStats {
 	return ms
 }
 
+// goMemLimitTarget returns the GOMEMLIMIT corresponding with the soft limit. The ballast
+// is added since it's accounted by the Go runtime but not in the memory usage.
+func (ml *MemoryLimiter) goMemLimitTarget() int64 {
+	return int64(ml.usageChecker.memAllocLimit - ml.usageChecker.memSpikeLimit + ml.ballastSize)
+}
+
+// adjustGoMemLimit recomputes the limits from the total memory when they are percentages,
+// since the total memory can change, e.g. when the container is resized, and updates
+// GOMEMLIMIT if the soft limit changed.
+func (ml *MemoryLimiter) adjustGoMemLimit() {
+	if ml.limitPercentage != 0 {
+		totalMemory, err := GetMemoryFn()
+		if err != nil {
+			ml.logger.Debug("Failed to get total memory, keeping the current limits.", zap.Error(err))
+		} else {
+			ml.usageChecker = *newPercentageMemUsageChecker(totalMemory, ml.limitPercentage, ml.spikePercentage)
+			ml.usageChecker.shedLowWatermark = ml.shedPercentage * totalMemory / 100
+		}
+	}
+	if goMemLimit := ml.goMemLimitTarget(); goMemLimit != ml.goMemLimit {
+		ml.goMemLimit = goMemLimit
+		goMemLimits.update(ml, goMemLimit)
+	}
+}
+
+// goMemLimits owns GOMEMLIMIT for all the memory limiters of the process, since it's
+// process-global: the lowest requested limit is set as long as at least one memory
+// limiter uses it, and the previous GOMEMLIMIT is restored once the last one releases it.
+var goMemLimits = &goMemLimitOwner{limits: map[*MemoryLimiter]int64{}}
+
+type goMemLimitOwner struct {
+	mu      sync.Mutex
+	limits  map[*MemoryLimiter]int64
+	current int64
+	prev    int64
+}
+
+// acquire sets the GOMEMLIMIT requested by the memory limiter.
+func (o *goMemLimitOwner) acquire(ml *MemoryLimiter, limit int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.set(ml, limit)
+}
+
+// update updates the GOMEMLIMIT requested by the memory limiter if it was acquired.
+func (o *goMemLimitOwner) update(ml *MemoryLimiter, limit int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.limits[ml]; ok {
+		o.set(ml, limit)
+	}
+}
+
+// set applies the GOMEMLIMIT requested by the memory limiter. The caller must hold the lock.
+func (o *goMemLimitOwner) set(ml *MemoryLimiter, limit int64) {
+	first := len(o.limits) == 0
+	o.limits[ml] = limit
+	goMemLimit := o.lowest()
+	switch {
+	case first:
+		o.prev = ml.setMemoryLimitFn(goMemLimit)
+	case goMemLimit != o.current:
+		ml.setMemoryLimitFn(goMemLimit)
+	default:
+		return
+	}
+	o.current = goMemLimit
+	ml.logger.Info("Go runtime soft memory limit set.", zap.Uint64("gomemlimit_mib", uint64(goMemLimit)/mibBytes))
+}
+
+// release removes the GOMEMLIMIT requested by the memory limiter.
+func (o *goMemLimitOwner) release(ml *MemoryLimiter) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.limits[ml]; !ok {
+		return
+	}
+	delete(o.limits, ml)
+	if len(o.limits) == 0 {
+		ml.setMemoryLimitFn(o.prev)
+		return
+	}
+	if goMemLimit := o.lowest(); goMemLimit != o.current {
+		ml.setMemoryLimitFn(goMemLimit)
+		o.current = goMemLimit
+		ml.logger.Info("Go runtime soft memory limit set.", zap.Uint64("gomemlimit_mib", uint64(goMemLimit)/mibBytes))
+	}
+}
+
+func (o *goMemLimitOwner) lowest() int64 {
+	lowest := int64(math.MaxInt64)
+	for _, limit := range o.limits {
+		lowest = min(lowest, limit)
+	}
+	return lowest
+}
+
+// cgroupUnderPressure reads the memory stats of the cgroup of the process and returns
+// true if the memory usage of the cgroup is within the spike limit of the cgroup memory
+// limit, or if the kernel reported memory pressure since the previous check. It returns
+// false if the stats are not available.
+func (ml *MemoryLimiter) cgroupUnderPressure() bool {
+	stats, ok, err := ml.readCgroupMemoryStatsFn()
+	if err != nil {
+		ml.logger.Debug("Failed to read cgroup memory stats.", zap.Error(err))
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	prevEvents := ml.cgroupEvents
+	ml.cgroupEvents = stats.Events
+
+	// The cgroup usage includes the memory not accounted by the Go runtime, e.g. the page
+	// cache, so the cgroup can run out of memory while the heap is below the soft limit.
+	if stats.Limit != 0 && stats.Current+ml.usageChecker.memSpikeLimit >= stats.Limit {
+		ml.logger.Debug("Cgroup memory usage is above the soft limit.",
+			zap.Uint64("cgroup_mem_mib", stats.Current/mibBytes), zap.Uint64("cgroup_limit_mib", stats.Limit/mibBytes))
+		return true
+	}
+
+	if prevEvents == nil {
+		// The counters are cumulative, the first read is only the baseline.
+		return false
+	}
+	for _, event := range cgroupPressureEvents {
+		if stats.Events[event] > prevEvents[event] {
+			ml.logger.Debug("Memory pressure reported by the cgroup.",
+				zap.String("event", event), zap.Uint64("cgroup_mem_mib", stats.Current/mibBytes))
+			return true
+		}
+	}
+	return false
+}
+
 // CheckMemLimits inspects current memory usage against threshold and toggle mustRefuse when threshold is exceeded
 func (ml *MemoryLimiter) CheckMemLimits() {
+	if ml.useGoMemLimit {
+		ml.adjustGoMemLimit()
+	}
+
 	ms := ml.readMemStats()
 
 	ml.logger.Debug("Currently used memory.", memstatToZapField(ms))
 
-	if ml.usageChecker.aboveHardLimit(ms) {
+	// With GOMEMLIMIT, the garbage collector already runs as the memory usage
+	// approaches the soft limit, so it's not forced.
+	if !ml.useGoMemLimit && ml.usageChecker.aboveHardLimit(ms) {
 		ml.logger.Warn("Memory usage is above hard limit. Forcing a GC.", memstatToZapField(ms))
 		ms = ml.doGCandReadMemStats()
 	}
@@ -207,35 +414,66 @@ func (ml *MemoryLimiter) CheckMemLimits() {
 	// Remember current state.
 	wasRefusing := ml.mustRefuse.Load()
 
-	// Check if the memory usage is above the soft limit.
-	mustRefuse := ml.usageChecker.aboveSoftLimit(ms)
+	// Check if the memory usage is above the limit of refusing all the data.
+	mustRefuse := ml.aboveRefuseLimit(ms)
 
-	if wasRefusing && !mustRefuse {
-		// Was previously refusing but enough memory is available now, no need to limit.
-		ml.logger.Info("Memory usage back within limits. Resuming normal operation.", memstatToZapField(ms))
-	}
+	// The cgroup stats are read at every check to keep track of the events counters.
+	cgroupPressure := ml.useGoMemLimit && ml.cgroupUnderPressure()
 
-	if !wasRefusing && mustRefuse {
+	if !wasRefusing && ml.usageChecker.aboveSoftLimit(ms) {
 		// We are above soft limit, do a GC if it wasn't done recently and see if
 		// it brings memory usage below the soft limit.
-		if time.Since(ml.lastGCDone) > minGCIntervalWhenSoftLimited {
+		if !ml.useGoMemLimit && time.Since(ml.lastGCDone) > minGCIntervalWhenSoftLimited {
 			ml.logger.Info("Memory usage is above soft limit. Forcing a GC.", memstatToZapField(ms))
 			ms = ml.doGCandReadMemStats()
 			// Check the limit again to see if GC helped.
-			mustRefuse = ml.usageChecker.aboveSoftLimit(ms)
+			mustRefuse = ml.aboveRefuseLimit(ms)
 		}
 
-		if mustRefuse {
+		switch {
+		case mustRefuse && ml.shedding:
+			ml.logger.Warn("Memory usage is above hard limit. Refusing data.", memstatToZapField(ms))
+		case mustRefuse:
 			ml.logger.Warn("Memory usage is above soft limit. Refusing data.", memstatToZapField(ms))
 		}
 	}
 
+	if cgroupPressure && !mustRefuse {
+		mustRefuse = true
+		if !wasRefusing {
+			ml.logger.Warn("Memory pressure reported by the cgroup. Refusing data.", memstatToZapField(ms))
+		}
+	}
+
+	if wasRefusing && !mustRefuse {
+		// Was previously refusing but enough memory is available now, no need to limit.
+		ml.logger.Info("Memory usage back within limits. Resuming normal operation.", memstatToZapField(ms))
+	}
+
 	ml.mustRefuse.Store(mustRefuse)
+
+	if ml.shedding {
+		shedRatio := ml.usageChecker.shedRatio(ms)
+		if cgroupPressure {
+			shedRatio = 1
+		}
+		ml.shedRatio.Store(math.Float64bits(shedRatio))
+	}
+}
+
+// aboveRefuseLimit returns if all the data must be refused: above the soft limit, or above
+// the hard limit when the gradual load shedding refuses a part of the data below it.
+func (ml *MemoryLimiter) aboveRefuseLimit(ms *runtime.MemStats) bool {
+	if ml.shedding {
+		return ml.usageChecker.aboveHardLimit(ms)
+	}
+	return ml.usageChecker.aboveSoftLimit(ms)
 }
 
 type memUsageChecker struct {
-	memAllocLimit uint64
-	memSpikeLimit uint64
+	memAllocLimit    uint64
+	memSpikeLimit    uint64
+	shedLowWatermark uint64
 }
 
 func (d memUsageChecker) aboveSoftLimit(ms *runtime.MemStats) bool {
@@ -246,6 +484,18 @@ func (d memUsageChecker) aboveHardLimit(ms *runtime.MemStats) bool {
 	return ms.Alloc >= d.memAllocLimit
 }
 
+// shedRatio returns the fraction of the data to refuse, growing linearly from 0 at the
+// low watermark to 1 at the hard limit, where all the data is refused.
+func (d memUsageChecker) shedRatio(ms *runtime.MemStats) float64 {
+	if ms.Alloc <= d.shedLowWatermark {
+		return 0
+	}
+	if ms.Alloc >= d.memAllocLimit {
+		return 1
+	}
+	return float64(ms.Alloc-d.shedLowWatermark) / float64(d.memAllocLimit-d.shedLowWatermark)
+}
+
 func newFixedMemUsageChecker(memAllocLimit, memSpikeLimit uint64) *memUsageChecker {
 	if memSpikeLimit == 0 {
 		// If spike limit is unspecified use 20% of mem limit.
@@ -5,7 +5,9 @@ package memorylimiter
 
 import (
 	"context"
+	"math"
 	"runtime"
+	"runtime/debug"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -152,6 +154,237 @@ func TestRefuseDecision(t *testing.T) {
 	}
 }
 
+func TestGoMemLimit(t *testing.T) {
+	totalMemory := uint64(1000 * mibBytes)
+	t.Cleanup(func() {
+		GetMemoryFn = iruntime.TotalMemory
+		SetMemoryLimitFn = debug.SetMemoryLimit
+	})
+	GetMemoryFn = func() (uint64, error) {
+		return totalMemory, nil
+	}
+	var goMemLimit int64 = math.MaxInt64
+	SetMemoryLimitFn = func(limit int64) int64 {
+		prev := goMemLimit
+		goMemLimit = limit
+		return prev
+	}
+
+	ml, err := NewMemoryLimiter(&Config{
+		CheckInterval:         10 * time.Second,
+		MemoryLimitPercentage: 50,
+		MemorySpikePercentage: 10,
+		UseGoMemLimit:         true,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = 100 * mibBytes
+	}
+	ml.readCgroupMemoryStatsFn = func() (iruntime.CgroupMemoryStats, bool, error) {
+		return iruntime.CgroupMemoryStats{}, false, nil
+	}
+
+	require.NoError(t, ml.Start(context.Background(), &host{ballastSize: 10 * mibBytes}))
+	// Soft limit plus the ballast.
+	assert.Equal(t, int64(410*mibBytes), goMemLimit)
+
+	// The limits follow the total memory.
+	totalMemory = 2000 * mibBytes
+	ml.CheckMemLimits()
+	assert.Equal(t, int64(810*mibBytes), goMemLimit)
+	assert.False(t, ml.MustRefuse())
+
+	require.NoError(t, ml.Shutdown(context.Background()))
+	assert.Equal(t, int64(math.MaxInt64), goMemLimit)
+}
+
+func TestCgroupMemoryPressure(t *testing.T) {
+	var currentMemAlloc uint64
+	events := map[string]uint64{"low": 0, "high": 0, "max": 0, "oom": 0}
+	ml := &MemoryLimiter{
+		usageChecker: memUsageChecker{
+			memAllocLimit: 1024,
+			memSpikeLimit: 200,
+		},
+		useGoMemLimit: true,
+		mustRefuse:    &atomic.Bool{},
+		readMemStatsFn: func(ms *runtime.MemStats) {
+			ms.Alloc = currentMemAlloc
+		},
+		setMemoryLimitFn: func(int64) int64 { return math.MaxInt64 },
+		readCgroupMemoryStatsFn: func() (iruntime.CgroupMemoryStats, bool, error) {
+			copied := make(map[string]uint64, len(events))
+			for k, v := range events {
+				copied[k] = v
+			}
+			return iruntime.CgroupMemoryStats{Current: 2048, Events: copied}, true, nil
+		},
+		logger: zap.NewNop(),
+	}
+
+	// Below the soft limit without pressure.
+	currentMemAlloc = 500
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// Events not reporting pressure are ignored.
+	events["low"]++
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// The cgroup reached memory.high.
+	events["high"]++
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+
+	// No more pressure since the previous check.
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// The cgroup reached memory.max.
+	events["max"] += 2
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+
+	// Above the soft limit without pressure, the garbage collection is not forced.
+	currentMemAlloc = 900
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+	assert.True(t, ml.lastGCDone.IsZero())
+}
+
+func TestCgroupMemoryUsage(t *testing.T) {
+	var cgroupCurrent uint64
+	ml := &MemoryLimiter{
+		usageChecker: memUsageChecker{
+			memAllocLimit: 1024,
+			memSpikeLimit: 200,
+		},
+		useGoMemLimit: true,
+		mustRefuse:    &atomic.Bool{},
+		readMemStatsFn: func(ms *runtime.MemStats) {
+			ms.Alloc = 500
+		},
+		setMemoryLimitFn: func(int64) int64 { return math.MaxInt64 },
+		readCgroupMemoryStatsFn: func() (iruntime.CgroupMemoryStats, bool, error) {
+			return iruntime.CgroupMemoryStats{Current: cgroupCurrent, Limit: 2048, Events: map[string]uint64{}}, true, nil
+		},
+		logger: zap.NewNop(),
+	}
+
+	// The cgroup usage is below its limit minus the spike limit.
+	cgroupCurrent = 1800
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// The cgroup usage, e.g. with the page cache, is close to its limit while the heap is below the soft limit.
+	cgroupCurrent = 1900
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+
+	cgroupCurrent = 1000
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+}
+
+func TestGoMemLimitShared(t *testing.T) {
+	var goMemLimit int64 = math.MaxInt64
+	setMemoryLimit := func(limit int64) int64 {
+		prev := goMemLimit
+		goMemLimit = limit
+		return prev
+	}
+	newMemoryLimiter := func(limitMiB uint32) *MemoryLimiter {
+		ml, err := NewMemoryLimiter(&Config{
+			CheckInterval:       10 * time.Second,
+			MemoryLimitMiB:      limitMiB,
+			MemorySpikeLimitMiB: 10,
+			UseGoMemLimit:       true,
+		}, zap.NewNop())
+		require.NoError(t, err)
+		ml.setMemoryLimitFn = setMemoryLimit
+		return ml
+	}
+	ml1 := newMemoryLimiter(100)
+	ml2 := newMemoryLimiter(50)
+
+	require.NoError(t, ml1.Start(context.Background(), &host{}))
+	assert.Equal(t, int64(90*mibBytes), goMemLimit)
+
+	// The lowest soft limit is set.
+	require.NoError(t, ml2.Start(context.Background(), &host{}))
+	assert.Equal(t, int64(40*mibBytes), goMemLimit)
+
+	// The other memory limiter still uses GOMEMLIMIT.
+	require.NoError(t, ml2.Shutdown(context.Background()))
+	assert.Equal(t, int64(90*mibBytes), goMemLimit)
+
+	require.NoError(t, ml1.Shutdown(context.Background()))
+	assert.Equal(t, int64(math.MaxInt64), goMemLimit)
+}
+
+func TestShedRatio(t *testing.T) {
+	var currentMemAlloc uint64
+	ml := &MemoryLimiter{
+		usageChecker: memUsageChecker{
+			memAllocLimit:    1000,
+			memSpikeLimit:    200,
+			shedLowWatermark: 600,
+		},
+		shedding:   true,
+		mustRefuse: &atomic.Bool{},
+		readMemStatsFn: func(ms *runtime.MemStats) {
+			ms.Alloc = currentMemAlloc
+		},
+		logger: zap.NewNop(),
+	}
+	assert.True(t, ml.SheddingEnabled())
+
+	// Below the low watermark.
+	currentMemAlloc = 500
+	ml.CheckMemLimits()
+	assert.Equal(t, 0.0, ml.ShedRatio())
+
+	// Between the low watermark and the hard limit, the soft limit doesn't refuse all the data.
+	currentMemAlloc = 700
+	ml.CheckMemLimits()
+	assert.InDelta(t, 0.25, ml.ShedRatio(), 1e-9)
+	assert.False(t, ml.MustRefuse())
+
+	currentMemAlloc = 900
+	ml.CheckMemLimits()
+	assert.InDelta(t, 0.75, ml.ShedRatio(), 1e-9)
+	assert.False(t, ml.MustRefuse())
+
+	// Above the hard limit.
+	currentMemAlloc = 1000
+	ml.CheckMemLimits()
+	assert.Equal(t, 1.0, ml.ShedRatio())
+	assert.True(t, ml.MustRefuse())
+
+	// Back between the soft and the hard limits.
+	currentMemAlloc = 800
+	ml.CheckMemLimits()
+	assert.InDelta(t, 0.5, ml.ShedRatio(), 1e-9)
+	assert.False(t, ml.MustRefuse())
+}
+
+func TestGetDecisionShedLowWatermark(t *testing.T) {
+	d, err := getMemUsageChecker(&Config{MemoryLimitMiB: 100, ShedLowWatermarkMiB: 60}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(60*mibBytes), d.shedLowWatermark)
+
+	t.Cleanup(func() {
+		GetMemoryFn = iruntime.TotalMemory
+	})
+	GetMemoryFn = func() (uint64, error) {
+		return 100 * mibBytes, nil
+	}
+	d, err = getMemUsageChecker(&Config{MemoryLimitPercentage: 50, ShedLowWatermarkPercentage: 30}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(30*mibBytes), d.shedLowWatermark)
+}
+
 func TestBallastSize(t *testing.T) {
 	cfg := &Config{
 		CheckInterval:  10 * time.Second,
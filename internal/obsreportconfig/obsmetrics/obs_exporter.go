@@ -13,6 +13,8 @@ const (
 	FailedToSendSpansKey = "send_failed_spans"
 	// FailedToEnqueueSpansKey used to track spans that failed to be enqueued by exporters.
 	FailedToEnqueueSpansKey = "enqueue_failed_spans"
+	// DeadlineExceededSpansKey used to track spans that failed to be sent by exporters before the request deadline.
+	DeadlineExceededSpansKey = "send_deadline_exceeded_spans"
 
 	// SentMetricPointsKey used to track metric points sent by exporters.
 	SentMetricPointsKey = "sent_metric_points"
@@ -20,6 +22,8 @@ const (
 	FailedToSendMetricPointsKey = "send_failed_metric_points"
 	// FailedToEnqueueMetricPointsKey used to track metric points that failed to be enqueued by exporters.
 	FailedToEnqueueMetricPointsKey = "enqueue_failed_metric_points"
+	// DeadlineExceededMetricPointsKey used to track metric points that failed to be sent by exporters before the request deadline.
+	DeadlineExceededMetricPointsKey = "send_deadline_exceeded_metric_points"
 
 	// SentLogRecordsKey used to track logs sent by exporters.
 	SentLogRecordsKey = "sent_log_records"
@@ -27,6 +31,8 @@ const (
 	FailedToSendLogRecordsKey = "send_failed_log_records"
 	// FailedToEnqueueLogRecordsKey used to track logs that failed to be enqueued by exporters.
 	FailedToEnqueueLogRecordsKey = "enqueue_failed_log_records"
+	// DeadlineExceededLogRecordsKey used to track logs that failed to be sent by exporters before the request deadline.
+	DeadlineExceededLogRecordsKey = "send_deadline_exceeded_log_records"
 )
 
 var (
@@ -171,6 +171,7 @@ func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 	col.service, err = service.New(ctx, service.Settings{
 		BuildInfo:         col.set.BuildInfo,
 		CollectorConf:     conf,
+		EffectiveConf:     cfg.redactedConf,
 		Receivers:         receiver.NewBuilder(cfg.Receivers, factories.Receivers),
 		Processors:        processor.NewBuilder(cfg.Processors, factories.Processors),
 		Exporters:         exporter.NewBuilder(cfg.Exporters, factories.Exporters),
@@ -210,7 +211,14 @@ func (col *Collector) reloadConfiguration(ctx context.Context) error {
 	return nil
 }
 
+// DryRun validates the configuration without running the collector. The config provider is shut down
+// once the configuration is validated, so the collector shouldn't be run afterwards.
 func (col *Collector) DryRun(ctx context.Context) error {
+	// Stop watching for the configuration updates, nothing reloads the configuration.
+	return multierr.Combine(col.dryRun(ctx), col.shutdownConfigProvider(ctx))
+}
+
+func (col *Collector) dryRun(ctx context.Context) error {
 	factories, err := col.set.Factories()
 	if err != nil {
 		return fmt.Errorf("failed to initialize factories: %w", err)
@@ -228,7 +236,8 @@ func (col *Collector) DryRun(ctx context.Context) error {
 func (col *Collector) Run(ctx context.Context) error {
 	if err := col.setupConfigurationComponents(ctx); err != nil {
 		col.setCollectorState(StateClosed)
-		return err
+		// Stop watching for the configuration updates, the collector cannot be run again.
+		return multierr.Combine(err, col.shutdownConfigProvider(ctx))
 	}
 
 	// Always notify with SIGHUP for configuration reloading.
@@ -249,7 +258,7 @@ LOOP:
 				break LOOP
 			}
 			if err = col.reloadConfiguration(ctx); err != nil {
-				return err
+				return multierr.Combine(err, col.shutdownConfigProvider(ctx))
 			}
 		case err := <-col.asyncErrorChannel:
 			col.service.Logger().Error("Asynchronous error received, terminating process", zap.Error(err))
@@ -260,7 +269,7 @@ LOOP:
 				break LOOP
 			}
 			if err := col.reloadConfiguration(ctx); err != nil {
-				return err
+				return multierr.Combine(err, col.shutdownConfigProvider(ctx))
 			}
 		case <-col.shutdownChan:
 			col.service.Logger().Info("Received shutdown request")
@@ -280,8 +289,8 @@ func (col *Collector) shutdown(ctx context.Context) error {
 	// Accumulate errors and proceed with shutting down remaining components.
 	var errs error
 
-	if err := col.set.ConfigProvider.Shutdown(ctx); err != nil {
-		errs = multierr.Append(errs, fmt.Errorf("failed to shutdown config provider: %w", err))
+	if err := col.shutdownConfigProvider(ctx); err != nil {
+		errs = multierr.Append(errs, err)
 	}
 
 	// shutdown service
@@ -294,6 +303,14 @@ func (col *Collector) shutdown(ctx context.Context) error {
 	return errs
 }
 
+// shutdownConfigProvider shuts down the config provider, which stops watching for the configuration updates.
+func (col *Collector) shutdownConfigProvider(ctx context.Context) error {
+	if err := col.set.ConfigProvider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shutdown config provider: %w", err)
+	}
+	return nil
+}
+
 // setCollectorState provides current state of the collector
 func (col *Collector) setCollectorState(state State) {
 	col.state.Store(int32(state))
@@ -429,6 +429,9 @@ func TestCollectorDryRun(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Error(t, col.DryRun(context.Background()))
+	// The config provider is shut down, which stops watching for the configuration updates.
+	_, ok := <-cfgProvider.Watch()
+	assert.False(t, ok)
 }
 
 func TestPassConfmapToServiceFailure(t *testing.T) {
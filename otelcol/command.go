@@ -9,6 +9,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/provider/httpprovider"
+	"go.opentelemetry.io/collector/confmap/provider/httpsprovider"
 	"go.opentelemetry.io/collector/featuregate"
 )
 
@@ -29,22 +32,38 @@ func NewCommand(set CollectorSettings) *cobra.Command {
 	}
 	rootCmd.AddCommand(newComponentsCommand(set))
 	rootCmd.AddCommand(newValidateSubCommand(set, flagSet))
+	rootCmd.AddCommand(newPrintConfigSubCommand(set, flagSet))
 	rootCmd.Flags().AddGoFlagSet(flagSet)
 	return rootCmd
 }
 
 func newCollectorWithFlags(set CollectorSettings, flags *flag.FlagSet) (*Collector, error) {
 	if set.ConfigProvider == nil {
-		configFlags := getConfigFlag(flags)
-		if len(configFlags) == 0 {
-			return nil, errors.New("at least one config flag must be provided")
-		}
-
 		var err error
-		set.ConfigProvider, err = NewConfigProvider(newDefaultConfigProviderSettings(configFlags))
-		if err != nil {
+		if set.ConfigProvider, err = newConfigProviderWithFlags(flags); err != nil {
 			return nil, err
 		}
 	}
 	return NewCollector(set)
 }
+
+// newConfigProviderWithFlags creates the default ConfigProvider for the config flags.
+func newConfigProviderWithFlags(flags *flag.FlagSet) (ConfigProvider, error) {
+	configFlags := getConfigFlag(flags)
+	if len(configFlags) == 0 {
+		return nil, errors.New("at least one config flag must be provided")
+	}
+
+	cfgProviderSettings := newDefaultConfigProviderSettings(configFlags)
+	cfgProviderSettings.ResolverSettings.MergeStrategy = getConfigMergeStrategyFlag(flags)
+	cfgProviderSettings.ResolverSettings.MergePaths = getConfigMergePathFlag(flags)
+	httpOpts, httpsOpts := getHTTPProviderOptions(flags)
+	providerSet := confmap.ProviderSettings{}
+	for _, provider := range []confmap.Provider{
+		httpprovider.NewWithOptions(providerSet, httpOpts...),
+		httpsprovider.NewWithOptions(providerSet, httpsOpts...),
+	} {
+		cfgProviderSettings.ResolverSettings.Providers[provider.Scheme()] = provider
+	}
+	return NewConfigProvider(cfgProviderSettings)
+}
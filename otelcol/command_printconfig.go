@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol // import "go.opentelemetry.io/collector/otelcol"
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v3"
+)
+
+// newPrintConfigSubCommand constructs a new print-config sub command using the given CollectorSettings.
+func newPrintConfigSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.Command {
+	printConfigCmd := &cobra.Command{
+		Use:   "print-config",
+		Short: "Outputs the effective config, with the sensitive values redacted",
+		Long: "Outputs the effective config, after the configs are merged and their values expanded, with the values of the" +
+			" configopaque.String fields and the values expanded from other values, e.g. ${env:PASSWORD}, redacted. The output format is not stable and can change between releases.",
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if set.ConfigProvider == nil {
+				var err error
+				if set.ConfigProvider, err = newConfigProviderWithFlags(flagSet); err != nil {
+					return err
+				}
+			}
+			factories, err := set.Factories()
+			if err != nil {
+				return fmt.Errorf("failed to initialize factories: %w", err)
+			}
+			cfg, err := set.ConfigProvider.Get(cmd.Context(), factories)
+			// Stop watching for the configuration updates.
+			if err = multierr.Combine(err, set.ConfigProvider.Shutdown(cmd.Context())); err != nil {
+				return fmt.Errorf("failed to get config: %w", err)
+			}
+			conf, err := cfg.redactedConf()
+			if err != nil {
+				return fmt.Errorf("failed to marshal config: %w", err)
+			}
+			yamlData, err := yaml.Marshal(conf.ToStringMap())
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(yamlData))
+			return nil
+		},
+	}
+	printConfigCmd.Flags().AddGoFlagSet(flagSet)
+	return printConfigCmd
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/featuregate"
+)
+
+type secretExporterConfig struct {
+	Endpoint string              `mapstructure:"endpoint"`
+	Password string              `mapstructure:"password"`
+	Token    configopaque.String `mapstructure:"token"`
+}
+
+func secretFactories() (Factories, error) {
+	factories, err := nopFactories()
+	if err != nil {
+		return Factories{}, err
+	}
+	factories.Exporters, err = exporter.MakeFactoryMap(exporter.NewFactory(component.MustNewType("secret"), func() component.Config {
+		return &secretExporterConfig{}
+	}))
+	return factories, err
+}
+
+func TestPrintConfigSubCommandNoConfig(t *testing.T) {
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: nopFactories}, flags(featuregate.GlobalRegistry()))
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at least one config flag must be provided")
+}
+
+func TestPrintConfigSubCommand(t *testing.T) {
+	t.Setenv("SECRET_PASSWORD", "p4ssw0rd")
+	t.Setenv("SECRET_TOKEN", "s3cr3t")
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: secretFactories}, flags(featuregate.NewRegistry()))
+	cmd.SetArgs([]string{"--config=" + filepath.Join("testdata", "otelcol-printconfig.yaml")})
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Execute())
+
+	// The opaque token and the password expanded in a plain string field are both redacted.
+	assert.NotContains(t, out.String(), "s3cr3t")
+	assert.NotContains(t, out.String(), "p4ssw0rd")
+	conf := map[string]any{}
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &conf))
+	assert.Equal(t, map[string]any{
+		"secret": map[string]any{
+			"endpoint": "localhost:4317",
+			"password": "[REDACTED]",
+			"token":    "[REDACTED]",
+		},
+	}, conf["exporters"])
+	assert.Equal(t, map[string]any{"nop": map[string]any{}}, conf["receivers"])
+	pipelines := conf["service"].(map[string]any)["pipelines"]
+	assert.Equal(t, map[string]any{
+		"traces": map[string]any{
+			"receivers":  []any{"nop"},
+			"processors": []any{},
+			"exporters":  []any{"secret"},
+		},
+	}, pipelines)
+}
+
+func TestPrintConfigSubCommandInvalidComponents(t *testing.T) {
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: nopFactories}, flags(featuregate.NewRegistry()))
+	cmd.SetArgs([]string{"--config=" + filepath.Join("testdata", "otelcol-invalid-components.yaml")})
+	err := cmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown type: \"nosuchprocessor\"")
+}
@@ -4,6 +4,10 @@
 package otelcol
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -15,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/converter/expandconverter"
 	"go.opentelemetry.io/collector/confmap/provider/fileprovider"
+	"go.opentelemetry.io/collector/featuregate"
 )
 
 func TestNewCommandVersion(t *testing.T) {
@@ -61,3 +66,32 @@ func TestNewCommandInvalidComponent(t *testing.T) {
 	cmd := NewCommand(CollectorSettings{Factories: nopFactories, ConfigProvider: cfgProvider})
 	require.Error(t, cmd.Execute())
 }
+
+func TestNewConfigProviderWithHTTPFlags(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "otelcol-nop.yaml"))
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	flgs := flags(featuregate.NewRegistry())
+	require.NoError(t, flgs.Parse([]string{
+		"--config=" + server.URL,
+		"--config-http-header=Authorization=Bearer token",
+		"--config-http-poll-interval=1h",
+	}))
+	cp, err := newConfigProviderWithFlags(flgs)
+	require.NoError(t, err)
+
+	factories, err := nopFactories()
+	require.NoError(t, err)
+	cfg, err := cp.Get(context.Background(), factories)
+	require.NoError(t, err)
+	assert.NoError(t, cfg.Validate())
+	assert.NoError(t, cp.Shutdown(context.Background()))
+}
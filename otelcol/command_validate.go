@@ -4,7 +4,6 @@
 package otelcol // import "go.opentelemetry.io/collector/otelcol"
 
 import (
-	"errors"
 	"flag"
 
 	"github.com/spf13/cobra"
@@ -19,14 +18,7 @@ func newValidateSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if set.ConfigProvider == nil {
 				var err error
-
-				configFlags := getConfigFlag(flagSet)
-				if len(configFlags) == 0 {
-					return errors.New("at least one config flag must be provided")
-				}
-
-				set.ConfigProvider, err = NewConfigProvider(newDefaultConfigProviderSettings(configFlags))
-				if err != nil {
+				if set.ConfigProvider, err = newConfigProviderWithFlags(flagSet); err != nil {
 					return err
 				}
 			}
@@ -6,8 +6,10 @@ package otelcol // import "go.opentelemetry.io/collector/otelcol"
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/service"
 )
 
@@ -34,6 +36,10 @@ type Config struct {
 	Extensions map[component.ID]component.Config
 
 	Service service.Config
+
+	// expandedKeys are the keys of the values expanded from other values, e.g. from "${env:PASSWORD}",
+	// which are redacted with the configopaque.String values.
+	expandedKeys []string
 }
 
 // Validate returns an error if the config is invalid.
@@ -149,3 +155,69 @@ func (cfg *Config) Validate() error {
 	}
 	return nil
 }
+
+// redactedConfig is the Config with the mapstructure tags of its sections.
+type redactedConfig struct {
+	Receivers  map[component.ID]component.Config `mapstructure:"receivers"`
+	Exporters  map[component.ID]component.Config `mapstructure:"exporters"`
+	Processors map[component.ID]component.Config `mapstructure:"processors"`
+	Connectors map[component.ID]component.Config `mapstructure:"connectors"`
+	Extensions map[component.ID]component.Config `mapstructure:"extensions"`
+	Service    service.Config                    `mapstructure:"service"`
+}
+
+// redactedValue replaces the sensitive values, like the marshaled configopaque.String values.
+const redactedValue = "[REDACTED]"
+
+// redactedConf marshals the Config as a confmap.Conf. The values of the configopaque.String fields,
+// used by the components for their sensitive values, and the values expanded from other values,
+// which can be secrets from the environment or from files, are redacted.
+func (cfg *Config) redactedConf() (*confmap.Conf, error) {
+	conf := confmap.New()
+	if err := conf.Marshal(&redactedConfig{
+		Receivers:  cfg.Receivers,
+		Exporters:  cfg.Exporters,
+		Processors: cfg.Processors,
+		Connectors: cfg.Connectors,
+		Extensions: cfg.Extensions,
+		Service:    cfg.Service,
+	}); err != nil {
+		return nil, err
+	}
+	if len(cfg.expandedKeys) == 0 {
+		return conf, nil
+	}
+	stringMap := conf.ToStringMap()
+	for _, key := range cfg.expandedKeys {
+		redactKey(stringMap, strings.Split(key, confmap.KeyDelimiter))
+	}
+	return confmap.NewFromStringMap(stringMap), nil
+}
+
+// redactKey redacts the value at the given key path in the map, if any. The key path is matched case-insensitively
+// since the keys of the marshaled configuration can differ in case from the ones of the resolved configuration.
+func redactKey(stringMap map[string]any, path []string) {
+	for k, v := range stringMap {
+		if !strings.EqualFold(k, path[0]) {
+			continue
+		}
+		if len(path) == 1 {
+			stringMap[k] = redactValue(v)
+		} else if m, ok := v.(map[string]any); ok {
+			redactKey(m, path[1:])
+		}
+	}
+}
+
+// redactValue returns the value with all its values redacted, the keys of the maps are kept.
+func redactValue(value any) any {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return redactedValue
+	}
+	ret := make(map[string]any, len(m))
+	for k, v := range m {
+		ret[k] = redactValue(v)
+	}
+	return ret
+}
@@ -276,3 +276,32 @@ func generateConfig() *Config {
 		},
 	}
 }
+
+func TestRedactKey(t *testing.T) {
+	stringMap := map[string]any{
+		"exporters": map[string]any{
+			"otlp": map[string]any{
+				"endpoint": "localhost:4317",
+				"headers":  map[string]any{"Authorization": "Bearer token", "X-Scope": "tenant"},
+			},
+		},
+		"receivers": map[string]any{
+			"otlp": map[string]any{"protocols": map[string]any{"grpc": map[string]any{"endpoint": "0.0.0.0:4317"}}},
+		},
+	}
+	redactKey(stringMap, []string{"exporters", "otlp", "headers", "authorization"})
+	redactKey(stringMap, []string{"receivers"})
+	redactKey(stringMap, []string{"processors", "batch"})
+	assert.Equal(t, map[string]any{
+		"exporters": map[string]any{
+			"otlp": map[string]any{
+				"endpoint": "localhost:4317",
+				"headers":  map[string]any{"Authorization": redactedValue, "X-Scope": "tenant"},
+			},
+		},
+		// The keys of the expanded maps are kept.
+		"receivers": map[string]any{
+			"otlp": map[string]any{"protocols": map[string]any{"grpc": map[string]any{"endpoint": redactedValue}}},
+		},
+	}, stringMap)
+}
@@ -111,6 +111,8 @@ func (cm *configProvider) Get(ctx context.Context, factories Factories) (*Config
 		Connectors: cfg.Connectors.Configs(),
 		Extensions: cfg.Extensions.Configs(),
 		Service:    cfg.Service,
+
+		expandedKeys: cm.mapResolver.ExpandedKeys(),
 	}, nil
 }
 
@@ -97,6 +97,7 @@ func TestConfigProviderFile(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.EqualValues(t, configNop, cfg)
+	assert.NoError(t, cp.Shutdown(context.Background()))
 }
 
 func TestGetConfmap(t *testing.T) {
@@ -126,4 +127,5 @@ func TestGetConfmap(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.EqualValues(t, yamlMap, cmap.ToStringMap())
+	assert.NoError(t, cp.Shutdown(context.Background()))
 }
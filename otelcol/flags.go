@@ -6,13 +6,25 @@ package otelcol // import "go.opentelemetry.io/collector/otelcol"
 import (
 	"errors"
 	"flag"
+	"sort"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/provider/httpprovider"
+	"go.opentelemetry.io/collector/confmap/provider/httpsprovider"
 	"go.opentelemetry.io/collector/featuregate"
 )
 
 const (
-	configFlag = "config"
+	configFlag                 = "config"
+	configMergeStrategyFlag    = "config-merge-strategy"
+	configMergePathFlag        = "config-merge-path"
+	configHTTPPollIntervalFlag = "config-http-poll-interval"
+	configHTTPHeaderFlag       = "config-http-header"
+	configHTTPSCAFileFlag      = "config-https-ca-file"
+	configHTTPSCertFileFlag    = "config-https-cert-file"
+	configHTTPSKeyFileFlag     = "config-https-key-file"
 )
 
 type configFlagValue struct {
@@ -20,6 +32,76 @@ type configFlagValue struct {
 	sets   []string
 }
 
+type mergeStrategyFlagValue struct {
+	strategy confmap.MergeStrategy
+}
+
+func (s *mergeStrategyFlagValue) Set(val string) error {
+	switch strategy := confmap.MergeStrategy(val); strategy {
+	case confmap.MergeStrategyOverride, confmap.MergeStrategyAppend, confmap.MergeStrategyKeyed:
+		s.strategy = strategy
+		return nil
+	}
+	// No need for more context, see TestConfigMergeStrategyFlag/unknown.
+	return errors.New("unknown merge strategy")
+}
+
+func (s *mergeStrategyFlagValue) String() string {
+	return string(s.strategy)
+}
+
+// defaultMergePaths are the paths of the lists merged with the "append" and "keyed" merge strategies
+// if no merge path flag is set: the lists of component IDs of the service.
+var defaultMergePaths = []string{
+	"service::extensions",
+	"service::pipelines::*::receivers",
+	"service::pipelines::*::processors",
+	"service::pipelines::*::exporters",
+}
+
+type mergePathFlagValue struct {
+	paths []string
+}
+
+func (s *mergePathFlagValue) Set(val string) error {
+	s.paths = append(s.paths, strings.ReplaceAll(strings.TrimSpace(val), ".", confmap.KeyDelimiter))
+	return nil
+}
+
+func (s *mergePathFlagValue) String() string {
+	return "[" + strings.Join(s.paths, ", ") + "]"
+}
+
+type headerFlagValue struct {
+	headers map[string]string
+}
+
+func (s *headerFlagValue) Set(val string) error {
+	idx := strings.Index(val, "=")
+	if idx == -1 {
+		return errors.New("missing equal sign")
+	}
+	name := strings.TrimSpace(val[:idx])
+	if name == "" {
+		return errors.New("missing header name")
+	}
+	if s.headers == nil {
+		s.headers = map[string]string{}
+	}
+	s.headers[name] = val[idx+1:]
+	return nil
+}
+
+// String returns the header names only, since their values may be sensitive.
+func (s *headerFlagValue) String() string {
+	names := make([]string, 0, len(s.headers))
+	for name := range s.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
 func (s *configFlagValue) Set(val string) error {
 	s.values = append(s.values, val)
 	return nil
@@ -49,6 +131,30 @@ func flags(reg *featuregate.Registry) *flag.FlagSet {
 			return nil
 		})
 
+	flagSet.Var(&mergeStrategyFlagValue{strategy: confmap.MergeStrategyOverride}, configMergeStrategyFlag,
+		"Strategy to merge the configs of the config flags, in the given order with the set flags last."+
+			" Known strategies are \"override\" (lists are overridden), \"append\" (lists are appended) and"+
+			" \"keyed\" (list elements not already set are appended). With \"append\" and \"keyed\", the \"__delete__\""+
+			" value deletes a map key and the \"{__delete__: value}\" list element deletes the value from the list.")
+	flagSet.Var(new(mergePathFlagValue), configMergePathFlag,
+		"Path of the lists merged with the \"append\" and \"keyed\" merge strategies, the other lists are overridden."+
+			" A \"*\" segment matches any key, and only a single path can be set per flag entry"+
+			" e.g. `--config-merge-path=service.pipelines.*.processors`. Defaults to the service extensions and"+
+			" the pipelines receivers, processors and exporters.")
+
+	flagSet.Duration(configHTTPPollIntervalFlag, 0,
+		"Interval to poll the configs of the http and https config locations at, to reload the collector once"+
+			" their content changes. The configs are not polled if 0.")
+	flagSet.Var(new(headerFlagValue), configHTTPHeaderFlag,
+		"Header added to the requests of the http and https config locations, note that only a single header"+
+			" can be set per flag entry e.g. `--config-http-header=Authorization=Bearer token`.")
+	flagSet.String(configHTTPSCAFileFlag, "",
+		"Path to a PEM file of CA certificates verifying the servers of the https config locations, in addition to the system ones.")
+	flagSet.String(configHTTPSCertFileFlag, "",
+		"Path to a PEM file of the client certificate authenticating the requests of the https config locations.")
+	flagSet.String(configHTTPSKeyFileFlag, "",
+		"Path to a PEM file of the key of the client certificate set by --"+configHTTPSCertFileFlag+".")
+
 	reg.RegisterFlags(flagSet)
 	return flagSet
 }
@@ -57,3 +163,37 @@ func getConfigFlag(flagSet *flag.FlagSet) []string {
 	cfv := flagSet.Lookup(configFlag).Value.(*configFlagValue)
 	return append(cfv.values, cfv.sets...)
 }
+
+func getConfigMergeStrategyFlag(flagSet *flag.FlagSet) confmap.MergeStrategy {
+	return flagSet.Lookup(configMergeStrategyFlag).Value.(*mergeStrategyFlagValue).strategy
+}
+
+func getConfigMergePathFlag(flagSet *flag.FlagSet) []string {
+	if paths := flagSet.Lookup(configMergePathFlag).Value.(*mergePathFlagValue).paths; len(paths) > 0 {
+		return paths
+	}
+	return defaultMergePaths
+}
+
+// getHTTPProviderOptions returns the options of the http and https config providers set by the flags.
+func getHTTPProviderOptions(flagSet *flag.FlagSet) ([]httpprovider.Option, []httpsprovider.Option) {
+	pollInterval := flagSet.Lookup(configHTTPPollIntervalFlag).Value.(flag.Getter).Get().(time.Duration)
+	headers := flagSet.Lookup(configHTTPHeaderFlag).Value.(*headerFlagValue).headers
+	httpOpts := []httpprovider.Option{
+		httpprovider.WithPollInterval(pollInterval),
+		httpprovider.WithHeaders(headers),
+	}
+	httpsOpts := []httpsprovider.Option{
+		httpsprovider.WithPollInterval(pollInterval),
+		httpsprovider.WithHeaders(headers),
+	}
+	if caFile := flagSet.Lookup(configHTTPSCAFileFlag).Value.String(); caFile != "" {
+		httpsOpts = append(httpsOpts, httpsprovider.WithCACertPath(caFile))
+	}
+	certFile := flagSet.Lookup(configHTTPSCertFileFlag).Value.String()
+	keyFile := flagSet.Lookup(configHTTPSKeyFileFlag).Value.String()
+	if certFile != "" || keyFile != "" {
+		httpsOpts = append(httpsOpts, httpsprovider.WithClientCertificate(certFile, keyFile))
+	}
+	return httpOpts, httpsOpts
+}
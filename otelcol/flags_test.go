@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/featuregate"
 )
 
@@ -69,3 +70,120 @@ func TestSetFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigMergeStrategyFlag(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		expectedStrategy confmap.MergeStrategy
+		expectedErr      string
+	}{
+		{
+			name:             "default",
+			args:             []string{"--config=file:testdata/otelcol-nop.yaml"},
+			expectedStrategy: confmap.MergeStrategyOverride,
+		},
+		{
+			name:             "append",
+			args:             []string{"--config=file:testdata/otelcol-nop.yaml", "--config-merge-strategy=append"},
+			expectedStrategy: confmap.MergeStrategyAppend,
+		},
+		{
+			name:             "keyed",
+			args:             []string{"--config-merge-strategy=keyed", "--config=file:testdata/otelcol-nop.yaml"},
+			expectedStrategy: confmap.MergeStrategyKeyed,
+		},
+		{
+			name:        "unknown",
+			args:        []string{"--config-merge-strategy=merge"},
+			expectedErr: `invalid value "merge" for flag -config-merge-strategy: unknown merge strategy`,
+		},
+		{
+			name:        "empty",
+			args:        []string{"--config-merge-strategy="},
+			expectedErr: `invalid value "" for flag -config-merge-strategy: unknown merge strategy`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flgs := flags(featuregate.NewRegistry())
+			err := flgs.Parse(tt.args)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStrategy, getConfigMergeStrategyFlag(flgs))
+		})
+	}
+}
+
+func TestConfigMergePathFlag(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedPaths []string
+	}{
+		{
+			name:          "default",
+			args:          []string{"--config-merge-strategy=keyed"},
+			expectedPaths: defaultMergePaths,
+		},
+		{
+			name:          "paths",
+			args:          []string{"--config-merge-path=service.pipelines.*.processors", "--config-merge-path=processors::attributes::actions"},
+			expectedPaths: []string{"service::pipelines::*::processors", "processors::attributes::actions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flgs := flags(featuregate.NewRegistry())
+			require.NoError(t, flgs.Parse(tt.args))
+			assert.Equal(t, tt.expectedPaths, getConfigMergePathFlag(flgs))
+		})
+	}
+}
+
+func TestConfigHTTPHeaderFlag(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedHeaders map[string]string
+		expectedErr     string
+	}{
+		{
+			name: "default",
+			args: []string{"--config=http://localhost/config"},
+		},
+		{
+			name:            "headers",
+			args:            []string{"--config-http-header=Authorization=Bearer a=b", "--config-http-header= X-Fleet =prod"},
+			expectedHeaders: map[string]string{"Authorization": "Bearer a=b", "X-Fleet": "prod"},
+		},
+		{
+			name:        "missing equal sign",
+			args:        []string{"--config-http-header=Authorization"},
+			expectedErr: `invalid value "Authorization" for flag -config-http-header: missing equal sign`,
+		},
+		{
+			name:        "missing name",
+			args:        []string{"--config-http-header==value"},
+			expectedErr: `invalid value "=value" for flag -config-http-header: missing header name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flgs := flags(featuregate.NewRegistry())
+			err := flgs.Parse(tt.args)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHeaders, flgs.Lookup(configHTTPHeaderFlag).Value.(*headerFlagValue).headers)
+		})
+	}
+}
@@ -6,6 +6,8 @@ package otelcoltest // import "go.opentelemetry.io/collector/otelcol/otelcoltest
 import (
 	"context"
 
+	"go.uber.org/multierr"
+
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/converter/expandconverter"
 	"go.opentelemetry.io/collector/confmap/provider/envprovider"
@@ -33,7 +35,9 @@ func LoadConfig(fileName string, factories otelcol.Factories) (*otelcol.Config,
 	if err != nil {
 		return nil, err
 	}
-	return provider.Get(context.Background(), factories)
+	cfg, err := provider.Get(context.Background(), factories)
+	// Stop watching for the configuration updates.
+	return cfg, multierr.Combine(err, provider.Shutdown(context.Background()))
 }
 
 // LoadConfigAndValidate loads a config from the file, and validates the configuration.
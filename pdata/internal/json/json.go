@@ -8,8 +8,12 @@ import (
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
+	jsoniter "github.com/json-iterator/go"
 )
 
+// readerBufferSize is the size of the buffer used to read the JSON from an io.Reader.
+const readerBufferSize = 4096
+
 var marshaler = &jsonpb.Marshaler{
 	// https://github.com/open-telemetry/opentelemetry-specification/pull/2758
 	EnumsAsInts: true,
@@ -20,3 +24,9 @@ var marshaler = &jsonpb.Marshaler{
 func Marshal(out io.Writer, pb proto.Message) error {
 	return marshaler.Marshal(out, pb)
 }
+
+// NewReaderIterator returns an iterator decoding the JSON as it's read from r,
+// without reading it entirely in memory first.
+func NewReaderIterator(r io.Reader) *jsoniter.Iterator {
+	return jsoniter.Parse(jsoniter.ConfigFastest, r, readerBufferSize)
+}
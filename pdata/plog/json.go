@@ -6,6 +6,7 @@ package plog // import "go.opentelemetry.io/collector/pdata/plog"
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -44,6 +45,19 @@ func (*JSONUnmarshaler) UnmarshalLogs(buf []byte) (Logs, error) {
 	return ld, nil
 }
 
+// UnmarshalLogsFrom reads the OTLP/JSON format from r into pdata.Logs. The data is decoded
+// as it's read, without reading it entirely in memory first.
+func (*JSONUnmarshaler) UnmarshalLogsFrom(r io.Reader) (Logs, error) {
+	iter := json.NewReaderIterator(r)
+	ld := NewLogs()
+	ld.unmarshalJsoniter(iter)
+	if iter.Error != nil {
+		return Logs{}, iter.Error
+	}
+	otlp.MigrateLogs(ld.getOrig().ResourceLogs)
+	return ld, nil
+}
+
 func (ms Logs) unmarshalJsoniter(iter *jsoniter.Iterator) {
 	iter.ReadObjectCB(func(iter *jsoniter.Iterator, f string) bool {
 		switch f {
@@ -4,7 +4,9 @@
 package plog
 
 import (
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
@@ -64,6 +66,19 @@ func TestJSONUnmarshal(t *testing.T) {
 	assert.EqualValues(t, logsOTLP, got)
 }
 
+func TestJSONUnmarshalFrom(t *testing.T) {
+	decoder := &JSONUnmarshaler{}
+	// Read one byte at a time to decode the data across many buffer refills.
+	got, err := decoder.UnmarshalLogsFrom(iotest.OneByteReader(strings.NewReader(logsJSON)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, logsOTLP, got)
+
+	_, err = decoder.UnmarshalLogsFrom(strings.NewReader(`{"extra":"", "resourceLogs": "extra"}`))
+	assert.Error(t, err)
+	_, err = decoder.UnmarshalLogsFrom(strings.NewReader(logsJSON[:len(logsJSON)-1]))
+	assert.Error(t, err)
+}
+
 func TestJSONMarshal(t *testing.T) {
 	encoder := &JSONMarshaler{}
 	jsonBuf, err := encoder.MarshalLogs(logsOTLP)
@@ -37,6 +37,28 @@ func TestProtoSizerEmptyLogs(t *testing.T) {
 	assert.Equal(t, 0, sizer.LogsSize(NewLogs()))
 }
 
+func TestProtoSizerElements(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	ld := NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "foo")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope")
+	lr := sl.LogRecords().AppendEmpty()
+	lr.Body().SetStr("log")
+	lr.Attributes().PutInt("attr", 1)
+
+	bytes, err := rl.orig.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, len(bytes), marshaler.ResourceLogsSize(rl))
+	bytes, err = sl.orig.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, len(bytes), marshaler.ScopeLogsSize(sl))
+	bytes, err = lr.orig.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, len(bytes), marshaler.LogRecordSize(lr))
+}
+
 func BenchmarkLogsToProto(b *testing.B) {
 	marshaler := &ProtoMarshaler{}
 	logs := generateBenchmarkLogs(128)
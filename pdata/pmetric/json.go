@@ -6,6 +6,7 @@ package pmetric // import "go.opentelemetry.io/collector/pdata/pmetric"
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -44,6 +45,19 @@ func (*JSONUnmarshaler) UnmarshalMetrics(buf []byte) (Metrics, error) {
 	return md, nil
 }
 
+// UnmarshalMetricsFrom reads the OTLP/JSON format from r into pdata.Metrics. The data is decoded
+// as it's read, without reading it entirely in memory first.
+func (*JSONUnmarshaler) UnmarshalMetricsFrom(r io.Reader) (Metrics, error) {
+	iter := json.NewReaderIterator(r)
+	md := NewMetrics()
+	md.unmarshalJsoniter(iter)
+	if iter.Error != nil {
+		return Metrics{}, iter.Error
+	}
+	otlp.MigrateMetrics(md.getOrig().ResourceMetrics)
+	return md, nil
+}
+
 func (ms Metrics) unmarshalJsoniter(iter *jsoniter.Iterator) {
 	iter.ReadObjectCB(func(iter *jsoniter.Iterator, f string) bool {
 		switch f {
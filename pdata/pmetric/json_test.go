@@ -4,7 +4,9 @@
 package pmetric
 
 import (
+	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -39,6 +41,19 @@ func TestMetricsJSON(t *testing.T) {
 	assert.EqualValues(t, metricsOTLP, got)
 }
 
+func TestMetricsJSONFrom(t *testing.T) {
+	decoder := &JSONUnmarshaler{}
+	// Read one byte at a time to decode the data across many buffer refills.
+	got, err := decoder.UnmarshalMetricsFrom(iotest.OneByteReader(strings.NewReader(metricsJSON)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, metricsOTLP, got)
+
+	_, err = decoder.UnmarshalMetricsFrom(strings.NewReader(`{"extra":"", "resourceMetrics": "extra"}`))
+	assert.Error(t, err)
+	_, err = decoder.UnmarshalMetricsFrom(strings.NewReader(metricsJSON[:len(metricsJSON)-1]))
+	assert.Error(t, err)
+}
+
 func TestMetricsJSON_Marshal(t *testing.T) {
 	encoder := &JSONMarshaler{}
 	jsonBuf, err := encoder.MarshalMetrics(metricsOTLP)
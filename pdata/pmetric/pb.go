@@ -22,6 +22,41 @@ func (e *ProtoMarshaler) MetricsSize(md Metrics) int {
 	return pb.Size()
 }
 
+// ResourceMetricsSize returns the size in bytes of a marshaled ResourceMetrics.
+func (e *ProtoMarshaler) ResourceMetricsSize(rm ResourceMetrics) int {
+	return rm.orig.Size()
+}
+
+// ScopeMetricsSize returns the size in bytes of a marshaled ScopeMetrics.
+func (e *ProtoMarshaler) ScopeMetricsSize(sm ScopeMetrics) int {
+	return sm.orig.Size()
+}
+
+// MetricSize returns the size in bytes of a marshaled Metric.
+func (e *ProtoMarshaler) MetricSize(m Metric) int {
+	return m.orig.Size()
+}
+
+// NumberDataPointSize returns the size in bytes of a marshaled NumberDataPoint.
+func (e *ProtoMarshaler) NumberDataPointSize(ndp NumberDataPoint) int {
+	return ndp.orig.Size()
+}
+
+// HistogramDataPointSize returns the size in bytes of a marshaled HistogramDataPoint.
+func (e *ProtoMarshaler) HistogramDataPointSize(hdp HistogramDataPoint) int {
+	return hdp.orig.Size()
+}
+
+// ExponentialHistogramDataPointSize returns the size in bytes of a marshaled ExponentialHistogramDataPoint.
+func (e *ProtoMarshaler) ExponentialHistogramDataPointSize(ehdp ExponentialHistogramDataPoint) int {
+	return ehdp.orig.Size()
+}
+
+// SummaryDataPointSize returns the size in bytes of a marshaled SummaryDataPoint.
+func (e *ProtoMarshaler) SummaryDataPointSize(sdp SummaryDataPoint) int {
+	return sdp.orig.Size()
+}
+
 type ProtoUnmarshaler struct{}
 
 func (d *ProtoUnmarshaler) UnmarshalMetrics(buf []byte) (Metrics, error) {
@@ -36,6 +36,42 @@ func TestProtoSizerEmptyMetrics(t *testing.T) {
 	assert.Equal(t, 0, sizer.MetricsSize(NewMetrics()))
 }
 
+func TestProtoSizerElements(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	md := NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "foo")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("scope")
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("metric")
+	ndp := m.SetEmptySum().DataPoints().AppendEmpty()
+	ndp.SetIntValue(1)
+	hdp := sm.Metrics().AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+	ehdp := sm.Metrics().AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	ehdp.SetScale(2)
+	sdp := sm.Metrics().AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetCount(3)
+
+	for _, tt := range []struct {
+		orig interface{ Marshal() ([]byte, error) }
+		size int
+	}{
+		{orig: rm.orig, size: marshaler.ResourceMetricsSize(rm)},
+		{orig: sm.orig, size: marshaler.ScopeMetricsSize(sm)},
+		{orig: m.orig, size: marshaler.MetricSize(m)},
+		{orig: ndp.orig, size: marshaler.NumberDataPointSize(ndp)},
+		{orig: hdp.orig, size: marshaler.HistogramDataPointSize(hdp)},
+		{orig: ehdp.orig, size: marshaler.ExponentialHistogramDataPointSize(ehdp)},
+		{orig: sdp.orig, size: marshaler.SummaryDataPointSize(sdp)},
+	} {
+		bytes, err := tt.orig.Marshal()
+		require.NoError(t, err)
+		assert.Equal(t, len(bytes), tt.size)
+	}
+}
+
 func BenchmarkMetricsToProto(b *testing.B) {
 	marshaler := &ProtoMarshaler{}
 	metrics := generateBenchmarkMetrics(128)
@@ -6,6 +6,7 @@ package ptrace // import "go.opentelemetry.io/collector/pdata/ptrace"
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -42,6 +43,19 @@ func (*JSONUnmarshaler) UnmarshalTraces(buf []byte) (Traces, error) {
 	return td, nil
 }
 
+// UnmarshalTracesFrom reads the OTLP/JSON format from r into pdata.Traces. The data is decoded
+// as it's read, without reading it entirely in memory first.
+func (*JSONUnmarshaler) UnmarshalTracesFrom(r io.Reader) (Traces, error) {
+	iter := json.NewReaderIterator(r)
+	td := NewTraces()
+	td.unmarshalJsoniter(iter)
+	if iter.Error != nil {
+		return Traces{}, iter.Error
+	}
+	otlp.MigrateTraces(td.getOrig().ResourceSpans)
+	return td, nil
+}
+
 func (ms Traces) unmarshalJsoniter(iter *jsoniter.Iterator) {
 	iter.ReadObjectCB(func(iter *jsoniter.Iterator, f string) bool {
 		switch f {
@@ -4,7 +4,9 @@
 package ptrace
 
 import (
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
@@ -97,6 +99,19 @@ func TestJSONUnmarshal(t *testing.T) {
 	assert.EqualValues(t, tracesOTLP, got)
 }
 
+func TestJSONUnmarshalFrom(t *testing.T) {
+	decoder := &JSONUnmarshaler{}
+	// Read one byte at a time to decode the data across many buffer refills.
+	got, err := decoder.UnmarshalTracesFrom(iotest.OneByteReader(strings.NewReader(tracesJSON)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, tracesOTLP, got)
+
+	_, err = decoder.UnmarshalTracesFrom(strings.NewReader(`{"extra":"", "resourceSpans": "extra"}`))
+	assert.Error(t, err)
+	_, err = decoder.UnmarshalTracesFrom(strings.NewReader(tracesJSON[:len(tracesJSON)-1]))
+	assert.Error(t, err)
+}
+
 func TestJSONMarshal(t *testing.T) {
 	encoder := &JSONMarshaler{}
 	jsonBuf, err := encoder.MarshalTraces(tracesOTLP)
@@ -22,6 +22,21 @@ func (e *ProtoMarshaler) TracesSize(td Traces) int {
 	return pb.Size()
 }
 
+// ResourceSpansSize returns the size in bytes of a marshaled ResourceSpans.
+func (e *ProtoMarshaler) ResourceSpansSize(rs ResourceSpans) int {
+	return rs.orig.Size()
+}
+
+// ScopeSpansSize returns the size in bytes of a marshaled ScopeSpans.
+func (e *ProtoMarshaler) ScopeSpansSize(ss ScopeSpans) int {
+	return ss.orig.Size()
+}
+
+// SpanSize returns the size in bytes of a marshaled Span.
+func (e *ProtoMarshaler) SpanSize(span Span) int {
+	return span.orig.Size()
+}
+
 type ProtoUnmarshaler struct{}
 
 func (d *ProtoUnmarshaler) UnmarshalTraces(buf []byte) (Traces, error) {
@@ -37,6 +37,28 @@ func TestProtoSizerEmptyTraces(t *testing.T) {
 	assert.Equal(t, 0, sizer.TracesSize(NewTraces()))
 }
 
+func TestProtoSizerElements(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	td := NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "foo")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("scope")
+	span := ss.Spans().AppendEmpty()
+	span.SetName("span")
+	span.Attributes().PutInt("attr", 1)
+
+	bytes, err := rs.orig.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, len(bytes), marshaler.ResourceSpansSize(rs))
+	bytes, err = ss.orig.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, len(bytes), marshaler.ScopeSpansSize(ss))
+	bytes, err = span.orig.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, len(bytes), marshaler.SpanSize(span))
+}
+
 func BenchmarkTracesToProto(b *testing.B) {
 	marshaler := &ProtoMarshaler{}
 	traces := generateBenchmarkTraces(128)
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/bits"
 	"runtime"
 	"sort"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/client"
@@ -36,6 +38,7 @@ var errTooManyBatchers = consumererror.NewPermanent(errors.New("too many batcher
 //
 // Batches are sent out with any of the following conditions:
 // - batch size reaches cfg.SendBatchSize
+// - batch size in bytes reaches cfg.SendBatchSizeBytes
 // - cfg.Timeout is elapsed since the timestamp when the previous batch was sent out.
 type batchProcessor struct {
 	logger           *zap.Logger
@@ -43,6 +46,11 @@ type batchProcessor struct {
 	sendBatchSize    int
 	sendBatchMaxSize int
 
+	// sendBatchSizeBytes and sendBatchMaxSizeBytes are the limits of the batch size in bytes,
+	// the size of the batches is tracked only if any of them is set.
+	sendBatchSizeBytes    int
+	sendBatchMaxSizeBytes int
+
 	// batchFunc is a factory for new batch objects corresponding
 	// with the appropriate signal.
 	batchFunc func() batch
@@ -53,9 +61,22 @@ type batchProcessor struct {
 	// triggers a new batcher, counted in `goroutines`.
 	metadataKeys []string
 
+	// resourceKeys is the configured list of resource attribute keys.
+	// When non-empty, the data is split by the values of these
+	// attributes and each distinct combination of metadata and
+	// attribute values triggers a new batcher.
+	resourceKeys []string
+
+	// groupFunc splits the data in groups by the values of resourceKeys,
+	// for the appropriate signal.
+	groupFunc groupFunc
+
 	// metadataLimit is the limiting size of the batchers map.
 	metadataLimit int
 
+	// waitForExport indicates whether consume blocks until the data is exported.
+	waitForExport bool
+
 	shutdownC  chan struct{}
 	goroutines sync.WaitGroup
 
@@ -91,16 +112,47 @@ type shard struct {
 	// batch is an in-flight data item containing one of the
 	// underlying data types.
 	batch batch
+
+	// waiters are the callers waiting for the export of the data in
+	// the batch, in the order the data was added to it.
+	waiters []*waiter
+}
+
+// waiter is sent to the shard instead of the data when the processor waits
+// for the exports. The result of the export is sent to done once all the
+// items of the data are exported.
+type waiter struct {
+	data any
+	done chan error
+
+	// The fields below are only accessed by the shard goroutine.
+	count     int
+	remaining int
+	failed    int
+	err       error
+}
+
+// result returns the error of the export of the data, with the number
+// of failed items if only a part of them failed.
+func (w *waiter) result() error {
+	if w.err == nil || w.failed == w.count {
+		return w.err
+	}
+	return fmt.Errorf("failed to export %d out of %d items: %w", w.failed, w.count, w.err)
 }
 
 // batch is an interface generalizing the individual signal types.
 type batch interface {
 	// export the current batch
-	export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (sentBatchSize int, sentBatchBytes int, err error)
+	export(ctx context.Context, sendBatchMaxSize int, sendBatchMaxSizeBytes int, returnBytes bool) (sentBatchSize int, sentBatchBytes int, err error)
 
 	// itemCount returns the size of the current batch
 	itemCount() int
 
+	// byteCount returns the size of the current batch in bytes, it's tracked only if the batch is created
+	// with trackBytes set.
+	byteCount() int
+
 	// add item to the current batch
 	add(item any)
 }
@@ -110,7 +162,7 @@ var _ consumer.Metrics = (*batchProcessor)(nil)
 var _ consumer.Logs = (*batchProcessor)(nil)
 
 // newBatchProcessor returns a new batch processor component.
-func newBatchProcessor(set processor.CreateSettings, cfg *Config, batchFunc func() batch) (*batchProcessor, error) {
+func newBatchProcessor(set processor.CreateSettings, cfg *Config, batchFunc func() batch, groupFunc groupFunc) (*batchProcessor, error) {
 	// use lower-case, to be consistent with http/2 headers.
 	mks := make([]string, len(cfg.MetadataKeys))
 	for i, k := range cfg.MetadataKeys {
@@ -120,15 +172,20 @@ func newBatchProcessor(set processor.CreateSettings, cfg *Config, batchFunc func
 	bp := &batchProcessor{
 		logger: set.Logger,
 
-		sendBatchSize:    int(cfg.SendBatchSize),
-		sendBatchMaxSize: int(cfg.SendBatchMaxSize),
-		timeout:          cfg.Timeout,
-		batchFunc:        batchFunc,
-		shutdownC:        make(chan struct{}, 1),
-		metadataKeys:     mks,
-		metadataLimit:    int(cfg.MetadataCardinalityLimit),
+		sendBatchSize:         int(cfg.SendBatchSize),
+		sendBatchMaxSize:      int(cfg.SendBatchMaxSize),
+		sendBatchSizeBytes:    int(cfg.SendBatchSizeBytes),
+		sendBatchMaxSizeBytes: int(cfg.SendBatchMaxSizeBytes),
+		timeout:               cfg.Timeout,
+		batchFunc:             batchFunc,
+		shutdownC:             make(chan struct{}, 1),
+		metadataKeys:          mks,
+		resourceKeys:          cfg.GroupByResourceAttributes,
+		groupFunc:             groupFunc,
+		metadataLimit:         int(cfg.MetadataCardinalityLimit),
+		waitForExport:         cfg.WaitForExport,
 	}
-	if len(bp.metadataKeys) == 0 {
+	if len(bp.metadataKeys) == 0 && len(bp.resourceKeys) == 0 {
 		bp.batcher = &singleShardBatcher{batcher: bp.newShard(nil)}
 	} else {
 		bp.batcher = &multiShardBatcher{
@@ -202,10 +259,13 @@ func (b *shard) start() {
 				}
 			}
 			// This is the close of the channel
-			if b.batch.itemCount() > 0 {
+			// The batch can be larger than the maximum size in bytes, so it may take several exports to send it.
+			for b.batch.itemCount() > 0 {
 				// TODO: Set a timeout on sendTraces or
 				// make it cancellable using the context that Shutdown gets as a parameter
-				b.sendItems(triggerTimeout)
+				if b.sendItems(triggerTimeout) == 0 {
+					break
+				}
 			}
 			return
 		case item := <-b.newItem:
@@ -214,20 +274,69 @@ func (b *shard) start() {
 			}
 			b.processItem(item)
 		case <-timerCh:
-			if b.batch.itemCount() > 0 {
-				b.sendItems(triggerTimeout)
+			for b.batch.itemCount() > 0 {
+				if b.sendItems(triggerTimeout) == 0 {
+					break
+				}
 			}
 			b.resetTimer()
 		}
 	}
 }
 
+// consume sends the data to the shard and, if the processor waits for the
+// exports, blocks until it's exported or the context is done.
+func (b *shard) consume(ctx context.Context, data any) error {
+	return b.wait(ctx, b.send(data))
+}
+
+// send sends the data to the shard and returns the waiter for its export,
+// or nil if the processor doesn't wait for the exports.
+func (b *shard) send(data any) *waiter {
+	if !b.processor.waitForExport {
+		b.newItem <- data
+		return nil
+	}
+	w := &waiter{data: data, done: make(chan error, 1)}
+	b.newItem <- w
+	return w
+}
+
+// wait blocks until the data of the waiter is exported or the context is done.
+func (b *shard) wait(ctx context.Context, w *waiter) error {
+	if w == nil {
+		return nil
+	}
+	select {
+	case err := <-w.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (b *shard) processItem(item any) {
-	b.batch.add(item)
+	w, wait := item.(*waiter)
+	if !wait {
+		b.batch.add(item)
+	} else {
+		before := b.batch.itemCount()
+		b.batch.add(w.data)
+		w.count = b.batch.itemCount() - before
+		w.remaining = w.count
+		if w.count == 0 {
+			w.done <- nil
+		} else {
+			b.waiters = append(b.waiters, w)
+		}
+	}
 	sent := false
-	for b.batch.itemCount() > 0 && (!b.hasTimer() || b.batch.itemCount() >= b.processor.sendBatchSize) {
+	for b.batch.itemCount() > 0 && (!b.hasTimer() || b.batch.itemCount() >= b.processor.sendBatchSize || b.reachedBatchSizeBytes()) {
 		sent = true
-		b.sendItems(triggerBatchSize)
+		// Stop if nothing could be sent, rather than spinning on the same data.
+		if b.sendItems(triggerBatchSize) == 0 {
+			break
+		}
 	}
 
 	if sent {
@@ -236,6 +345,11 @@ func (b *shard) processItem(item any) {
 	}
 }
 
+// reachedBatchSizeBytes returns true if the size of the batch in bytes triggers sending it.
+func (b *shard) reachedBatchSizeBytes() bool {
+	return b.processor.sendBatchSizeBytes > 0 && b.batch.byteCount() >= b.processor.sendBatchSizeBytes
+}
+
 func (b *shard) hasTimer() bool {
 	return b.timer != nil
 }
@@ -252,13 +366,39 @@ func (b *shard) resetTimer() {
 	}
 }
 
-func (b *shard) sendItems(trigger trigger) {
-	sent, bytes, err := b.batch.export(b.exportCtx, b.processor.sendBatchMaxSize, b.processor.telemetry.detailed)
+// sendItems exports the next items of the batch and returns the number of items sent.
+func (b *shard) sendItems(trigger trigger) int {
+	sent, bytes, err := b.batch.export(b.exportCtx, b.processor.sendBatchMaxSize, b.processor.sendBatchMaxSizeBytes,
+		b.processor.telemetry.detailed)
 	if err != nil {
 		b.processor.logger.Warn("Sender failed", zap.Error(err))
 	} else {
 		b.processor.telemetry.record(trigger, int64(sent), int64(bytes))
 	}
+	b.notifyWaiters(sent, err)
+	return sent
+}
+
+// notifyWaiters attributes the result of the export of sent items to the
+// waiters, in the order their data was added to the batch, and notifies
+// the ones whose data is fully exported.
+func (b *shard) notifyWaiters(sent int, err error) {
+	for sent > 0 && len(b.waiters) > 0 {
+		w := b.waiters[0]
+		n := min(sent, w.remaining)
+		sent -= n
+		w.remaining -= n
+		if err != nil {
+			w.failed += n
+			w.err = multierr.Append(w.err, err)
+		}
+		if w.remaining > 0 {
+			return
+		}
+		b.waiters[0] = nil
+		b.waiters = b.waiters[1:]
+		w.done <- w.result()
+	}
 }
 
 // singleShardBatcher is used when metadataKeys is empty, to avoid the
@@ -267,16 +407,15 @@ type singleShardBatcher struct {
 	batcher *shard
 }
 
-func (sb *singleShardBatcher) consume(_ context.Context, data any) error {
-	sb.batcher.newItem <- data
-	return nil
+func (sb *singleShardBatcher) consume(ctx context.Context, data any) error {
+	return sb.batcher.consume(ctx, data)
 }
 
 func (sb *singleShardBatcher) currentMetadataCardinality() int {
 	return 1
 }
 
-// multiBatcher is used when metadataKeys is not empty.
+// multiBatcher is used when metadataKeys or resourceKeys is not empty.
 type multiShardBatcher struct {
 	*batchProcessor
 	batchers sync.Map
@@ -305,14 +444,50 @@ func (mb *multiShardBatcher) consume(ctx context.Context, data any) error {
 			attrs = append(attrs, attribute.StringSlice(k, vs))
 		}
 	}
-	aset := attribute.NewSet(attrs...)
 
+	if len(mb.resourceKeys) == 0 {
+		b, err := mb.getShard(attribute.NewSet(attrs...), md)
+		if err != nil {
+			return err
+		}
+		return b.consume(ctx, data)
+	}
+
+	// Split the data by the values of the resource attributes, and
+	// resolve all the shards before sending anything, so the data is
+	// either entirely accepted or refused.
+	groups := mb.groupFunc(data, mb.resourceKeys)
+	asets := make([]attribute.Set, len(groups))
+	for i, g := range groups {
+		gattrs := make([]attribute.KeyValue, 0, len(attrs)+len(g.attrs))
+		gattrs = append(gattrs, attrs...)
+		gattrs = append(gattrs, g.attrs...)
+		asets[i] = attribute.NewSet(gattrs...)
+	}
+	shards, err := mb.getShards(asets, md)
+	if err != nil {
+		return err
+	}
+	waiters := make([]*waiter, len(groups))
+	for i, g := range groups {
+		waiters[i] = shards[i].send(g.data)
+	}
+	var errs error
+	for i, w := range waiters {
+		errs = multierr.Append(errs, shards[i].wait(ctx, w))
+	}
+	return errs
+}
+
+// getShard gets or creates the shard corresponding with aset, the metadata
+// of its exports is md.
+func (mb *multiShardBatcher) getShard(aset attribute.Set, md map[string][]string) (*shard, error) {
 	b, ok := mb.batchers.Load(aset)
 	if !ok {
 		mb.lock.Lock()
 		if mb.metadataLimit != 0 && mb.size >= mb.metadataLimit {
 			mb.lock.Unlock()
-			return errTooManyBatchers
+			return nil, errTooManyBatchers
 		}
 
 		// aset.ToSlice() returns the sorted, deduplicated,
@@ -324,8 +499,51 @@ func (mb *multiShardBatcher) consume(ctx context.Context, data any) error {
 		}
 		mb.lock.Unlock()
 	}
-	b.(*shard).newItem <- data
-	return nil
+	return b.(*shard), nil
+}
+
+// getShards gets or creates the shards corresponding with asets, the metadata
+// of their exports is md. None of the shards is created if creating all the
+// missing ones would exceed the metadata cardinality limit.
+func (mb *multiShardBatcher) getShards(asets []attribute.Set, md map[string][]string) ([]*shard, error) {
+	shards := make([]*shard, len(asets))
+	var missing []int
+	for i, aset := range asets {
+		if b, ok := mb.batchers.Load(aset); ok {
+			shards[i] = b.(*shard)
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return shards, nil
+	}
+
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	// The shards are created with the lock held, so the ones created since
+	// the lookup above are found here.
+	n := 0
+	for _, i := range missing {
+		if b, ok := mb.batchers.Load(asets[i]); ok {
+			shards[i] = b.(*shard)
+		} else {
+			missing[n] = i
+			n++
+		}
+	}
+	missing = missing[:n]
+	if mb.metadataLimit != 0 && mb.size+len(missing) > mb.metadataLimit {
+		return nil, errTooManyBatchers
+	}
+	for _, i := range missing {
+		b, loaded := mb.batchers.LoadOrStore(asets[i], mb.newShard(md))
+		if !loaded {
+			mb.size++
+		}
+		shards[i] = b.(*shard)
+	}
+	return shards, nil
 }
 
 func (mb *multiShardBatcher) currentMetadataCardinality() int {
@@ -351,28 +569,34 @@ func (bp *batchProcessor) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 
 // newBatchTracesProcessor creates a new batch processor that batches traces by size or with timeout
 func newBatchTracesProcessor(set processor.CreateSettings, next consumer.Traces, cfg *Config) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, func() batch { return newBatchTraces(next) })
+	trackBytes := cfg.SendBatchSizeBytes > 0 || cfg.SendBatchMaxSizeBytes > 0
+	return newBatchProcessor(set, cfg, func() batch { return newBatchTraces(next, trackBytes) }, groupTraces)
 }
 
 // newBatchMetricsProcessor creates a new batch processor that batches metrics by size or with timeout
 func newBatchMetricsProcessor(set processor.CreateSettings, next consumer.Metrics, cfg *Config) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, func() batch { return newBatchMetrics(next) })
+	trackBytes := cfg.SendBatchSizeBytes > 0 || cfg.SendBatchMaxSizeBytes > 0
+	return newBatchProcessor(set, cfg, func() batch { return newBatchMetrics(next, trackBytes) }, groupMetrics)
 }
 
 // newBatchLogsProcessor creates a new batch processor that batches logs by size or with timeout
 func newBatchLogsProcessor(set processor.CreateSettings, next consumer.Logs, cfg *Config) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, func() batch { return newBatchLogs(next) })
+	trackBytes := cfg.SendBatchSizeBytes > 0 || cfg.SendBatchMaxSizeBytes > 0
+	return newBatchProcessor(set, cfg, func() batch { return newBatchLogs(next, trackBytes) }, groupLogs)
 }
 
 type batchTraces struct {
 	nextConsumer consumer.Traces
 	traceData    ptrace.Traces
 	spanCount    int
-	sizer        ptrace.Sizer
+	sizer        *ptrace.ProtoMarshaler
+	trackBytes   bool
+	bytesCount   int
 }
 
-func newBatchTraces(nextConsumer consumer.Traces) *batchTraces {
-	return &batchTraces{nextConsumer: nextConsumer, traceData: ptrace.NewTraces(), sizer: &ptrace.ProtoMarshaler{}}
+func newBatchTraces(nextConsumer consumer.Traces, trackBytes bool) *batchTraces {
+	return &batchTraces{nextConsumer: nextConsumer, traceData: ptrace.NewTraces(), sizer: &ptrace.ProtoMarshaler{},
+		trackBytes: trackBytes}
 }
 
 // add updates current batchTraces by adding new TraceData object
@@ -384,24 +608,38 @@ func (bt *batchTraces) add(item any) {
 	}
 
 	bt.spanCount += newSpanCount
+	if bt.trackBytes {
+		bt.bytesCount += bt.sizer.TracesSize(td)
+	}
 	td.ResourceSpans().MoveAndAppendTo(bt.traceData.ResourceSpans())
 }
 
-func (bt *batchTraces) export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (int, int, error) {
+func (bt *batchTraces) export(ctx context.Context, sendBatchMaxSize int, sendBatchMaxSizeBytes int, returnBytes bool) (int, int, error) {
 	var req ptrace.Traces
 	var sent int
 	var bytes int
-	if sendBatchMaxSize > 0 && bt.itemCount() > sendBatchMaxSize {
+	switch {
+	case bt.trackBytes && (sendBatchMaxSizeBytes > 0 && bt.bytesCount > sendBatchMaxSizeBytes ||
+		sendBatchMaxSize > 0 && bt.spanCount > sendBatchMaxSize):
+		var removedBytes int
+		req, bytes, removedBytes = splitTracesBytes(sendBatchMaxSize, sendBatchMaxSizeBytes, bt.traceData, bt.sizer)
+		sent = req.SpanCount()
+		bt.spanCount -= sent
+		bt.bytesCount -= removedBytes
+	case sendBatchMaxSize > 0 && bt.itemCount() > sendBatchMaxSize:
 		req = splitTraces(sendBatchMaxSize, bt.traceData)
 		bt.spanCount -= sendBatchMaxSize
 		sent = sendBatchMaxSize
-	} else {
+	default:
 		req = bt.traceData
 		sent = bt.spanCount
+		bytes = bt.bytesCount
 		bt.traceData = ptrace.NewTraces()
 		bt.spanCount = 0
+		bt.bytesCount = 0
 	}
-	if returnBytes {
+	// The size of the data is tracked incrementally if trackBytes is set.
+	if returnBytes && !bt.trackBytes {
 		bytes = bt.sizer.TracesSize(req)
 	}
 	return sent, bytes, bt.nextConsumer.ConsumeTraces(ctx, req)
@@ -411,32 +649,50 @@ func (bt *batchTraces) itemCount() int {
 	return bt.spanCount
 }
 
+func (bt *batchTraces) byteCount() int {
+	return bt.bytesCount
+}
+
 type batchMetrics struct {
 	nextConsumer   consumer.Metrics
 	metricData     pmetric.Metrics
 	dataPointCount int
-	sizer          pmetric.Sizer
+	sizer          *pmetric.ProtoMarshaler
+	trackBytes     bool
+	bytesCount     int
 }
 
-func newBatchMetrics(nextConsumer consumer.Metrics) *batchMetrics {
-	return &batchMetrics{nextConsumer: nextConsumer, metricData: pmetric.NewMetrics(), sizer: &pmetric.ProtoMarshaler{}}
+func newBatchMetrics(nextConsumer consumer.Metrics, trackBytes bool) *batchMetrics {
+	return &batchMetrics{nextConsumer: nextConsumer, metricData: pmetric.NewMetrics(), sizer: &pmetric.ProtoMarshaler{},
+		trackBytes: trackBytes}
 }
 
-func (bm *batchMetrics) export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (int, int, error) {
+func (bm *batchMetrics) export(ctx context.Context, sendBatchMaxSize int, sendBatchMaxSizeBytes int, returnBytes bool) (int, int, error) {
 	var req pmetric.Metrics
 	var sent int
 	var bytes int
-	if sendBatchMaxSize > 0 && bm.dataPointCount > sendBatchMaxSize {
+	switch {
+	case bm.trackBytes && (sendBatchMaxSizeBytes > 0 && bm.bytesCount > sendBatchMaxSizeBytes ||
+		sendBatchMaxSize > 0 && bm.dataPointCount > sendBatchMaxSize):
+		var removedBytes int
+		req, bytes, removedBytes = splitMetricsBytes(sendBatchMaxSize, sendBatchMaxSizeBytes, bm.metricData, bm.sizer)
+		sent = req.DataPointCount()
+		bm.dataPointCount -= sent
+		bm.bytesCount -= removedBytes
+	case sendBatchMaxSize > 0 && bm.dataPointCount > sendBatchMaxSize:
 		req = splitMetrics(sendBatchMaxSize, bm.metricData)
 		bm.dataPointCount -= sendBatchMaxSize
 		sent = sendBatchMaxSize
-	} else {
+	default:
 		req = bm.metricData
 		sent = bm.dataPointCount
+		bytes = bm.bytesCount
 		bm.metricData = pmetric.NewMetrics()
 		bm.dataPointCount = 0
+		bm.bytesCount = 0
 	}
-	if returnBytes {
+	// The size of the data is tracked incrementally if trackBytes is set.
+	if returnBytes && !bm.trackBytes {
 		bytes = bm.sizer.MetricsSize(req)
 	}
 	return sent, bytes, bm.nextConsumer.ConsumeMetrics(ctx, req)
@@ -446,6 +702,10 @@ func (bm *batchMetrics) itemCount() int {
 	return bm.dataPointCount
 }
 
+func (bm *batchMetrics) byteCount() int {
+	return bm.bytesCount
+}
+
 func (bm *batchMetrics) add(item any) {
 	md := item.(pmetric.Metrics)
 
@@ -454,6 +714,9 @@ func (bm *batchMetrics) add(item any) {
 		return
 	}
 	bm.dataPointCount += newDataPointCount
+	if bm.trackBytes {
+		bm.bytesCount += bm.sizer.MetricsSize(md)
+	}
 	md.ResourceMetrics().MoveAndAppendTo(bm.metricData.ResourceMetrics())
 }
 
@@ -461,29 +724,43 @@ type batchLogs struct {
 	nextConsumer consumer.Logs
 	logData      plog.Logs
 	logCount     int
-	sizer        plog.Sizer
+	sizer        *plog.ProtoMarshaler
+	trackBytes   bool
+	bytesCount   int
 }
 
-func newBatchLogs(nextConsumer consumer.Logs) *batchLogs {
-	return &batchLogs{nextConsumer: nextConsumer, logData: plog.NewLogs(), sizer: &plog.ProtoMarshaler{}}
+func newBatchLogs(nextConsumer consumer.Logs, trackBytes bool) *batchLogs {
+	return &batchLogs{nextConsumer: nextConsumer, logData: plog.NewLogs(), sizer: &plog.ProtoMarshaler{},
+		trackBytes: trackBytes}
 }
 
-func (bl *batchLogs) export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (int, int, error) {
+func (bl *batchLogs) export(ctx context.Context, sendBatchMaxSize int, sendBatchMaxSizeBytes int, returnBytes bool) (int, int, error) {
 	var req plog.Logs
 	var sent int
 	var bytes int
 
-	if sendBatchMaxSize > 0 && bl.logCount > sendBatchMaxSize {
+	switch {
+	case bl.trackBytes && (sendBatchMaxSizeBytes > 0 && bl.bytesCount > sendBatchMaxSizeBytes ||
+		sendBatchMaxSize > 0 && bl.logCount > sendBatchMaxSize):
+		var removedBytes int
+		req, bytes, removedBytes = splitLogsBytes(sendBatchMaxSize, sendBatchMaxSizeBytes, bl.logData, bl.sizer)
+		sent = req.LogRecordCount()
+		bl.logCount -= sent
+		bl.bytesCount -= removedBytes
+	case sendBatchMaxSize > 0 && bl.logCount > sendBatchMaxSize:
 		req = splitLogs(sendBatchMaxSize, bl.logData)
 		bl.logCount -= sendBatchMaxSize
 		sent = sendBatchMaxSize
-	} else {
+	default:
 		req = bl.logData
 		sent = bl.logCount
+		bytes = bl.bytesCount
 		bl.logData = plog.NewLogs()
 		bl.logCount = 0
+		bl.bytesCount = 0
 	}
-	if returnBytes {
+	// The size of the data is tracked incrementally if trackBytes is set.
+	if returnBytes && !bl.trackBytes {
 		bytes = bl.sizer.LogsSize(req)
 	}
 	return sent, bytes, bl.nextConsumer.ConsumeLogs(ctx, req)
@@ -493,6 +770,10 @@ func (bl *batchLogs) itemCount() int {
 	return bl.logCount
 }
 
+func (bl *batchLogs) byteCount() int {
+	return bl.bytesCount
+}
+
 func (bl *batchLogs) add(item any) {
 	ld := item.(plog.Logs)
 
@@ -501,5 +782,38 @@ func (bl *batchLogs) add(item any) {
 		return
 	}
 	bl.logCount += newLogsCount
+	if bl.trackBytes {
+		bl.bytesCount += bl.sizer.LogsSize(ld)
+	}
 	ld.ResourceLogs().MoveAndAppendTo(bl.logData.ResourceLogs())
 }
+
+// splitLimits tracks the number of items and the size in bytes of the data split off by the split*Bytes functions.
+type splitLimits struct {
+	// maxSize and maxBytes are the limits of the split data, zero meaning no limit.
+	maxSize  int
+	maxBytes int
+	count    int
+	bytes    int
+}
+
+// fits returns whether the given number of items fits in the limits along with the items already split off,
+// when the split data takes the given number of bytes in addition to the bytes already accounted.
+// The first item always fits, so the data is split even if a single item is over the limit in bytes.
+func (l *splitLimits) fits(count, bytes int) bool {
+	if l.count+count == 1 {
+		return true
+	}
+	return (l.maxSize <= 0 || l.count+count <= l.maxSize) && (l.maxBytes <= 0 || l.bytes+bytes <= l.maxBytes)
+}
+
+func (l *splitLimits) add(count, bytes int) {
+	l.count += count
+	l.bytes += bytes
+}
+
+// delimitedSize returns the size in bytes of a marshaled message of the given size embedded in another message,
+// including the field tag, assumed to take a single byte, and the length.
+func delimitedSize(size int) int {
+	return 1 + (bits.Len64(uint64(size)|1)+6)/7 + size
+}
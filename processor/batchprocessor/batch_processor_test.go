@@ -5,8 +5,10 @@ package batchprocessor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -452,6 +454,159 @@ func testBatchMetricProcessorBatchSize(t *testing.T, tel testTelemetry) {
 	})
 }
 
+func TestBatchProcessorSendBatchSizeBytes(t *testing.T) {
+	sizer := &ptrace.ProtoMarshaler{}
+	sink := new(consumertest.TracesSink)
+	requestSize := sizer.TracesSize(testdata.GenerateTraces(100))
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1000000
+	cfg.SendBatchSizeBytes = uint32(4 * requestSize)
+	cfg.SendBatchMaxSizeBytes = uint32(5 * requestSize)
+	cfg.Timeout = time.Hour
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 10
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraces(100)))
+	}
+
+	// The batches are sent once their size in bytes is reached, before the timeout.
+	assert.Eventually(t, func() bool {
+		return sink.SpanCount() >= 800
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*100, sink.SpanCount())
+	for _, td := range sink.AllTraces() {
+		assert.LessOrEqual(t, sizer.TracesSize(td), int(cfg.SendBatchMaxSizeBytes))
+	}
+}
+
+func TestBatchProcessorSendBatchMaxSizeBytes(t *testing.T) {
+	sizer := &plog.ProtoMarshaler{}
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1000
+	cfg.SendBatchMaxSizeBytes = uint32(sizer.LogsSize(testdata.GenerateLogs(1000)) / 4)
+	batcher, err := newBatchLogsProcessor(processortest.NewNopCreateSettings(), sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, batcher.ConsumeLogs(context.Background(), testdata.GenerateLogs(1000)))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, 1000, sink.LogRecordCount())
+	require.GreaterOrEqual(t, len(sink.AllLogs()), 4)
+	for _, ld := range sink.AllLogs() {
+		assert.LessOrEqual(t, sizer.LogsSize(ld), int(cfg.SendBatchMaxSizeBytes))
+	}
+}
+
+func TestBatchProcessorSendBatchMaxSizeBytesEmptyResource(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1000
+	cfg.SendBatchMaxSizeBytes = 200
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The first resource has no spans and is larger than the maximum size in bytes.
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("large", strings.Repeat("x", 1000))
+	testdata.GenerateTraces(5).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.Equal(t, 5, sink.SpanCount())
+	for _, sent := range sink.AllTraces() {
+		assert.Positive(t, sent.SpanCount())
+	}
+}
+
+func TestBatchProcessorWaitForExport(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 50
+	cfg.Timeout = 10 * time.Millisecond
+	cfg.WaitForExport = true
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraces(20)))
+		}()
+	}
+	wg.Wait()
+	// The data is exported once the callers return.
+	assert.Equal(t, 200, sink.SpanCount())
+
+	// Empty data doesn't wait for any export.
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), ptrace.NewTraces()))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+}
+
+func TestBatchProcessorWaitForExport_Error(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.WaitForExport = true
+	cfg.MetadataKeys = []string{"token"}
+	batcher, err := newBatchLogsProcessor(processortest.NewNopCreateSettings(),
+		consumertest.NewErr(consumererror.NewPermanent(errors.New("rejected"))), cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	err = batcher.ConsumeLogs(context.Background(), testdata.GenerateLogs(10))
+	assert.EqualError(t, err, "Permanent error: rejected")
+	assert.True(t, consumererror.IsPermanent(err))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+}
+
+func TestBatchProcessorWaitForExport_PartialFailure(t *testing.T) {
+	calls := 0
+	next, err := consumer.NewTraces(func(context.Context, ptrace.Traces) error {
+		calls++
+		if calls == 2 {
+			return errors.New("export failed")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 0
+	cfg.SendBatchMaxSize = 10
+	cfg.WaitForExport = true
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), next, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The data is split in 3 batches, and the second one fails.
+	assert.EqualError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraces(25)),
+		"failed to export 10 out of 25 items: export failed")
+	assert.Equal(t, 3, calls)
+	require.NoError(t, batcher.Shutdown(context.Background()))
+}
+
+func TestBatchProcessorWaitForExport_ContextDone(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Timeout = time.Hour
+	cfg.WaitForExport = true
+	batcher, err := newBatchMetricsProcessor(processortest.NewNopCreateSettings(), consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, batcher.ConsumeMetrics(ctx, testdata.GenerateMetrics(1)), context.DeadlineExceeded)
+	require.NoError(t, batcher.Shutdown(context.Background()))
+}
+
 func TestBatchMetrics_UnevenBatchMaxSize(t *testing.T) {
 	ctx := context.Background()
 	sink := new(metricsSink)
@@ -459,12 +614,12 @@ func TestBatchMetrics_UnevenBatchMaxSize(t *testing.T) {
 	dataPointsPerMetric := 2
 	sendBatchMaxSize := 99
 
-	batchMetrics := newBatchMetrics(sink)
+	batchMetrics := newBatchMetrics(sink, false)
 	md := testdata.GenerateMetrics(metricsCount)
 
 	batchMetrics.add(md)
 	require.Equal(t, dataPointsPerMetric*metricsCount, batchMetrics.dataPointCount)
-	sent, _, sendErr := batchMetrics.export(ctx, sendBatchMaxSize, false)
+	sent, _, sendErr := batchMetrics.export(ctx, sendBatchMaxSize, 0, false)
 	require.NoError(t, sendErr)
 	require.Equal(t, sendBatchMaxSize, sent)
 	remainingDataPointCount := metricsCount*dataPointsPerMetric - sendBatchMaxSize
@@ -1032,6 +1187,151 @@ func TestBatchProcessorMetadataCardinalityLimit(t *testing.T) {
 	require.NoError(t, batcher.Shutdown(context.Background()))
 }
 
+func TestBatchProcessorSpansGroupedByResourceAttributes(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1000
+	cfg.Timeout = 10 * time.Minute
+	cfg.GroupByResourceAttributes = []string{"tenant.id"}
+	creationSet := processortest.NewNopCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	tenants := []string{"a", "b", "c"}
+	requestCount := 30
+	spansPerResource := 5
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		td := ptrace.NewTraces()
+		for _, tenant := range tenants {
+			rs := testdata.GenerateTraces(spansPerResource).ResourceSpans().At(0)
+			rs.Resource().Attributes().PutStr("tenant.id", tenant)
+			rs.MoveTo(td.ResourceSpans().AppendEmpty())
+		}
+		// A resource without the attribute forms its own group.
+		testdata.GenerateTraces(spansPerResource).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerResource*(len(tenants)+1), sink.SpanCount())
+	spansByTenant := map[string]int{}
+	for _, td := range sink.AllTraces() {
+		var batchTenant *string
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			rs := td.ResourceSpans().At(i)
+			tenant := "<unset>"
+			if v, ok := rs.Resource().Attributes().Get("tenant.id"); ok {
+				tenant = v.Str()
+			}
+			if batchTenant == nil {
+				batchTenant = &tenant
+			}
+			require.Equal(t, *batchTenant, tenant, "every batch must contain a single tenant")
+			spansByTenant[tenant] += rs.ScopeSpans().At(0).Spans().Len()
+		}
+	}
+	for _, tenant := range append(tenants, "<unset>") {
+		assert.Equal(t, requestCount*spansPerResource, spansByTenant[tenant])
+	}
+	assert.Equal(t, len(tenants)+1, batcher.batcher.currentMetadataCardinality())
+}
+
+func TestBatchProcessorGroupByResourceAttributesCardinalityLimit(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.GroupByResourceAttributes = []string{"tenant.id"}
+	cfg.MetadataCardinalityLimit = 2
+	creationSet := processortest.NewNopCreateSettings()
+	batcher, err := newBatchLogsProcessor(creationSet, sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	newLogs := func(tenants ...string) plog.Logs {
+		ld := plog.NewLogs()
+		for _, tenant := range tenants {
+			rl := testdata.GenerateLogs(1).ResourceLogs().At(0)
+			rl.Resource().Attributes().PutStr("tenant.id", tenant)
+			rl.MoveTo(ld.ResourceLogs().AppendEmpty())
+		}
+		return ld
+	}
+	assert.NoError(t, batcher.ConsumeLogs(context.Background(), newLogs("a", "b")))
+
+	err = batcher.ConsumeLogs(context.Background(), newLogs("a", "c"))
+	assert.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Contains(t, err.Error(), "too many")
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	// The data is refused entirely when any of its groups exceeds the limit.
+	assert.Equal(t, 2, sink.LogRecordCount())
+}
+
+func TestBatchProcessorGroupByResourceAttributesRefusedGroupsNotCreated(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.GroupByResourceAttributes = []string{"tenant.id"}
+	cfg.MetadataCardinalityLimit = 2
+	creationSet := processortest.NewNopCreateSettings()
+	batcher, err := newBatchLogsProcessor(creationSet, sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	newLogs := func(tenants ...string) plog.Logs {
+		ld := plog.NewLogs()
+		for _, tenant := range tenants {
+			rl := testdata.GenerateLogs(1).ResourceLogs().At(0)
+			rl.Resource().Attributes().PutStr("tenant.id", tenant)
+			rl.MoveTo(ld.ResourceLogs().AppendEmpty())
+		}
+		return ld
+	}
+	assert.NoError(t, batcher.ConsumeLogs(context.Background(), newLogs("a")))
+	assert.Error(t, batcher.ConsumeLogs(context.Background(), newLogs("b", "c")))
+	// No shard is created for the groups of the refused data, so they don't take the room left.
+	assert.Equal(t, 1, batcher.batcher.currentMetadataCardinality())
+	assert.NoError(t, batcher.ConsumeLogs(context.Background(), newLogs("c")))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, 2, sink.LogRecordCount())
+}
+
+func TestBatchProcessorGroupByResourceAttributesWaitForExport(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.GroupByResourceAttributes = []string{"service.name"}
+	cfg.WaitForExport = true
+	creationSet := processortest.NewNopCreateSettings()
+	batcher, err := newBatchMetricsProcessor(creationSet, sink, cfg)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	md := pmetric.NewMetrics()
+	for _, service := range []string{"a", "b"} {
+		rm := testdata.GenerateMetrics(2).ResourceMetrics().At(0)
+		rm.Resource().Attributes().PutStr("service.name", service)
+		rm.MoveTo(md.ResourceMetrics().AppendEmpty())
+	}
+	dataPoints := md.DataPointCount()
+	require.NoError(t, batcher.ConsumeMetrics(context.Background(), md))
+	// The call returns once the data of all the groups is exported.
+	assert.Equal(t, dataPoints, sink.DataPointCount())
+	assert.Len(t, sink.AllMetrics(), 2)
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+}
+
+func TestBatchProcessorDuplicateGroupByResourceAttributes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.GroupByResourceAttributes = []string{"tenant.id", "tenant.id"}
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate")
+	require.Contains(t, err.Error(), "tenant.id")
+}
+
 func TestBatchZeroConfig(t *testing.T) {
 	// This is a no-op configuration. No need for a timer, no
 	// minimum, no maximum, just a pass through.
@@ -28,6 +28,15 @@ type Config struct {
 	// Default value is 0, that means no maximum size.
 	SendBatchMaxSize uint32 `mapstructure:"send_batch_max_size"`
 
+	// SendBatchSizeBytes is the size of a batch in bytes, as encoded in the OTLP protobuf format,
+	// which after hit, will trigger it to be sent. Default value is 0, that means the size in bytes is ignored.
+	SendBatchSizeBytes uint32 `mapstructure:"send_batch_size_bytes"`
+
+	// SendBatchMaxSizeBytes is the maximum size of a batch in bytes, as encoded in the OTLP protobuf format.
+	// It must be larger than SendBatchSizeBytes. Larger batches are split into smaller units.
+	// Default value is 0, that means no maximum size in bytes.
+	SendBatchMaxSizeBytes uint32 `mapstructure:"send_batch_max_size_bytes"`
+
 	// MetadataKeys is a list of client.Metadata keys that will be
 	// used to form distinct batchers.  If this setting is empty,
 	// a single batcher instance will be used.  When this setting
@@ -44,6 +53,25 @@ type Config struct {
 	// batcher instances that will be created through a distinct
 	// combination of MetadataKeys.
 	MetadataCardinalityLimit uint32 `mapstructure:"metadata_cardinality_limit"`
+
+	// GroupByResourceAttributes is a list of resource attribute keys
+	// that will be used to form distinct batchers, in addition to
+	// MetadataKeys.  When this setting is not empty, the data is split
+	// by the values of the listed attributes of its resources, and one
+	// batcher will be used per distinct combination of values, so every
+	// batch only contains resources with the same values.
+	//
+	// Empty value and unset attributes are treated as distinct cases.
+	//
+	// Entries are case-sensitive.  Duplicated entries will trigger a
+	// validation error.  The number of batchers is limited by
+	// MetadataCardinalityLimit.
+	GroupByResourceAttributes []string `mapstructure:"group_by_resource_attributes"`
+
+	// WaitForExport indicates whether the callers block until the batches containing their data
+	// are exported, and get the export errors back. Default value is false, that means the data is
+	// exported asynchronously and the export errors are only logged.
+	WaitForExport bool `mapstructure:"wait_for_export"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -53,6 +81,9 @@ func (cfg *Config) Validate() error {
 	if cfg.SendBatchMaxSize > 0 && cfg.SendBatchMaxSize < cfg.SendBatchSize {
 		return errors.New("send_batch_max_size must be greater or equal to send_batch_size")
 	}
+	if cfg.SendBatchMaxSizeBytes > 0 && cfg.SendBatchMaxSizeBytes < cfg.SendBatchSizeBytes {
+		return errors.New("send_batch_max_size_bytes must be greater or equal to send_batch_size_bytes")
+	}
 	uniq := map[string]bool{}
 	for _, k := range cfg.MetadataKeys {
 		l := strings.ToLower(k)
@@ -61,6 +92,13 @@ func (cfg *Config) Validate() error {
 		}
 		uniq[l] = true
 	}
+	uniqAttrs := map[string]bool{}
+	for _, k := range cfg.GroupByResourceAttributes {
+		if _, has := uniqAttrs[k]; has {
+			return fmt.Errorf("duplicate entry in group_by_resource_attributes: %q", k)
+		}
+		uniqAttrs[k] = true
+	}
 	if cfg.Timeout < 0 {
 		return errors.New("timeout must be greater or equal to 0")
 	}
@@ -63,6 +63,17 @@ func TestValidateConfig_InvalidBatchSize(t *testing.T) {
 	assert.Error(t, cfg.Validate())
 }
 
+func TestValidateConfig_InvalidBatchSizeBytes(t *testing.T) {
+	cfg := &Config{
+		SendBatchSizeBytes:    1000,
+		SendBatchMaxSizeBytes: 100,
+	}
+	assert.EqualError(t, cfg.Validate(), "send_batch_max_size_bytes must be greater or equal to send_batch_size_bytes")
+
+	cfg.SendBatchMaxSizeBytes = 0
+	assert.NoError(t, cfg.Validate())
+}
+
 func TestValidateConfig_InvalidTimeout(t *testing.T) {
 	cfg := &Config{
 		Timeout: -time.Second,
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package batchprocessor // import "go.opentelemetry.io/collector/processor/batchprocessor"
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// resourceAttributePrefix prefixes the resource attribute keys in the attribute set identifying
+// the shards, so they are distinct from the metadata keys.
+const resourceAttributePrefix = "resource."
+
+// resourceGroup is the part of the data whose resources have the same values of the grouping attributes.
+type resourceGroup struct {
+	attrs []attribute.KeyValue
+	data  any
+}
+
+// groupFunc splits the data in resource groups by the values of the given resource attributes.
+type groupFunc func(data any, keys []string) []resourceGroup
+
+// groupResources returns the grouping attributes of every group and the index of the group of every resource.
+// Missing resource attributes are left out of the attributes, so they are distinct from empty values.
+func groupResources(n int, resource func(i int) pcommon.Resource, keys []string) ([][]attribute.KeyValue, []int) {
+	var groups [][]attribute.KeyValue
+	groupOf := make([]int, n)
+	index := map[attribute.Distinct]int{}
+	for i := 0; i < n; i++ {
+		attrs := make([]attribute.KeyValue, 0, len(keys))
+		for _, k := range keys {
+			if v, ok := resource(i).Attributes().Get(k); ok {
+				attrs = append(attrs, attribute.String(resourceAttributePrefix+k, v.AsString()))
+			}
+		}
+		set := attribute.NewSet(attrs...)
+		idx, ok := index[set.Equivalent()]
+		if !ok {
+			idx = len(groups)
+			index[set.Equivalent()] = idx
+			groups = append(groups, attrs)
+		}
+		groupOf[i] = idx
+	}
+	return groups, groupOf
+}
+
+func groupTraces(data any, keys []string) []resourceGroup {
+	td := data.(ptrace.Traces)
+	rss := td.ResourceSpans()
+	groups, groupOf := groupResources(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() }, keys)
+	if len(groups) <= 1 {
+		return []resourceGroup{{attrs: firstGroup(groups), data: td}}
+	}
+	dests := make([]ptrace.Traces, len(groups))
+	for i := range dests {
+		dests[i] = ptrace.NewTraces()
+	}
+	for i := 0; i < rss.Len(); i++ {
+		rss.At(i).MoveTo(dests[groupOf[i]].ResourceSpans().AppendEmpty())
+	}
+	res := make([]resourceGroup, len(groups))
+	for i, attrs := range groups {
+		res[i] = resourceGroup{attrs: attrs, data: dests[i]}
+	}
+	return res
+}
+
+func groupMetrics(data any, keys []string) []resourceGroup {
+	md := data.(pmetric.Metrics)
+	rms := md.ResourceMetrics()
+	groups, groupOf := groupResources(rms.Len(), func(i int) pcommon.Resource { return rms.At(i).Resource() }, keys)
+	if len(groups) <= 1 {
+		return []resourceGroup{{attrs: firstGroup(groups), data: md}}
+	}
+	dests := make([]pmetric.Metrics, len(groups))
+	for i := range dests {
+		dests[i] = pmetric.NewMetrics()
+	}
+	for i := 0; i < rms.Len(); i++ {
+		rms.At(i).MoveTo(dests[groupOf[i]].ResourceMetrics().AppendEmpty())
+	}
+	res := make([]resourceGroup, len(groups))
+	for i, attrs := range groups {
+		res[i] = resourceGroup{attrs: attrs, data: dests[i]}
+	}
+	return res
+}
+
+func groupLogs(data any, keys []string) []resourceGroup {
+	ld := data.(plog.Logs)
+	rls := ld.ResourceLogs()
+	groups, groupOf := groupResources(rls.Len(), func(i int) pcommon.Resource { return rls.At(i).Resource() }, keys)
+	if len(groups) <= 1 {
+		return []resourceGroup{{attrs: firstGroup(groups), data: ld}}
+	}
+	dests := make([]plog.Logs, len(groups))
+	for i := range dests {
+		dests[i] = plog.NewLogs()
+	}
+	for i := 0; i < rls.Len(); i++ {
+		rls.At(i).MoveTo(dests[groupOf[i]].ResourceLogs().AppendEmpty())
+	}
+	res := make([]resourceGroup, len(groups))
+	for i, attrs := range groups {
+		res[i] = resourceGroup{attrs: attrs, data: dests[i]}
+	}
+	return res
+}
+
+// firstGroup returns the attributes of the only group, or none if the data is empty.
+func firstGroup(groups [][]attribute.KeyValue) []attribute.KeyValue {
+	if len(groups) == 0 {
+		return nil
+	}
+	return groups[0]
+}
@@ -64,6 +64,104 @@ func splitLogs(size int, src plog.Logs) plog.Logs {
 	return dest
 }
 
+// splitLogsBytes removes log records from the input logs and returns new logs of at most maxBytes bytes and
+// at most maxSize log records, zero meaning no limit, along with their size in bytes and the number of bytes removed
+// from src. The returned logs have at least one log record even if it doesn't fit in maxBytes. The size is computed
+// incrementally from the sizes of the resources, scopes and log records as they are moved, so every log record
+// is sized at most once.
+func splitLogsBytes(maxSize, maxBytes int, src plog.Logs, sizer *plog.ProtoMarshaler) (plog.Logs, int, int) {
+	dest := plog.NewLogs()
+	limits := splitLimits{maxSize: maxSize, maxBytes: maxBytes}
+	removedBytes := 0
+	full := false
+
+	src.ResourceLogs().RemoveIf(func(srcRl plog.ResourceLogs) bool {
+		// If we are done skip everything else.
+		if full {
+			return false
+		}
+
+		// If it fully fits
+		rlSize := delimitedSize(sizer.ResourceLogsSize(srcRl))
+		rlLRC := resourceLRC(srcRl)
+		if limits.fits(rlLRC, rlSize) {
+			limits.add(rlLRC, rlSize)
+			removedBytes += rlSize
+			srcRl.MoveTo(dest.ResourceLogs().AppendEmpty())
+			return true
+		}
+		// Drop the empty resources which don't fit, so every split makes progress.
+		if rlLRC == 0 {
+			removedBytes += rlSize
+			return true
+		}
+
+		destRl := plog.NewResourceLogs()
+		srcRl.Resource().CopyTo(destRl.Resource())
+		destRl.SetSchemaUrl(srcRl.SchemaUrl())
+		destRlSize := sizer.ResourceLogsSize(destRl)
+		srcRl.ScopeLogs().RemoveIf(func(srcSl plog.ScopeLogs) bool {
+			// If we are done skip everything else.
+			if full {
+				return false
+			}
+
+			// If possible to move all log records do that.
+			slSize := delimitedSize(sizer.ScopeLogsSize(srcSl))
+			slLRC := srcSl.LogRecords().Len()
+			if limits.fits(slLRC, delimitedSize(destRlSize+slSize)) {
+				limits.count += slLRC
+				destRlSize += slSize
+				srcSl.MoveTo(destRl.ScopeLogs().AppendEmpty())
+				return true
+			}
+			if slLRC == 0 {
+				return true
+			}
+
+			full = true
+			destSl := plog.NewScopeLogs()
+			srcSl.Scope().CopyTo(destSl.Scope())
+			destSl.SetSchemaUrl(srcSl.SchemaUrl())
+			destSlSize := sizer.ScopeLogsSize(destSl)
+			stop := false
+			srcSl.LogRecords().RemoveIf(func(srcLr plog.LogRecord) bool {
+				if stop {
+					return false
+				}
+				lrSize := delimitedSize(sizer.LogRecordSize(srcLr))
+				if !limits.fits(1, delimitedSize(destRlSize+delimitedSize(destSlSize+lrSize))) {
+					stop = true
+					return false
+				}
+				limits.count++
+				destSlSize += lrSize
+				srcLr.MoveTo(destSl.LogRecords().AppendEmpty())
+				return true
+			})
+			if destSl.LogRecords().Len() == 0 {
+				return false
+			}
+			destRlSize += delimitedSize(destSlSize)
+			destSl.MoveTo(destRl.ScopeLogs().AppendEmpty())
+			return srcSl.LogRecords().Len() == 0
+		})
+		full = true
+		if destRl.ScopeLogs().Len() > 0 {
+			limits.bytes += delimitedSize(destRlSize)
+			destRl.MoveTo(dest.ResourceLogs().AppendEmpty())
+		}
+		if srcRl.ScopeLogs().Len() == 0 {
+			removedBytes += rlSize
+			return true
+		}
+		removedBytes += rlSize - delimitedSize(sizer.ResourceLogsSize(srcRl))
+		return false
+	})
+
+	return dest, limits.bytes, removedBytes
+}
+
 // resourceLRC calculates the total number of log records in the plog.ResourceLogs.
 func resourceLRC(rs plog.ResourceLogs) (count int) {
 	for k := 0; k < rs.ScopeLogs().Len(); k++ {
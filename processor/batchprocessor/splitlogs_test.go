@@ -4,9 +4,11 @@
 package batchprocessor
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -145,3 +147,62 @@ func TestSplitLogsMultipleILL(t *testing.T) {
 	assert.Equal(t, "test-log-int-0-0", split.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).SeverityText())
 	assert.Equal(t, "test-log-int-0-4", split.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(4).SeverityText())
 }
+
+func TestSplitLogsBytes(t *testing.T) {
+	sizer := &plog.ProtoMarshaler{}
+	for _, parts := range []int{1, 3, 7, 20} {
+		ld := testdata.GenerateLogs(20)
+		// Add a second resource with several scopes, so the logs are split at every level.
+		rl := ld.ResourceLogs().AppendEmpty()
+		testdata.GenerateLogs(1).ResourceLogs().At(0).Resource().CopyTo(rl.Resource())
+		for i := 0; i < 3; i++ {
+			testdata.GenerateLogs(5).ResourceLogs().At(0).ScopeLogs().At(0).CopyTo(rl.ScopeLogs().AppendEmpty())
+		}
+		maxBytes := sizer.LogsSize(ld) / parts
+
+		total := 0
+		for ld.LogRecordCount() > 0 {
+			srcBytes := sizer.LogsSize(ld)
+			split, splitBytes, removedBytes := splitLogsBytes(0, maxBytes, ld, sizer)
+			require.Positive(t, split.LogRecordCount())
+			assert.LessOrEqual(t, splitBytes, maxBytes)
+			// The sizes are computed incrementally, and must match the sizes of the whole data.
+			assert.Equal(t, sizer.LogsSize(split), splitBytes)
+			assert.Equal(t, srcBytes-sizer.LogsSize(ld), removedBytes)
+			total += split.LogRecordCount()
+		}
+		assert.Equal(t, 35, total)
+	}
+}
+
+func TestSplitLogsBytes_MaxSize(t *testing.T) {
+	sizer := &plog.ProtoMarshaler{}
+	ld := testdata.GenerateLogs(20)
+	split, _, _ := splitLogsBytes(5, 0, ld, sizer)
+	assert.Equal(t, 5, split.LogRecordCount())
+	assert.Equal(t, 15, ld.LogRecordCount())
+}
+
+func TestSplitLogsBytes_EmptyContainersLargerThanMax(t *testing.T) {
+	sizer := &plog.ProtoMarshaler{}
+	large := strings.Repeat("x", 1000)
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("large", large)
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("large", large)
+	rl.ScopeLogs().AppendEmpty().Scope().SetName(large)
+	testdata.GenerateLogs(5).ResourceLogs().At(0).ScopeLogs().At(0).MoveTo(rl.ScopeLogs().AppendEmpty())
+
+	// Every split makes progress, the empty resources and scopes which don't fit are dropped.
+	total := 0
+	for i := 0; ld.LogRecordCount() > 0; i++ {
+		require.Less(t, i, 5)
+		srcBytes := sizer.LogsSize(ld)
+		split, splitBytes, removedBytes := splitLogsBytes(0, 200, ld, sizer)
+		require.Positive(t, split.LogRecordCount())
+		assert.Equal(t, sizer.LogsSize(split), splitBytes)
+		assert.Equal(t, srcBytes-sizer.LogsSize(ld), removedBytes)
+		total += split.LogRecordCount()
+	}
+	assert.Equal(t, 5, total)
+}
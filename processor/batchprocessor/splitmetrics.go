@@ -75,6 +75,196 @@ func splitMetrics(size int, src pmetric.Metrics) pmetric.Metrics {
 	return dest
 }
 
+// splitMetricsBytes removes data points from the input metrics and returns new metrics of at most maxBytes bytes and
+// at most maxSize data points, zero meaning no limit, along with their size in bytes and the number of bytes removed
+// from src. The returned metrics have at least one data point even if it doesn't fit in maxBytes. The size is computed
+// incrementally from the sizes of the resources, scopes, metrics and data points as they are moved, so every data
+// point is sized at most once.
+func splitMetricsBytes(maxSize, maxBytes int, src pmetric.Metrics, sizer *pmetric.ProtoMarshaler) (pmetric.Metrics, int, int) {
+	dest := pmetric.NewMetrics()
+	limits := splitLimits{maxSize: maxSize, maxBytes: maxBytes}
+	removedBytes := 0
+	full := false
+
+	src.ResourceMetrics().RemoveIf(func(srcRs pmetric.ResourceMetrics) bool {
+		// If we are done skip everything else.
+		if full {
+			return false
+		}
+
+		// If it fully fits
+		rsSize := delimitedSize(sizer.ResourceMetricsSize(srcRs))
+		rsDPC := resourceMetricsDPC(srcRs)
+		if limits.fits(rsDPC, rsSize) {
+			limits.add(rsDPC, rsSize)
+			removedBytes += rsSize
+			srcRs.MoveTo(dest.ResourceMetrics().AppendEmpty())
+			return true
+		}
+		// Drop the empty resources which don't fit, so every split makes progress.
+		if rsDPC == 0 {
+			removedBytes += rsSize
+			return true
+		}
+
+		destRs := pmetric.NewResourceMetrics()
+		srcRs.Resource().CopyTo(destRs.Resource())
+		destRs.SetSchemaUrl(srcRs.SchemaUrl())
+		destRsSize := sizer.ResourceMetricsSize(destRs)
+		srcRs.ScopeMetrics().RemoveIf(func(srcSm pmetric.ScopeMetrics) bool {
+			// If we are done skip everything else.
+			if full {
+				return false
+			}
+
+			// If possible to move all metrics do that.
+			smSize := delimitedSize(sizer.ScopeMetricsSize(srcSm))
+			smDPC := scopeMetricsDPC(srcSm)
+			if limits.fits(smDPC, delimitedSize(destRsSize+smSize)) {
+				limits.count += smDPC
+				destRsSize += smSize
+				srcSm.MoveTo(destRs.ScopeMetrics().AppendEmpty())
+				return true
+			}
+			if smDPC == 0 {
+				return true
+			}
+
+			destSm := pmetric.NewScopeMetrics()
+			srcSm.Scope().CopyTo(destSm.Scope())
+			destSm.SetSchemaUrl(srcSm.SchemaUrl())
+			destSmSize := sizer.ScopeMetricsSize(destSm)
+			srcSm.Metrics().RemoveIf(func(srcMetric pmetric.Metric) bool {
+				// If we are done skip everything else.
+				if full {
+					return false
+				}
+
+				// If possible to move all points do that.
+				metricSize := delimitedSize(sizer.MetricSize(srcMetric))
+				mDPC := metricDPC(srcMetric)
+				if limits.fits(mDPC, delimitedSize(destRsSize+delimitedSize(destSmSize+metricSize))) {
+					limits.count += mDPC
+					destSmSize += metricSize
+					srcMetric.MoveTo(destSm.Metrics().AppendEmpty())
+					return true
+				}
+				if mDPC == 0 {
+					return true
+				}
+
+				full = true
+				destMetric := pmetric.NewMetric()
+				moved, destMetricSize := splitMetricBytes(srcMetric, destMetric, sizer, func(metricSize int) bool {
+					if !limits.fits(1, delimitedSize(destRsSize+delimitedSize(destSmSize+delimitedSize(metricSize)))) {
+						return false
+					}
+					limits.count++
+					return true
+				})
+				if moved == 0 {
+					return false
+				}
+				destSmSize += delimitedSize(destMetricSize)
+				destMetric.MoveTo(destSm.Metrics().AppendEmpty())
+				return mDPC == moved
+			})
+			full = true
+			if destSm.Metrics().Len() == 0 {
+				return srcSm.Metrics().Len() == 0
+			}
+			destRsSize += delimitedSize(destSmSize)
+			destSm.MoveTo(destRs.ScopeMetrics().AppendEmpty())
+			return srcSm.Metrics().Len() == 0
+		})
+		full = true
+		if destRs.ScopeMetrics().Len() > 0 {
+			limits.bytes += delimitedSize(destRsSize)
+			destRs.MoveTo(dest.ResourceMetrics().AppendEmpty())
+		}
+		if srcRs.ScopeMetrics().Len() == 0 {
+			removedBytes += rsSize
+			return true
+		}
+		removedBytes += rsSize - delimitedSize(sizer.ResourceMetricsSize(srcRs))
+		return false
+	})
+
+	return dest, limits.bytes, removedBytes
+}
+
+// splitMetricBytes moves the data points of ms to dest, as long as fits returns true for the size in bytes
+// of dest with the next data point, fits accounting for the data point if it does.
+// Returns the number of moved data points and the size of dest in bytes.
+func splitMetricBytes(ms, dest pmetric.Metric, sizer *pmetric.ProtoMarshaler, fits func(metricSize int) bool) (int, int) {
+	dest.SetName(ms.Name())
+	dest.SetDescription(ms.Description())
+	dest.SetUnit(ms.Unit())
+	headerSize := sizer.MetricSize(dest)
+	initMetricData(ms, dest)
+	// The data without data points is shorter than 128 bytes, so its field tag and length take two bytes.
+	dataSize := sizer.MetricSize(dest) - headerSize - 2
+
+	moved := 0
+	stop := false
+	// moveIf returns whether the data point of the given size fits, and accounts for it if it does.
+	moveIf := func(dpSize int) bool {
+		if stop {
+			return false
+		}
+		dpSize = delimitedSize(dpSize)
+		if !fits(headerSize + delimitedSize(dataSize+dpSize)) {
+			stop = true
+			return false
+		}
+		moved++
+		dataSize += dpSize
+		return true
+	}
+
+	switch ms.Type() {
+	case pmetric.MetricTypeGauge:
+		moveNumberDataPoints(ms.Gauge().DataPoints(), dest.Gauge().DataPoints(), sizer, moveIf)
+	case pmetric.MetricTypeSum:
+		moveNumberDataPoints(ms.Sum().DataPoints(), dest.Sum().DataPoints(), sizer, moveIf)
+	case pmetric.MetricTypeHistogram:
+		ms.Histogram().DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+			if !moveIf(sizer.HistogramDataPointSize(dp)) {
+				return false
+			}
+			dp.MoveTo(dest.Histogram().DataPoints().AppendEmpty())
+			return true
+		})
+	case pmetric.MetricTypeExponentialHistogram:
+		ms.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+			if !moveIf(sizer.ExponentialHistogramDataPointSize(dp)) {
+				return false
+			}
+			dp.MoveTo(dest.ExponentialHistogram().DataPoints().AppendEmpty())
+			return true
+		})
+	case pmetric.MetricTypeSummary:
+		ms.Summary().DataPoints().RemoveIf(func(dp pmetric.SummaryDataPoint) bool {
+			if !moveIf(sizer.SummaryDataPointSize(dp)) {
+				return false
+			}
+			dp.MoveTo(dest.Summary().DataPoints().AppendEmpty())
+			return true
+		})
+	}
+	return moved, headerSize + delimitedSize(dataSize)
+}
+
+func moveNumberDataPoints(src, dst pmetric.NumberDataPointSlice, sizer *pmetric.ProtoMarshaler, moveIf func(int) bool) {
+	src.RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+		if !moveIf(sizer.NumberDataPointSize(dp)) {
+			return false
+		}
+		dp.MoveTo(dst.AppendEmpty())
+		return true
+	})
+}
+
 // resourceMetricsDPC calculates the total number of data points in the pmetric.ResourceMetrics.
 func resourceMetricsDPC(rs pmetric.ResourceMetrics) int {
 	dataPointCount := 0
@@ -118,27 +308,39 @@ func splitMetric(ms, dest pmetric.Metric, size int) (int, bool) {
 	dest.SetName(ms.Name())
 	dest.SetDescription(ms.Description())
 	dest.SetUnit(ms.Unit())
+	initMetricData(ms, dest)
+
+	switch ms.Type() {
+	case pmetric.MetricTypeGauge:
+		return splitNumberDataPoints(ms.Gauge().DataPoints(), dest.Gauge().DataPoints(), size)
+	case pmetric.MetricTypeSum:
+		return splitNumberDataPoints(ms.Sum().DataPoints(), dest.Sum().DataPoints(), size)
+	case pmetric.MetricTypeHistogram:
+		return splitHistogramDataPoints(ms.Histogram().DataPoints(), dest.Histogram().DataPoints(), size)
+	case pmetric.MetricTypeExponentialHistogram:
+		return splitExponentialHistogramDataPoints(ms.ExponentialHistogram().DataPoints(), dest.ExponentialHistogram().DataPoints(), size)
+	case pmetric.MetricTypeSummary:
+		return splitSummaryDataPoints(ms.Summary().DataPoints(), dest.Summary().DataPoints(), size)
+	}
+	return size, false
+}
 
+// initMetricData sets the empty data of the type of ms in dest, along with the fields applying to all the data points.
+func initMetricData(ms, dest pmetric.Metric) {
 	switch ms.Type() {
 	case pmetric.MetricTypeGauge:
-		return splitNumberDataPoints(ms.Gauge().DataPoints(), dest.SetEmptyGauge().DataPoints(), size)
+		dest.SetEmptyGauge()
 	case pmetric.MetricTypeSum:
 		destSum := dest.SetEmptySum()
 		destSum.SetAggregationTemporality(ms.Sum().AggregationTemporality())
 		destSum.SetIsMonotonic(ms.Sum().IsMonotonic())
-		return splitNumberDataPoints(ms.Sum().DataPoints(), destSum.DataPoints(), size)
 	case pmetric.MetricTypeHistogram:
-		destHistogram := dest.SetEmptyHistogram()
-		destHistogram.SetAggregationTemporality(ms.Histogram().AggregationTemporality())
-		return splitHistogramDataPoints(ms.Histogram().DataPoints(), destHistogram.DataPoints(), size)
+		dest.SetEmptyHistogram().SetAggregationTemporality(ms.Histogram().AggregationTemporality())
 	case pmetric.MetricTypeExponentialHistogram:
-		destHistogram := dest.SetEmptyExponentialHistogram()
-		destHistogram.SetAggregationTemporality(ms.ExponentialHistogram().AggregationTemporality())
-		return splitExponentialHistogramDataPoints(ms.ExponentialHistogram().DataPoints(), destHistogram.DataPoints(), size)
+		dest.SetEmptyExponentialHistogram().SetAggregationTemporality(ms.ExponentialHistogram().AggregationTemporality())
 	case pmetric.MetricTypeSummary:
-		return splitSummaryDataPoints(ms.Summary().DataPoints(), dest.SetEmptySummary().DataPoints(), size)
+		dest.SetEmptySummary()
 	}
-	return size, false
 }
 
 func splitNumberDataPoints(src, dst pmetric.NumberDataPointSlice, size int) (int, bool) {
@@ -4,9 +4,11 @@
 package batchprocessor
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -314,3 +316,69 @@ func TestSplitMetricsMultipleILM(t *testing.T) {
 	assert.Equal(t, "test-metric-int-0-0", split.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
 	assert.Equal(t, "test-metric-int-0-4", split.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(4).Name())
 }
+
+func TestSplitMetricsBytes(t *testing.T) {
+	sizer := &pmetric.ProtoMarshaler{}
+	for _, parts := range []int{1, 3, 7, 40} {
+		md := testdata.GenerateMetrics(20)
+		// Add a second resource with several scopes, so the metrics are split at every level.
+		rm := md.ResourceMetrics().AppendEmpty()
+		testdata.GenerateMetrics(1).ResourceMetrics().At(0).Resource().CopyTo(rm.Resource())
+		for i := 0; i < 3; i++ {
+			testdata.GenerateMetrics(5).ResourceMetrics().At(0).ScopeMetrics().At(0).CopyTo(rm.ScopeMetrics().AppendEmpty())
+		}
+		total := md.DataPointCount()
+		maxBytes := sizer.MetricsSize(md) / parts
+
+		splitTotal := 0
+		for md.DataPointCount() > 0 {
+			srcBytes := sizer.MetricsSize(md)
+			split, splitBytes, removedBytes := splitMetricsBytes(0, maxBytes, md, sizer)
+			require.Positive(t, split.DataPointCount())
+			if split.DataPointCount() > 1 {
+				assert.LessOrEqual(t, splitBytes, maxBytes)
+			}
+			// The sizes are computed incrementally, and must match the sizes of the whole data.
+			assert.Equal(t, sizer.MetricsSize(split), splitBytes)
+			assert.Equal(t, srcBytes-sizer.MetricsSize(md), removedBytes)
+			splitTotal += split.DataPointCount()
+		}
+		assert.Equal(t, total, splitTotal)
+	}
+}
+
+func TestSplitMetricsBytes_MaxSize(t *testing.T) {
+	sizer := &pmetric.ProtoMarshaler{}
+	md := testdata.GenerateMetrics(20)
+	split, _, _ := splitMetricsBytes(5, 0, md, sizer)
+	assert.Equal(t, 5, split.DataPointCount())
+	assert.Equal(t, 35, md.DataPointCount())
+}
+
+func TestSplitMetricsBytes_EmptyContainersLargerThanMax(t *testing.T) {
+	sizer := &pmetric.ProtoMarshaler{}
+	large := strings.Repeat("x", 1000)
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr("large", large)
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("large", large)
+	rm.ScopeMetrics().AppendEmpty().Scope().SetName(large)
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(large)
+	sm.Metrics().AppendEmpty().SetName(large)
+	testdata.GenerateMetrics(5).ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().MoveAndAppendTo(sm.Metrics())
+	total := md.DataPointCount()
+
+	// Every split makes progress, the empty resources, scopes and metrics which don't fit are dropped.
+	splitTotal := 0
+	for i := 0; md.DataPointCount() > 0; i++ {
+		require.Less(t, i, total)
+		srcBytes := sizer.MetricsSize(md)
+		split, splitBytes, removedBytes := splitMetricsBytes(0, 200, md, sizer)
+		require.Positive(t, split.DataPointCount())
+		assert.Equal(t, sizer.MetricsSize(split), splitBytes)
+		assert.Equal(t, srcBytes-sizer.MetricsSize(md), removedBytes)
+		splitTotal += split.DataPointCount()
+	}
+	assert.Equal(t, total, splitTotal)
+}
@@ -64,6 +64,103 @@ func splitTraces(size int, src ptrace.Traces) ptrace.Traces {
 	return dest
 }
 
+// splitTracesBytes removes spans from the input trace and returns a new trace of at most maxBytes bytes and
+// at most maxSize spans, zero meaning no limit, along with its size in bytes and the number of bytes removed from src.
+// The returned trace has at least one span even if it doesn't fit in maxBytes. The size is computed incrementally
+// from the sizes of the resources, scopes and spans as they are moved, so every span is sized at most once.
+func splitTracesBytes(maxSize, maxBytes int, src ptrace.Traces, sizer *ptrace.ProtoMarshaler) (ptrace.Traces, int, int) {
+	dest := ptrace.NewTraces()
+	limits := splitLimits{maxSize: maxSize, maxBytes: maxBytes}
+	removedBytes := 0
+	full := false
+
+	src.ResourceSpans().RemoveIf(func(srcRs ptrace.ResourceSpans) bool {
+		// If we are done skip everything else.
+		if full {
+			return false
+		}
+
+		// If it fully fits
+		rsSize := delimitedSize(sizer.ResourceSpansSize(srcRs))
+		rsSC := resourceSC(srcRs)
+		if limits.fits(rsSC, rsSize) {
+			limits.add(rsSC, rsSize)
+			removedBytes += rsSize
+			srcRs.MoveTo(dest.ResourceSpans().AppendEmpty())
+			return true
+		}
+		// Drop the empty resources which don't fit, so every split makes progress.
+		if rsSC == 0 {
+			removedBytes += rsSize
+			return true
+		}
+
+		destRs := ptrace.NewResourceSpans()
+		srcRs.Resource().CopyTo(destRs.Resource())
+		destRs.SetSchemaUrl(srcRs.SchemaUrl())
+		destRsSize := sizer.ResourceSpansSize(destRs)
+		srcRs.ScopeSpans().RemoveIf(func(srcSs ptrace.ScopeSpans) bool {
+			// If we are done skip everything else.
+			if full {
+				return false
+			}
+
+			// If possible to move all spans do that.
+			ssSize := delimitedSize(sizer.ScopeSpansSize(srcSs))
+			ssSC := srcSs.Spans().Len()
+			if limits.fits(ssSC, delimitedSize(destRsSize+ssSize)) {
+				limits.count += ssSC
+				destRsSize += ssSize
+				srcSs.MoveTo(destRs.ScopeSpans().AppendEmpty())
+				return true
+			}
+			if ssSC == 0 {
+				return true
+			}
+
+			full = true
+			destSs := ptrace.NewScopeSpans()
+			srcSs.Scope().CopyTo(destSs.Scope())
+			destSs.SetSchemaUrl(srcSs.SchemaUrl())
+			destSsSize := sizer.ScopeSpansSize(destSs)
+			stop := false
+			srcSs.Spans().RemoveIf(func(srcSpan ptrace.Span) bool {
+				if stop {
+					return false
+				}
+				spanSize := delimitedSize(sizer.SpanSize(srcSpan))
+				if !limits.fits(1, delimitedSize(destRsSize+delimitedSize(destSsSize+spanSize))) {
+					stop = true
+					return false
+				}
+				limits.count++
+				destSsSize += spanSize
+				srcSpan.MoveTo(destSs.Spans().AppendEmpty())
+				return true
+			})
+			if destSs.Spans().Len() == 0 {
+				return false
+			}
+			destRsSize += delimitedSize(destSsSize)
+			destSs.MoveTo(destRs.ScopeSpans().AppendEmpty())
+			return srcSs.Spans().Len() == 0
+		})
+		full = true
+		if destRs.ScopeSpans().Len() > 0 {
+			limits.bytes += delimitedSize(destRsSize)
+			destRs.MoveTo(dest.ResourceSpans().AppendEmpty())
+		}
+		if srcRs.ScopeSpans().Len() == 0 {
+			removedBytes += rsSize
+			return true
+		}
+		removedBytes += rsSize - delimitedSize(sizer.ResourceSpansSize(srcRs))
+		return false
+	})
+
+	return dest, limits.bytes, removedBytes
+}
+
 // resourceSC calculates the total number of spans in the ptrace.ResourceSpans.
 func resourceSC(rs ptrace.ResourceSpans) (count int) {
 	for k := 0; k < rs.ScopeSpans().Len(); k++ {
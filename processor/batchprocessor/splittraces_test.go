@@ -4,9 +4,11 @@
 package batchprocessor
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -145,3 +147,99 @@ func TestSplitTracesMultipleILS(t *testing.T) {
 	assert.Equal(t, "test-span-0-0", split.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
 	assert.Equal(t, "test-span-0-4", split.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(4).Name())
 }
+
+func TestSplitTracesBytes(t *testing.T) {
+	sizer := &ptrace.ProtoMarshaler{}
+	td := testdata.GenerateTraces(20)
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetName(getTestSpanName(0, i))
+	}
+	maxBytes := sizer.TracesSize(td) / 3
+
+	var names []string
+	for td.SpanCount() > 0 {
+		split, splitBytes, _ := splitTracesBytes(0, maxBytes, td, sizer)
+		assert.LessOrEqual(t, splitBytes, maxBytes)
+		splitSpans := split.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < splitSpans.Len(); i++ {
+			names = append(names, splitSpans.At(i).Name())
+		}
+	}
+	// All the spans are sent in the original order.
+	require.Len(t, names, 20)
+	for i, name := range names {
+		assert.Equal(t, getTestSpanName(0, i), name)
+	}
+}
+
+func TestSplitTracesBytes_Sizes(t *testing.T) {
+	sizer := &ptrace.ProtoMarshaler{}
+	for _, parts := range []int{1, 3, 7, 20} {
+		td := testdata.GenerateTraces(20)
+		// Add a second resource with several scopes, so the traces are split at every level.
+		rs := td.ResourceSpans().AppendEmpty()
+		testdata.GenerateTraces(1).ResourceSpans().At(0).Resource().CopyTo(rs.Resource())
+		for i := 0; i < 3; i++ {
+			testdata.GenerateTraces(5).ResourceSpans().At(0).ScopeSpans().At(0).CopyTo(rs.ScopeSpans().AppendEmpty())
+		}
+		maxBytes := sizer.TracesSize(td) / parts
+
+		total := 0
+		for td.SpanCount() > 0 {
+			srcBytes := sizer.TracesSize(td)
+			split, splitBytes, removedBytes := splitTracesBytes(0, maxBytes, td, sizer)
+			require.Positive(t, split.SpanCount())
+			assert.LessOrEqual(t, splitBytes, maxBytes)
+			// The sizes are computed incrementally, and must match the sizes of the whole data.
+			assert.Equal(t, sizer.TracesSize(split), splitBytes)
+			assert.Equal(t, srcBytes-sizer.TracesSize(td), removedBytes)
+			total += split.SpanCount()
+		}
+		assert.Equal(t, 35, total)
+	}
+}
+
+func TestSplitTracesBytes_MaxSize(t *testing.T) {
+	sizer := &ptrace.ProtoMarshaler{}
+	td := testdata.GenerateTraces(20)
+	split, _, _ := splitTracesBytes(5, 0, td, sizer)
+	assert.Equal(t, 5, split.SpanCount())
+	assert.Equal(t, 15, td.SpanCount())
+}
+
+func TestSplitTracesBytes_SpanLargerThanMax(t *testing.T) {
+	sizer := &ptrace.ProtoMarshaler{}
+	td := testdata.GenerateTraces(2)
+	split, _, _ := splitTracesBytes(0, 1, td, sizer)
+	assert.Equal(t, 1, split.SpanCount())
+	assert.Equal(t, 1, td.SpanCount())
+
+	split, _, _ = splitTracesBytes(0, 1, td, sizer)
+	assert.Equal(t, 1, split.SpanCount())
+	assert.Equal(t, 0, td.SpanCount())
+}
+
+func TestSplitTracesBytes_EmptyContainersLargerThanMax(t *testing.T) {
+	sizer := &ptrace.ProtoMarshaler{}
+	large := strings.Repeat("x", 1000)
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("large", large)
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("large", large)
+	rs.ScopeSpans().AppendEmpty().Scope().SetName(large)
+	testdata.GenerateTraces(5).ResourceSpans().At(0).ScopeSpans().At(0).MoveTo(rs.ScopeSpans().AppendEmpty())
+
+	// Every split makes progress, the empty resources and scopes which don't fit are dropped.
+	total := 0
+	for i := 0; td.SpanCount() > 0; i++ {
+		require.Less(t, i, 5)
+		srcBytes := sizer.TracesSize(td)
+		split, splitBytes, removedBytes := splitTracesBytes(0, 200, td, sizer)
+		require.Positive(t, split.SpanCount())
+		assert.Equal(t, sizer.TracesSize(split), splitBytes)
+		assert.Equal(t, srcBytes-sizer.TracesSize(td), removedBytes)
+		total += split.SpanCount()
+	}
+	assert.Equal(t, 5, total)
+}
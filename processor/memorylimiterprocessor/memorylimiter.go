@@ -5,19 +5,34 @@ package memorylimiterprocessor // import "go.opentelemetry.io/collector/processo
 
 import (
 	"context"
+	"math/rand"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/internal/memorylimiter"
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/memorylimiterprocessor/internal/metadata"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 )
 
+// maxShedPriority is the priority of the data refused last by the gradual load shedding.
+const maxShedPriority = 100
+
 type memoryLimiterProcessor struct {
 	memlimiter *memorylimiter.MemoryLimiter
 	obsrep     *processorhelper.ObsReport
+
+	// shedPriorityAttribute is the resource attribute holding the priority
+	// of the data for the gradual load shedding.
+	shedPriorityAttribute string
 }
 
 // newMemoryLimiter returns a new memorylimiter processor.
@@ -35,8 +50,25 @@ func newMemoryLimiterProcessor(set processor.CreateSettings, cfg *Config) (*memo
 	}
 
 	p := &memoryLimiterProcessor{
-		memlimiter: ml,
-		obsrep:     obsrep,
+		memlimiter:            ml,
+		obsrep:                obsrep,
+		shedPriorityAttribute: cfg.ShedPriorityAttribute,
+	}
+
+	if ml.SheddingEnabled() {
+		processorAttr := metric.WithAttributes(attribute.String(obsmetrics.ProcessorKey, set.ID.String()))
+		_, err = metadata.Meter(set.TelemetrySettings).Float64ObservableGauge(
+			processorhelper.BuildCustomMetricName(metadata.Type.String(), "shed_ratio"),
+			metric.WithDescription("Fraction of the data refused by the gradual load shedding"),
+			metric.WithUnit("1"),
+			metric.WithFloat64Callback(func(_ context.Context, obs metric.Float64Observer) error {
+				obs.Observe(ml.ShedRatio(), processorAttr)
+				return nil
+			}),
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return p, nil
@@ -50,9 +82,65 @@ func (p *memoryLimiterProcessor) shutdown(ctx context.Context) error {
 	return p.memlimiter.Shutdown(ctx)
 }
 
+// mustRefuse returns if the data must be refused. When the gradual load shedding is enabled,
+// the data below the hard limit is refused if its score, either its priority or a random value,
+// is below the fraction of the data to refuse.
+func (p *memoryLimiterProcessor) mustRefuse(n int, resource func(i int) pcommon.Resource) bool {
+	if p.memlimiter.MustRefuse() {
+		return true
+	}
+	if !p.memlimiter.SheddingEnabled() {
+		return false
+	}
+	shedRatio := p.memlimiter.ShedRatio()
+	if shedRatio <= 0 {
+		return false
+	}
+	if shedRatio >= 1 {
+		return true
+	}
+	if priority, ok := p.shedPriority(n, resource); ok {
+		return float64(priority)/maxShedPriority < shedRatio
+	}
+	return rand.Float64() < shedRatio
+}
+
+// shedPriority returns the highest priority of the resources of the data, so the data
+// containing high priority resources is refused last.
+func (p *memoryLimiterProcessor) shedPriority(n int, resource func(i int) pcommon.Resource) (int64, bool) {
+	if p.shedPriorityAttribute == "" {
+		return 0, false
+	}
+	priority, found := int64(0), false
+	for i := 0; i < n; i++ {
+		v, ok := resource(i).Attributes().Get(p.shedPriorityAttribute)
+		if !ok {
+			continue
+		}
+		var rp int64
+		switch v.Type() {
+		case pcommon.ValueTypeInt:
+			rp = v.Int()
+		case pcommon.ValueTypeStr:
+			parsed, err := strconv.ParseInt(v.Str(), 10, 64)
+			if err != nil {
+				continue
+			}
+			rp = parsed
+		default:
+			continue
+		}
+		if !found || rp > priority {
+			priority, found = rp, true
+		}
+	}
+	return min(max(priority, 0), maxShedPriority), found
+}
+
 func (p *memoryLimiterProcessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
 	numSpans := td.SpanCount()
-	if p.memlimiter.MustRefuse() {
+	rss := td.ResourceSpans()
+	if p.mustRefuse(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() }) {
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -70,7 +158,8 @@ func (p *memoryLimiterProcessor) processTraces(ctx context.Context, td ptrace.Tr
 
 func (p *memoryLimiterProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
 	numDataPoints := md.DataPointCount()
-	if p.memlimiter.MustRefuse() {
+	rms := md.ResourceMetrics()
+	if p.mustRefuse(rms.Len(), func(i int) pcommon.Resource { return rms.At(i).Resource() }) {
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -88,7 +177,8 @@ func (p *memoryLimiterProcessor) processMetrics(ctx context.Context, md pmetric.
 
 func (p *memoryLimiterProcessor) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
 	numRecords := ld.LogRecordCount()
-	if p.memlimiter.MustRefuse() {
+	rls := ld.ResourceLogs()
+	if p.mustRefuse(rls.Len(), func(i int) pcommon.Resource { return rls.At(i).Resource() }) {
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -460,6 +460,68 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	})
 }
 
+func TestGradualLoadShedding(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() {
+		memorylimiter.ReadMemStatsFn = runtime.ReadMemStats
+	})
+	// Half way between the low watermark and the hard limit, above the soft limit.
+	memorylimiter.ReadMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = 75 * 1024 * 1024
+	}
+
+	cfg := &Config{
+		CheckInterval:         time.Second,
+		MemoryLimitMiB:        100,
+		MemorySpikeLimitMiB:   30,
+		ShedLowWatermarkMiB:   50,
+		ShedPriorityAttribute: "priority",
+	}
+	ml, err := newMemoryLimiterProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	tp, err := processorhelper.NewTracesProcessor(
+		context.Background(),
+		processortest.NewNopCreateSettings(),
+		cfg,
+		consumertest.NewNop(),
+		ml.processTraces,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(ml.start),
+		processorhelper.WithShutdown(ml.shutdown))
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(ctx, componenttest.NewNopHost()))
+	ml.memlimiter.CheckMemLimits()
+	assert.InDelta(t, 0.5, ml.memlimiter.ShedRatio(), 1e-9)
+
+	newTraces := func(priorities ...any) ptrace.Traces {
+		td := ptrace.NewTraces()
+		for _, priority := range priorities {
+			rs := td.ResourceSpans().AppendEmpty()
+			rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			require.NoError(t, rs.Resource().Attributes().FromRaw(map[string]any{"priority": priority}))
+		}
+		return td
+	}
+
+	// Below the hard limit, the data is refused by priority.
+	assert.Equal(t, memorylimiter.ErrDataRefused, tp.ConsumeTraces(ctx, newTraces(40)))
+	assert.NoError(t, tp.ConsumeTraces(ctx, newTraces(60)))
+	assert.NoError(t, tp.ConsumeTraces(ctx, newTraces("80")))
+	// The highest priority of the resources is used.
+	assert.NoError(t, tp.ConsumeTraces(ctx, newTraces(10, 70)))
+
+	// Without priority, the data is refused with the probability of the shed ratio.
+	refused := 0
+	for i := 0; i < 1000; i++ {
+		if err := tp.ConsumeTraces(ctx, newTraces()); err != nil {
+			refused++
+		}
+	}
+	assert.InDelta(t, 500, refused, 100)
+
+	assert.NoError(t, tp.Shutdown(ctx))
+}
+
 type host struct {
 	ballastSize uint64
 	component.Host
@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/admission"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metadata"
+)
+
+// admitter limits the total size of the requests processed concurrently by the receiver,
+// the requests are admitted when they're received and released when the pipeline returns.
+type admitter struct {
+	queue *admission.BoundedQueue
+
+	rejectedRequests metric.Int64Counter
+	receiverAttr     attribute.KeyValue
+}
+
+// newAdmitter returns the admitter for the configured limits, or nil if the requests are not limited.
+func newAdmitter(cfg AdmissionConfig, set *receiver.CreateSettings) (*admitter, error) {
+	if cfg.RequestLimitMiB == 0 {
+		return nil, nil
+	}
+
+	a := &admitter{
+		queue:        admission.NewBoundedQueue(int64(cfg.RequestLimitMiB)<<20, int(cfg.WaiterLimit)),
+		receiverAttr: attribute.String(obsmetrics.ReceiverKey, set.ID.String()),
+	}
+
+	meter := metadata.Meter(set.TelemetrySettings)
+	var err error
+	a.rejectedRequests, err = meter.Int64Counter(
+		admissionMetricName("admission_rejected_requests"),
+		metric.WithDescription("Number of requests rejected because the admission limits were reached"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, err = meter.Int64ObservableGauge(
+		admissionMetricName("admission_in_flight_bytes"),
+		metric.WithDescription("Total size of the requests being processed"),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, obs metric.Int64Observer) error {
+			obs.Observe(a.queue.InFlight(), metric.WithAttributes(a.receiverAttr))
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func admissionMetricName(name string) string {
+	return obsmetrics.ReceiverPrefix + metadata.Type.String() + obsmetrics.NameSep + name
+}
+
+// acquire admits a request of the given size, recording the rejection if it isn't admitted.
+func (a *admitter) acquire(ctx context.Context, size int64, transport string) error {
+	err := a.queue.Acquire(ctx, size)
+	if err != nil {
+		a.recordRejected(ctx, transport)
+	}
+	return err
+}
+
+// tryAcquire admits more of a request already admitted up to the given size without waiting,
+// recording the rejection if it isn't admitted. The request is too large if the total is over the limit.
+func (a *admitter) tryAcquire(ctx context.Context, admitted, size int64, transport string) error {
+	err := admission.ErrRequestTooLarge
+	if admitted+size <= a.queue.Limit() {
+		err = a.queue.TryAcquire(size)
+	}
+	if err != nil {
+		a.recordRejected(ctx, transport)
+	}
+	return err
+}
+
+func (a *admitter) recordRejected(ctx context.Context, transport string) {
+	a.rejectedRequests.Add(ctx, 1, metric.WithAttributes(a.receiverAttr, attribute.String(obsmetrics.TransportKey, transport)))
+}
+
+// httpHandler returns a handler admitting the requests before calling next and releasing
+// them once it returns. The requests of unknown length, e.g. chunked or compressed, are
+// admitted as their body is read, see admittedBody.
+func (a *admitter) httpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength < 0 {
+			body := &admittedBody{ReadCloser: r.Body, ctx: r.Context(), admitter: a}
+			defer func() { a.queue.Release(body.admitted) }()
+			// The first chunk waits like the requests of known length, so the
+			// requests over the limit are admitted in order.
+			size := min(admissionChunkSize, a.queue.Limit())
+			if err := a.acquire(r.Context(), size, "http"); err != nil {
+				errorHandler(w, r, err.Error(), admissionHTTPStatus(err))
+				return
+			}
+			body.admitted = size
+			r.Body = body
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		size := r.ContentLength
+		if err := a.acquire(r.Context(), size, "http"); err != nil {
+			errorHandler(w, r, err.Error(), admissionHTTPStatus(err))
+			return
+		}
+		defer a.queue.Release(size)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// admissionChunkSize is the size by which the body of the HTTP requests of unknown length is admitted.
+const admissionChunkSize = 64 << 10
+
+// admittedBody admits the body of an HTTP request of unknown length by chunks as it's read,
+// instead of reading it entirely in memory to know its size first. The chunks after the first
+// one are admitted without waiting, since the request already holds a part of the limit, and
+// the read fails if they can't be admitted.
+type admittedBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	admitter *admitter
+
+	// admitted is the admitted size, released once the request is processed, and read
+	// the size of the body read so far.
+	admitted int64
+	read     int64
+	err      error
+}
+
+func (b *admittedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.admitted {
+		size := max(b.read-b.admitted, admissionChunkSize)
+		if b.admitted+size > b.admitter.queue.Limit() {
+			size = b.read - b.admitted
+		}
+		if b.err = b.admitter.tryAcquire(b.ctx, b.admitted, size, "http"); b.err != nil {
+			return 0, b.err
+		}
+		b.admitted += size
+	}
+	return n, err
+}
+
+// unaryInterceptor returns a gRPC interceptor admitting the requests before calling the handler
+// and releasing them once it returns. The size of the requests is their message size recorded by
+// statsHandler once they're received: unlike the HTTP requests, the gRPC requests are read and
+// decoded before being admitted, they're only limited by the server max_recv_msg_size_mib until then.
+func (a *admitter) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		size, ok := ctx.Value(payloadSizeKey{}).(*int64)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if err := a.acquire(ctx, *size, "grpc"); err != nil {
+			return nil, admissionGRPCStatus(err).Err()
+		}
+		defer a.queue.Release(*size)
+		return handler(ctx, req)
+	}
+}
+
+// payloadSizeKey is the context key of the size of the received gRPC request message.
+type payloadSizeKey struct{}
+
+// statsHandler records the size of the received gRPC request messages reported by the server,
+// so the interceptor doesn't compute the size of the decoded requests.
+type statsHandler struct{}
+
+func (statsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, payloadSizeKey{}, new(int64))
+}
+
+func (statsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	in, ok := rs.(*stats.InPayload)
+	if !ok {
+		return
+	}
+	if size, ok := ctx.Value(payloadSizeKey{}).(*int64); ok {
+		*size = int64(in.Length)
+	}
+}
+
+func (statsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (statsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// admissionHTTPStatus returns the HTTP status of the rejected requests. The requests larger
+// than the limit are refused permanently, the others can be retried later.
+func admissionHTTPStatus(err error) int {
+	if errors.Is(err, admission.ErrRequestTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusServiceUnavailable
+}
+
+// admissionGRPCStatus returns the gRPC status of the rejected requests, RESOURCE_EXHAUSTED
+// without RetryInfo is not retryable by the OTLP clients, UNAVAILABLE is.
+func admissionGRPCStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, admission.ErrRequestTooLarge):
+		return status.New(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err)
+	default:
+		return status.New(codes.Unavailable, err.Error())
+	}
+}
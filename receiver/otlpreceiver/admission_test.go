@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// blockingConsumer blocks the traces until unblock is closed.
+type blockingConsumer struct {
+	consumertest.Consumer
+	consuming chan struct{}
+	unblock   chan struct{}
+}
+
+func (bc *blockingConsumer) ConsumeTraces(context.Context, ptrace.Traces) error {
+	bc.consuming <- struct{}{}
+	<-bc.unblock
+	return nil
+}
+
+func generateLargeTraces(size int) ptrace.Traces {
+	td := testdata.GenerateTraces(1)
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("large", strings.Repeat("x", size))
+	return td
+}
+
+func TestOTLPReceiverAdmission(t *testing.T) {
+	grpcAddr := testutil.GetAvailableLocalAddress(t)
+	httpAddr := testutil.GetAvailableLocalAddress(t)
+	bc := &blockingConsumer{
+		Consumer:  consumertest.NewNop(),
+		consuming: make(chan struct{}),
+		unblock:   make(chan struct{}),
+	}
+
+	reader := sdkmetric.NewManualReader()
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = grpcAddr
+	cfg.HTTP.Endpoint = httpAddr
+	cfg.Admission.RequestLimitMiB = 1
+	recv := newReceiver(t, settings, cfg, otlpReceiverID, bc)
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	// The first request takes most of the limit until the pipeline returns.
+	td := generateLargeTraces(600 << 10)
+	done := make(chan error)
+	go func() {
+		done <- exportTraces(cc, td)
+	}()
+	<-bc.consuming
+	assert.Greater(t, admissionMetricValue(t, reader, "admission_in_flight_bytes"), int64(600<<10))
+
+	// Without waiters, the requests over the limit are rejected with the retryable UNAVAILABLE.
+	err = exportTraces(cc, td)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+
+	// Same over HTTP, the size of the compressed requests is known once decompressed.
+	tracesProto, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	req := createHTTPRequest(t, "http://"+httpAddr+defaultTracesURLPath, "gzip", "application/x-protobuf", tracesProto)
+	assert.Equal(t, http.StatusServiceUnavailable, doAdmissionHTTPRequest(t, req))
+	assert.Equal(t, int64(2), admissionMetricValue(t, reader, "admission_rejected_requests"))
+
+	// The requests larger than the limit are refused permanently.
+	tooLargeProto, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(generateLargeTraces(2 << 20))
+	require.NoError(t, err)
+	req = createHTTPRequest(t, "http://"+httpAddr+defaultTracesURLPath, "", "application/x-protobuf", tooLargeProto)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, doAdmissionHTTPRequest(t, req))
+
+	// The limit is released once the pipeline returns.
+	close(bc.unblock)
+	require.NoError(t, <-done)
+	assert.Equal(t, int64(0), admissionMetricValue(t, reader, "admission_in_flight_bytes"))
+
+	// The compressed requests are admitted as they're decompressed, and refused permanently
+	// once they're larger than the limit.
+	req = createHTTPRequest(t, "http://"+httpAddr+defaultTracesURLPath, "gzip", "application/x-protobuf", tooLargeProto)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, doAdmissionHTTPRequest(t, req))
+	assert.Equal(t, int64(0), admissionMetricValue(t, reader, "admission_in_flight_bytes"))
+	go func() {
+		<-bc.consuming
+	}()
+	req = createHTTPRequest(t, "http://"+httpAddr+defaultTracesURLPath, "", "application/x-protobuf", tracesProto)
+	assert.Equal(t, http.StatusOK, doAdmissionHTTPRequest(t, req))
+}
+
+func TestOTLPReceiverAdmissionWaiters(t *testing.T) {
+	grpcAddr := testutil.GetAvailableLocalAddress(t)
+	bc := &blockingConsumer{
+		Consumer:  consumertest.NewNop(),
+		consuming: make(chan struct{}),
+		unblock:   make(chan struct{}),
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = grpcAddr
+	cfg.HTTP = nil
+	cfg.Admission.RequestLimitMiB = 1
+	cfg.Admission.WaiterLimit = 1
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, bc)
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	td := generateLargeTraces(600 << 10)
+	done := make(chan error, 2)
+	go func() {
+		done <- exportTraces(cc, td)
+	}()
+	<-bc.consuming
+
+	// The second request waits for the first one to be released.
+	go func() {
+		done <- exportTraces(cc, td)
+	}()
+	select {
+	case <-bc.consuming:
+		t.Fatal("request admitted over the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(bc.unblock)
+	<-bc.consuming
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+}
+
+func doAdmissionHTTPRequest(t *testing.T, req *http.Request) int {
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp.StatusCode
+}
+
+func admissionMetricValue(t *testing.T, reader *sdkmetric.ManualReader, name string) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != admissionMetricName(name) {
+				continue
+			}
+			// Sum the values of all the transports.
+			var value int64
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				for _, dp := range data.DataPoints {
+					value += dp.Value
+				}
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					value += dp.Value
+				}
+			}
+			return value
+		}
+	}
+	t.Fatalf("metric %q not found", name)
+	return 0
+}
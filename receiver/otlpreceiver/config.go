@@ -40,10 +40,32 @@ type Protocols struct {
 	HTTP *HTTPConfig              `mapstructure:"http"`
 }
 
+// AdmissionConfig limits the total size of the requests processed concurrently by the receiver.
+type AdmissionConfig struct {
+	// RequestLimitMiB is the maximum total size, in MiB, of the requests being processed
+	// concurrently, from when they're received until the pipeline returns. The size of the
+	// HTTP requests is their decompressed body size and the size of the gRPC requests is
+	// their decoded message size. If 0, the requests are not limited.
+	RequestLimitMiB uint64 `mapstructure:"request_limit_mib"`
+
+	// WaiterLimit is the maximum number of requests waiting for the in-flight requests to
+	// be released when the limit is reached, the requests over it are rejected. If 0, the
+	// requests are rejected as soon as the limit is reached.
+	WaiterLimit uint64 `mapstructure:"waiter_limit"`
+}
+
 // Config defines configuration for OTLP receiver.
 type Config struct {
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+
+	// MemoryLimiter is the ID of the memory limiter extension used to refuse the requests,
+	// before they're processed, when the memory usage is too high. If omitted, the requests
+	// are not limited by the receiver.
+	MemoryLimiter *component.ID `mapstructure:"memory_limiter"`
+
+	// Admission limits the total size of the requests processed concurrently.
+	Admission AdmissionConfig `mapstructure:"admission"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -54,6 +76,9 @@ func (cfg *Config) Validate() error {
 	if cfg.GRPC == nil && cfg.HTTP == nil {
 		return errors.New("must specify at least one protocol when using the OTLP receiver")
 	}
+	if cfg.Admission.RequestLimitMiB == 0 && cfg.Admission.WaiterLimit != 0 {
+		return errors.New("admission::waiter_limit requires admission::request_limit_mib to be set")
+	}
 	return nil
 }
 
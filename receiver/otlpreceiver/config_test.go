@@ -134,6 +134,10 @@ func TestUnmarshalConfig(t *testing.T) {
 					LogsURLPath:    "/log/ingest",
 				},
 			},
+			Admission: AdmissionConfig{
+				RequestLimitMiB: 64,
+				WaiterLimit:     100,
+			},
 		}, cfg)
 
 }
@@ -191,6 +195,12 @@ func TestUnmarshalConfigEmptyProtocols(t *testing.T) {
 	assert.EqualError(t, component.ValidateConfig(cfg), "must specify at least one protocol when using the OTLP receiver")
 }
 
+func TestValidateConfigWaiterLimitWithoutRequestLimit(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Admission.WaiterLimit = 10
+	assert.EqualError(t, component.ValidateConfig(cfg), "admission::waiter_limit requires admission::request_limit_mib to be set")
+}
+
 func TestUnmarshalConfigInvalidSignalPath(t *testing.T) {
 	tests := []struct {
 		name       string
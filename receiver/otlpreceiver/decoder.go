@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// resourcesFieldNumber is the number of the resource spans, metrics or logs field
+	// in the OTLP export requests, the only field they have.
+	resourcesFieldNumber = 1
+
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+
+	decoderBufferSize = 4096
+)
+
+var errResourceTooLarge = errors.New("proto: resource too large")
+
+// decodeProtoResources reads an OTLP export request in the protobuf format and calls
+// appendResource with each of its resources, encoded as a request with only that resource,
+// as soon as it's read. Only one resource is held in memory at a time, instead of the
+// whole request, and the unknown fields are skipped.
+func decodeProtoResources(r io.Reader, appendResource func(buf []byte) error) error {
+	br := bufio.NewReaderSize(r, decoderBufferSize)
+	var buf bytes.Buffer
+	for {
+		tag, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		fieldNumber, wireType := tag>>3, tag&0x7
+		if fieldNumber == 0 {
+			return errors.New("proto: illegal tag 0")
+		}
+		if fieldNumber == resourcesFieldNumber && wireType != protoWireBytes {
+			return fmt.Errorf("proto: wrong wireType = %d for field %d", wireType, fieldNumber)
+		}
+
+		switch wireType {
+		case protoWireVarint:
+			_, err = binary.ReadUvarint(br)
+		case protoWireFixed64:
+			err = discard(br, 8)
+		case protoWireFixed32:
+			err = discard(br, 4)
+		case protoWireBytes:
+			var length uint64
+			if length, err = binary.ReadUvarint(br); err != nil {
+				break
+			}
+			if length > math.MaxInt32 {
+				return errResourceTooLarge
+			}
+			if fieldNumber != resourcesFieldNumber {
+				err = discard(br, length)
+				break
+			}
+			// The buffer only grows with the data actually read, not the announced length.
+			buf.Reset()
+			buf.Write(binary.AppendUvarint(nil, tag))
+			buf.Write(binary.AppendUvarint(nil, length))
+			if _, err = io.CopyN(&buf, br, int64(length)); err != nil {
+				break
+			}
+			err = appendResource(buf.Bytes())
+		default:
+			return fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func discard(br *bufio.Reader, n uint64) error {
+	_, err := io.CopyN(io.Discard, br, int64(n))
+	return err
+}
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// generateTracesResources returns traces with the given number of resources of spanCount spans.
+func generateTracesResources(resourceCount int, spanCount int) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for i := 0; i < resourceCount; i++ {
+		resource := testdata.GenerateTraces(spanCount)
+		resource.ResourceSpans().At(0).Resource().Attributes().PutInt("index", int64(i))
+		resource.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	return td
+}
+
+func TestDecodeTracesRequest(t *testing.T) {
+	req := ptraceotlp.NewExportRequestFromTraces(generateTracesResources(3, 2))
+	protoBytes, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBytes, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	got, err := pbEncoder.decodeTracesRequest(bytes.NewReader(protoBytes))
+	require.NoError(t, err)
+	assert.Equal(t, req.Traces(), got.Traces())
+
+	got, err = jsEncoder.decodeTracesRequest(bytes.NewReader(jsonBytes))
+	require.NoError(t, err)
+	assert.Equal(t, req.Traces(), got.Traces())
+
+	// The snake case field names are accepted like by pdata.
+	got, err = jsEncoder.decodeTracesRequest(strings.NewReader(strings.Replace(string(jsonBytes), "resourceSpans", "resource_spans", 1)))
+	require.NoError(t, err)
+	assert.Equal(t, req.Traces(), got.Traces())
+}
+
+func TestDecodeMetricsRequest(t *testing.T) {
+	md := testdata.GenerateMetrics(2)
+	md.ResourceMetrics().At(0).CopyTo(md.ResourceMetrics().AppendEmpty())
+	req := pmetricotlp.NewExportRequestFromMetrics(md)
+	protoBytes, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBytes, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	got, err := pbEncoder.decodeMetricsRequest(bytes.NewReader(protoBytes))
+	require.NoError(t, err)
+	assert.Equal(t, req.Metrics(), got.Metrics())
+
+	got, err = jsEncoder.decodeMetricsRequest(bytes.NewReader(jsonBytes))
+	require.NoError(t, err)
+	assert.Equal(t, req.Metrics(), got.Metrics())
+}
+
+func TestDecodeLogsRequest(t *testing.T) {
+	ld := testdata.GenerateLogs(2)
+	ld.ResourceLogs().At(0).CopyTo(ld.ResourceLogs().AppendEmpty())
+	req := plogotlp.NewExportRequestFromLogs(ld)
+	protoBytes, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBytes, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	got, err := pbEncoder.decodeLogsRequest(bytes.NewReader(protoBytes))
+	require.NoError(t, err)
+	assert.Equal(t, req.Logs(), got.Logs())
+
+	got, err = jsEncoder.decodeLogsRequest(bytes.NewReader(jsonBytes))
+	require.NoError(t, err)
+	assert.Equal(t, req.Logs(), got.Logs())
+}
+
+func TestDecodeUnknownFields(t *testing.T) {
+	req := ptraceotlp.NewExportRequestFromTraces(generateTracesResources(2, 1))
+	protoBytes, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBytes, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	// Unknown fields of every wire type, before and after the resources.
+	var unknown []byte
+	unknown = binary.AppendUvarint(unknown, 2<<3|protoWireVarint)
+	unknown = binary.AppendUvarint(unknown, 300)
+	unknown = binary.AppendUvarint(unknown, 3<<3|protoWireFixed64)
+	unknown = append(unknown, make([]byte, 8)...)
+	unknown = binary.AppendUvarint(unknown, 4<<3|protoWireFixed32)
+	unknown = append(unknown, make([]byte, 4)...)
+	unknown = binary.AppendUvarint(unknown, 5<<3|protoWireBytes)
+	unknown = binary.AppendUvarint(unknown, 3)
+	unknown = append(unknown, "abc"...)
+	got, err := pbEncoder.decodeTracesRequest(io.MultiReader(bytes.NewReader(unknown), bytes.NewReader(protoBytes), bytes.NewReader(unknown)))
+	require.NoError(t, err)
+	assert.Equal(t, req.Traces(), got.Traces())
+
+	unknownJSON := `{"unknown":{"resourceSpans":[{}]},` + string(jsonBytes[1:len(jsonBytes)-1]) + `,"other":[1,"2"]}`
+	got, err = jsEncoder.decodeTracesRequest(strings.NewReader(unknownJSON))
+	require.NoError(t, err)
+	assert.Equal(t, req.Traces(), got.Traces())
+}
+
+func TestDecodeInvalidRequest(t *testing.T) {
+	req := ptraceotlp.NewExportRequestFromTraces(generateTracesResources(2, 1))
+	protoBytes, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBytes, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	_, err = pbEncoder.decodeTracesRequest(bytes.NewReader(protoBytes[:len(protoBytes)-1]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, err = jsEncoder.decodeTracesRequest(bytes.NewReader(jsonBytes[:len(jsonBytes)-1]))
+	assert.Error(t, err)
+
+	// The resources field with another wire type.
+	_, err = pbEncoder.decodeTracesRequest(bytes.NewReader([]byte{resourcesFieldNumber<<3 | protoWireVarint, 1}))
+	assert.EqualError(t, err, "proto: wrong wireType = 0 for field 1")
+
+	// The announced length of a resource is not allocated before its data is read.
+	announced := binary.AppendUvarint([]byte{resourcesFieldNumber<<3 | protoWireBytes}, 1<<30)
+	_, err = pbEncoder.decodeTracesRequest(bytes.NewReader(announced))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+// BenchmarkDecodeTraces compares reading the whole body before unmarshalling it with
+// decoding it as it's read. The bytes allocated per operation show the memory saved by
+// not holding the whole body and its copies while growing the buffer.
+func BenchmarkDecodeTraces(b *testing.B) {
+	req := ptraceotlp.NewExportRequestFromTraces(generateTracesResources(100, 100))
+	protoBytes, err := req.MarshalProto()
+	require.NoError(b, err)
+	jsonBytes, err := req.MarshalJSON()
+	require.NoError(b, err)
+
+	for _, tt := range []struct {
+		name      string
+		body      []byte
+		unmarshal func(ptraceotlp.ExportRequest, []byte) error
+		enc       encoder
+	}{
+		{name: "proto", body: protoBytes, unmarshal: ptraceotlp.ExportRequest.UnmarshalProto, enc: pbEncoder},
+		{name: "json", body: jsonBytes, unmarshal: ptraceotlp.ExportRequest.UnmarshalJSON, enc: jsEncoder},
+	} {
+		b.Run(fmt.Sprintf("%s/buffered", tt.name), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				body, err := io.ReadAll(bytes.NewReader(tt.body))
+				require.NoError(b, err)
+				require.NoError(b, tt.unmarshal(ptraceotlp.NewExportRequest(), body))
+			}
+		})
+		b.Run(fmt.Sprintf("%s/streaming", tt.name), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := tt.enc.decodeTracesRequest(bytes.NewReader(tt.body))
+				require.NoError(b, err)
+			}
+		})
+	}
+}
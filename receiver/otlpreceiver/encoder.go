@@ -5,13 +5,17 @@ package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlprecei
 
 import (
 	"bytes"
+	"io"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 )
 
@@ -24,12 +28,16 @@ var (
 	pbEncoder       = &protoEncoder{}
 	jsEncoder       = &jsonEncoder{}
 	jsonPbMarshaler = &jsonpb.Marshaler{}
+
+	jsonTracesUnmarshaler  = &ptrace.JSONUnmarshaler{}
+	jsonMetricsUnmarshaler = &pmetric.JSONUnmarshaler{}
+	jsonLogsUnmarshaler    = &plog.JSONUnmarshaler{}
 )
 
 type encoder interface {
-	unmarshalTracesRequest(buf []byte) (ptraceotlp.ExportRequest, error)
-	unmarshalMetricsRequest(buf []byte) (pmetricotlp.ExportRequest, error)
-	unmarshalLogsRequest(buf []byte) (plogotlp.ExportRequest, error)
+	decodeTracesRequest(r io.Reader) (ptraceotlp.ExportRequest, error)
+	decodeMetricsRequest(r io.Reader) (pmetricotlp.ExportRequest, error)
+	decodeLogsRequest(r io.Reader) (plogotlp.ExportRequest, error)
 
 	marshalTracesResponse(ptraceotlp.ExportResponse) ([]byte, error)
 	marshalMetricsResponse(pmetricotlp.ExportResponse) ([]byte, error)
@@ -42,21 +50,42 @@ type encoder interface {
 
 type protoEncoder struct{}
 
-func (protoEncoder) unmarshalTracesRequest(buf []byte) (ptraceotlp.ExportRequest, error) {
+func (protoEncoder) decodeTracesRequest(r io.Reader) (ptraceotlp.ExportRequest, error) {
 	req := ptraceotlp.NewExportRequest()
-	err := req.UnmarshalProto(buf)
+	err := decodeProtoResources(r, func(buf []byte) error {
+		part := ptraceotlp.NewExportRequest()
+		if err := part.UnmarshalProto(buf); err != nil {
+			return err
+		}
+		part.Traces().ResourceSpans().MoveAndAppendTo(req.Traces().ResourceSpans())
+		return nil
+	})
 	return req, err
 }
 
-func (protoEncoder) unmarshalMetricsRequest(buf []byte) (pmetricotlp.ExportRequest, error) {
+func (protoEncoder) decodeMetricsRequest(r io.Reader) (pmetricotlp.ExportRequest, error) {
 	req := pmetricotlp.NewExportRequest()
-	err := req.UnmarshalProto(buf)
+	err := decodeProtoResources(r, func(buf []byte) error {
+		part := pmetricotlp.NewExportRequest()
+		if err := part.UnmarshalProto(buf); err != nil {
+			return err
+		}
+		part.Metrics().ResourceMetrics().MoveAndAppendTo(req.Metrics().ResourceMetrics())
+		return nil
+	})
 	return req, err
 }
 
-func (protoEncoder) unmarshalLogsRequest(buf []byte) (plogotlp.ExportRequest, error) {
+func (protoEncoder) decodeLogsRequest(r io.Reader) (plogotlp.ExportRequest, error) {
 	req := plogotlp.NewExportRequest()
-	err := req.UnmarshalProto(buf)
+	err := decodeProtoResources(r, func(buf []byte) error {
+		part := plogotlp.NewExportRequest()
+		if err := part.UnmarshalProto(buf); err != nil {
+			return err
+		}
+		part.Logs().ResourceLogs().MoveAndAppendTo(req.Logs().ResourceLogs())
+		return nil
+	})
 	return req, err
 }
 
@@ -82,22 +111,28 @@ func (protoEncoder) contentType() string {
 
 type jsonEncoder struct{}
 
-func (jsonEncoder) unmarshalTracesRequest(buf []byte) (ptraceotlp.ExportRequest, error) {
-	req := ptraceotlp.NewExportRequest()
-	err := req.UnmarshalJSON(buf)
-	return req, err
+func (jsonEncoder) decodeTracesRequest(r io.Reader) (ptraceotlp.ExportRequest, error) {
+	td, err := jsonTracesUnmarshaler.UnmarshalTracesFrom(r)
+	if err != nil {
+		return ptraceotlp.NewExportRequest(), err
+	}
+	return ptraceotlp.NewExportRequestFromTraces(td), nil
 }
 
-func (jsonEncoder) unmarshalMetricsRequest(buf []byte) (pmetricotlp.ExportRequest, error) {
-	req := pmetricotlp.NewExportRequest()
-	err := req.UnmarshalJSON(buf)
-	return req, err
+func (jsonEncoder) decodeMetricsRequest(r io.Reader) (pmetricotlp.ExportRequest, error) {
+	md, err := jsonMetricsUnmarshaler.UnmarshalMetricsFrom(r)
+	if err != nil {
+		return pmetricotlp.NewExportRequest(), err
+	}
+	return pmetricotlp.NewExportRequestFromMetrics(md), nil
 }
 
-func (jsonEncoder) unmarshalLogsRequest(buf []byte) (plogotlp.ExportRequest, error) {
-	req := plogotlp.NewExportRequest()
-	err := req.UnmarshalJSON(buf)
-	return req, err
+func (jsonEncoder) decodeLogsRequest(r io.Reader) (plogotlp.ExportRequest, error) {
+	ld, err := jsonLogsUnmarshaler.UnmarshalLogsFrom(r)
+	if err != nil {
+		return plogotlp.NewExportRequest(), err
+	}
+	return plogotlp.NewExportRequestFromLogs(ld), nil
 }
 
 func (jsonEncoder) marshalTracesResponse(resp ptraceotlp.ExportResponse) ([]byte, error) {
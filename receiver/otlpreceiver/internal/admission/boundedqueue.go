@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package admission limits the total size of the requests processed concurrently by a receiver.
+package admission // import "go.opentelemetry.io/collector/receiver/otlpreceiver/internal/admission"
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrTooManyWaiters is returned when the request can't be admitted immediately and
+	// the maximum number of waiting requests is reached.
+	ErrTooManyWaiters = errors.New("rejecting request, too many waiters")
+	// ErrRequestTooLarge is returned when the request is larger than the limit itself,
+	// so it can never be admitted.
+	ErrRequestTooLarge = errors.New("rejecting request, request is larger than the admission limit")
+	// ErrLimitReached is returned by TryAcquire when the request can't be admitted without waiting.
+	ErrLimitReached = errors.New("rejecting request, admission limit reached")
+)
+
+type waiter struct {
+	size  int64
+	ready chan struct{}
+}
+
+// BoundedQueue admits the requests while the total size of the admitted requests is
+// under a limit, and makes a limited number of requests wait, in order, for the
+// admitted ones to be released.
+type BoundedQueue struct {
+	limit       int64
+	waiterLimit int
+
+	mu       sync.Mutex
+	inFlight int64
+	waiters  *list.List
+}
+
+// NewBoundedQueue returns a BoundedQueue admitting up to limit bytes at a time and
+// making up to waiterLimit requests wait when the limit is reached.
+func NewBoundedQueue(limit int64, waiterLimit int) *BoundedQueue {
+	return &BoundedQueue{
+		limit:       limit,
+		waiterLimit: waiterLimit,
+		waiters:     list.New(),
+	}
+}
+
+// Acquire admits a request of the given size, waiting until enough of the limit is
+// released or ctx is done. It returns an error if the request is rejected, in which
+// case Release must not be called.
+func (bq *BoundedQueue) Acquire(ctx context.Context, size int64) error {
+	if size > bq.limit {
+		return ErrRequestTooLarge
+	}
+
+	bq.mu.Lock()
+	if bq.waiters.Len() == 0 && bq.inFlight+size <= bq.limit {
+		bq.inFlight += size
+		bq.mu.Unlock()
+		return nil
+	}
+	if bq.waiters.Len() >= bq.waiterLimit {
+		bq.mu.Unlock()
+		return ErrTooManyWaiters
+	}
+	w := &waiter{size: size, ready: make(chan struct{})}
+	elem := bq.waiters.PushBack(w)
+	bq.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		bq.mu.Lock()
+		defer bq.mu.Unlock()
+		select {
+		case <-w.ready:
+			// Admitted concurrently with the cancellation, give the size back.
+			bq.inFlight -= size
+		default:
+			bq.waiters.Remove(elem)
+		}
+		// The request may have been blocking the smaller ones behind it.
+		bq.admitWaitersLocked()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire admits a request of the given size only if it fits under the limit right away,
+// ahead of the waiting requests, e.g. to admit more of a request being read, which must not
+// wait for the other requests while holding a part of the limit. It returns an error if the
+// request is rejected, in which case Release must not be called.
+func (bq *BoundedQueue) TryAcquire(size int64) error {
+	if size > bq.limit {
+		return ErrRequestTooLarge
+	}
+
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+	if bq.inFlight+size > bq.limit {
+		return ErrLimitReached
+	}
+	bq.inFlight += size
+	return nil
+}
+
+// Limit returns the maximum total size of the admitted requests.
+func (bq *BoundedQueue) Limit() int64 {
+	return bq.limit
+}
+
+// Release gives back the size of a request admitted by Acquire once it's processed.
+func (bq *BoundedQueue) Release(size int64) {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+	bq.inFlight -= size
+	bq.admitWaitersLocked()
+}
+
+// InFlight returns the total size of the admitted requests.
+func (bq *BoundedQueue) InFlight() int64 {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+	return bq.inFlight
+}
+
+// admitWaitersLocked admits the waiting requests, in order, while they fit under the limit.
+func (bq *BoundedQueue) admitWaitersLocked() {
+	for elem := bq.waiters.Front(); elem != nil; elem = bq.waiters.Front() {
+		w := elem.Value.(*waiter)
+		if bq.inFlight+w.size > bq.limit {
+			return
+		}
+		bq.inFlight += w.size
+		bq.waiters.Remove(elem)
+		close(w.ready)
+	}
+}
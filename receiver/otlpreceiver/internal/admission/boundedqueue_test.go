@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundedQueueAcquireRelease(t *testing.T) {
+	bq := NewBoundedQueue(10, 0)
+
+	require.NoError(t, bq.Acquire(context.Background(), 6))
+	require.NoError(t, bq.Acquire(context.Background(), 4))
+	assert.EqualValues(t, 10, bq.InFlight())
+
+	assert.ErrorIs(t, bq.Acquire(context.Background(), 1), ErrTooManyWaiters)
+	assert.ErrorIs(t, bq.Acquire(context.Background(), 11), ErrRequestTooLarge)
+
+	bq.Release(6)
+	assert.EqualValues(t, 4, bq.InFlight())
+	require.NoError(t, bq.Acquire(context.Background(), 6))
+}
+
+func TestBoundedQueueTryAcquire(t *testing.T) {
+	bq := NewBoundedQueue(10, 1)
+	require.NoError(t, bq.Acquire(context.Background(), 6))
+
+	waiting := make(chan error)
+	go func() {
+		waiting <- bq.Acquire(context.Background(), 6)
+	}()
+	assert.Eventually(t, func() bool {
+		bq.mu.Lock()
+		defer bq.mu.Unlock()
+		return bq.waiters.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	// Admitted ahead of the waiter, without waiting.
+	require.NoError(t, bq.TryAcquire(3))
+	assert.ErrorIs(t, bq.TryAcquire(2), ErrLimitReached)
+	assert.ErrorIs(t, bq.TryAcquire(11), ErrRequestTooLarge)
+	assert.EqualValues(t, 9, bq.InFlight())
+
+	bq.Release(9)
+	require.NoError(t, <-waiting)
+	assert.EqualValues(t, 6, bq.InFlight())
+}
+
+func TestBoundedQueueWaiters(t *testing.T) {
+	bq := NewBoundedQueue(10, 2)
+	require.NoError(t, bq.Acquire(context.Background(), 10))
+
+	admitted := make(chan int64, 2)
+	for _, size := range []int64{8, 2} {
+		size := size
+		go func() {
+			assert.NoError(t, bq.Acquire(context.Background(), size))
+			admitted <- size
+		}()
+		// Wait for the request to be queued to keep the order.
+		assert.Eventually(t, func() bool {
+			bq.mu.Lock()
+			defer bq.mu.Unlock()
+			return bq.waiters.Back() != nil && bq.waiters.Back().Value.(*waiter).size == size
+		}, time.Second, time.Millisecond)
+	}
+	assert.ErrorIs(t, bq.Acquire(context.Background(), 1), ErrTooManyWaiters)
+
+	// The waiters are admitted in order, the small one doesn't get ahead of the large one.
+	bq.Release(5)
+	select {
+	case <-admitted:
+		t.Fatal("waiter admitted over the limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+	bq.Release(5)
+	assert.ElementsMatch(t, []int64{8, 2}, []int64{<-admitted, <-admitted})
+	assert.EqualValues(t, 10, bq.InFlight())
+}
+
+func TestBoundedQueueWaiterCanceled(t *testing.T) {
+	bq := NewBoundedQueue(10, 2)
+	require.NoError(t, bq.Acquire(context.Background(), 5))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bq.Acquire(ctx, 8), context.DeadlineExceeded)
+	assert.EqualValues(t, 5, bq.InFlight())
+	assert.Equal(t, 0, bq.waiters.Len())
+
+	// The canceled waiter doesn't block the following requests.
+	require.NoError(t, bq.Acquire(context.Background(), 5))
+	assert.EqualValues(t, 10, bq.InFlight())
+}
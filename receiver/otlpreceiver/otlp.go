@@ -11,7 +11,11 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -40,6 +44,14 @@ type otlpReceiver struct {
 	obsrepGRPC *receiverhelper.ObsReport
 	obsrepHTTP *receiverhelper.ObsReport
 
+	// memoryLimiter refuses the requests before they're processed, it's nil
+	// if no memory limiter extension is configured.
+	memoryLimiter receiverhelper.MemoryLimiter
+
+	// admitter limits the total size of the requests processed concurrently,
+	// it's nil if no admission limit is configured.
+	admitter *admitter
+
 	settings *receiver.CreateSettings
 }
 
@@ -72,6 +84,9 @@ func newOtlpReceiver(cfg *Config, set *receiver.CreateSettings) (*otlpReceiver,
 	if err != nil {
 		return nil, err
 	}
+	if r.admitter, err = newAdmitter(cfg.Admission, set); err != nil {
+		return nil, err
+	}
 
 	return r, nil
 }
@@ -82,8 +97,23 @@ func (r *otlpReceiver) startGRPCServer(host component.Host) error {
 		return nil
 	}
 
+	var interceptors []grpc.UnaryServerInterceptor
+	if r.memoryLimiter != nil {
+		interceptors = append(interceptors, memoryLimiterUnaryInterceptor(r.memoryLimiter))
+	}
+	if r.admitter != nil {
+		interceptors = append(interceptors, r.admitter.unaryInterceptor())
+	}
+	var opts []grpc.ServerOption
+	if len(interceptors) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(interceptors...))
+	}
+	if r.admitter != nil {
+		opts = append(opts, grpc.StatsHandler(statsHandler{}))
+	}
+
 	var err error
-	if r.serverGRPC, err = r.cfg.GRPC.ToServer(host, r.settings.TelemetrySettings); err != nil {
+	if r.serverGRPC, err = r.cfg.GRPC.ToServer(host, r.settings.TelemetrySettings, opts...); err != nil {
 		return err
 	}
 
@@ -144,8 +174,19 @@ func (r *otlpReceiver) startHTTPServer(host component.Host) error {
 		})
 	}
 
+	var handler http.Handler = httpMux
+	if r.admitter != nil {
+		handler = r.admitter.httpHandler(handler)
+	}
+	if r.cfg.HTTP.MaxRequestBodySize > 0 {
+		handler = maxDecompressedBodySizeHandler(handler, r.cfg.HTTP.MaxRequestBodySize)
+	}
+	if r.memoryLimiter != nil {
+		handler = receiverhelper.NewMemoryLimiterHTTPHandler(r.memoryLimiter, handler, errorHandler)
+	}
+
 	var err error
-	if r.serverHTTP, err = r.cfg.HTTP.ToServer(host, r.settings.TelemetrySettings, httpMux, confighttp.WithErrorHandler(errorHandler)); err != nil {
+	if r.serverHTTP, err = r.cfg.HTTP.ToServer(host, r.settings.TelemetrySettings, handler, confighttp.WithErrorHandler(errorHandler)); err != nil {
 		return err
 	}
 
@@ -169,6 +210,13 @@ func (r *otlpReceiver) startHTTPServer(host component.Host) error {
 // Start runs the trace receiver on the gRPC server. Currently
 // it also enables the metrics receiver too.
 func (r *otlpReceiver) Start(ctx context.Context, host component.Host) error {
+	if r.cfg.MemoryLimiter != nil {
+		ml, err := receiverhelper.GetMemoryLimiter(host.GetExtensions(), *r.cfg.MemoryLimiter)
+		if err != nil {
+			return err
+		}
+		r.memoryLimiter = ml
+	}
 	if err := r.startGRPCServer(host); err != nil {
 		return err
 	}
@@ -198,6 +246,23 @@ func (r *otlpReceiver) Shutdown(ctx context.Context) error {
 	return err
 }
 
+// memoryLimiterUnaryInterceptor returns a gRPC interceptor refusing the requests with RESOURCE_EXHAUSTED,
+// and the RetryInfo details required by the OTLP clients to retry them, when the memory limiter must refuse
+// data. The refused requests are not processed, but unlike the HTTP requests they're already read and decoded,
+// since the streams aborted before that can't carry the RetryInfo details.
+func memoryLimiterUnaryInterceptor(ml receiverhelper.MemoryLimiter) grpc.UnaryServerInterceptor {
+	refused := status.New(codes.ResourceExhausted, receiverhelper.ErrMemoryLimited.Error())
+	if st, err := refused.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(receiverhelper.MemoryLimiterRetryDelay)}); err == nil {
+		refused = st
+	}
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if ml.MustRefuse() {
+			return nil, refused.Err()
+		}
+		return handler(ctx, req)
+	}
+}
+
 func (r *otlpReceiver) registerTraceConsumer(tc consumer.Traces) error {
 	if tc == nil {
 		return component.ErrNilNextConsumer
@@ -13,6 +13,9 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -21,6 +24,7 @@ import (
 	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -726,7 +730,7 @@ func TestHTTPInvalidTLSCredentials(t *testing.T) {
 		`failed to load TLS config: failed to load TLS cert and key: for auth via TLS, provide both certificate and key, or neither`)
 }
 
-func testHTTPMaxRequestBodySize(t *testing.T, path string, contentType string, payload []byte, size int, expectedStatusCode int) {
+func testHTTPMaxRequestBodySize(t *testing.T, path string, contentType string, encoding string, payload []byte, size int, expectedStatusCode int) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	url := "http://" + addr + path
 	cfg := &Config{
@@ -746,7 +750,7 @@ func testHTTPMaxRequestBodySize(t *testing.T, path string, contentType string, p
 	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, consumertest.NewNop())
 	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
 
-	req := createHTTPRequest(t, url, "", contentType, payload)
+	req := createHTTPRequest(t, url, encoding, contentType, payload)
 	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	_, err = io.ReadAll(resp.Body)
@@ -760,14 +764,165 @@ func TestHTTPMaxRequestBodySize(t *testing.T) {
 	dataReqs := generateDataRequests(t)
 
 	for _, dr := range dataReqs {
-		testHTTPMaxRequestBodySize(t, dr.path, "application/json", dr.jsonBytes, len(dr.jsonBytes), 200)
-		testHTTPMaxRequestBodySize(t, dr.path, "application/json", dr.jsonBytes, len(dr.jsonBytes)-1, 400)
+		testHTTPMaxRequestBodySize(t, dr.path, "application/json", "", dr.jsonBytes, len(dr.jsonBytes), 200)
+		testHTTPMaxRequestBodySize(t, dr.path, "application/json", "", dr.jsonBytes, len(dr.jsonBytes)-1, 413)
 
-		testHTTPMaxRequestBodySize(t, dr.path, "application/x-protobuf", dr.protoBytes, len(dr.protoBytes), 200)
-		testHTTPMaxRequestBodySize(t, dr.path, "application/x-protobuf", dr.protoBytes, len(dr.protoBytes)-1, 400)
+		testHTTPMaxRequestBodySize(t, dr.path, "application/x-protobuf", "", dr.protoBytes, len(dr.protoBytes), 200)
+		testHTTPMaxRequestBodySize(t, dr.path, "application/x-protobuf", "", dr.protoBytes, len(dr.protoBytes)-1, 413)
 	}
 }
 
+func TestHTTPMaxRequestBodySizeDecompressed(t *testing.T) {
+	// The highly compressible request is smaller than the limit until it's decompressed.
+	td := generateLargeTraces(64 << 10)
+	payload, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	require.Less(t, compressGzip(t, payload).Len(), 8<<10)
+
+	testHTTPMaxRequestBodySize(t, defaultTracesURLPath, "application/x-protobuf", "gzip", payload, len(payload), 200)
+	testHTTPMaxRequestBodySize(t, defaultTracesURLPath, "application/x-protobuf", "gzip", payload, 8<<10, 413)
+}
+
+type memoryLimiterExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	mustRefuse bool
+}
+
+func (ml *memoryLimiterExtension) MustRefuse() bool {
+	return ml.mustRefuse
+}
+
+type memoryLimiterHost struct {
+	component.Host
+	ml *memoryLimiterExtension
+}
+
+func (h *memoryLimiterHost) GetExtensions() map[component.ID]component.Component {
+	return map[component.ID]component.Component{
+		component.MustNewID("memory_limiter"): h.ml,
+	}
+}
+
+func TestOTLPReceiverMemoryLimiter(t *testing.T) {
+	grpcAddr := testutil.GetAvailableLocalAddress(t)
+	httpAddr := testutil.GetAvailableLocalAddress(t)
+	sink := newErrOrSinkConsumer()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = grpcAddr
+	cfg.HTTP.Endpoint = httpAddr
+	mlID := component.MustNewID("memory_limiter")
+	cfg.MemoryLimiter = &mlID
+	ml := &memoryLimiterExtension{mustRefuse: true}
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, sink)
+	require.NoError(t, recv.Start(context.Background(), &memoryLimiterHost{Host: componenttest.NewNopHost(), ml: ml}))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	// gRPC requests are refused with RESOURCE_EXHAUSTED and the RetryInfo making them retryable.
+	err = exportTraces(cc, testdata.GenerateTraces(1))
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Contains(t, st.Message(), "high memory usage")
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Equal(t, time.Second, retryInfo.RetryDelay.AsDuration())
+
+	// HTTP requests are refused with 429 and a Retry-After header.
+	dr := generateTracesRequest(t)
+	req := createHTTPRequest(t, "http://"+httpAddr+dr.path, "", "application/x-protobuf", dr.protoBytes)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	respBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	errStatus := &spb.Status{}
+	require.NoError(t, proto.Unmarshal(respBytes, errStatus))
+	assert.Equal(t, int32(codes.ResourceExhausted), errStatus.Code)
+	assert.Empty(t, sink.AllTraces())
+
+	// The requests are accepted once the memory usage is back within limits.
+	ml.mustRefuse = false
+	require.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+	req = createHTTPRequest(t, "http://"+httpAddr+dr.path, "", "application/x-protobuf", dr.protoBytes)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, sink.AllTraces(), 2)
+}
+
+func TestOTLPReceiverMemoryLimiterNotFound(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.HTTP = nil
+	mlID := component.MustNewID("memory_limiter")
+	cfg.MemoryLimiter = &mlID
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, consumertest.NewNop())
+	assert.ErrorContains(t, recv.Start(context.Background(), componenttest.NewNopHost()), "memory limiter not found")
+}
+
+func TestOTLPReceiverUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	dir := t.TempDir()
+	grpcSocket := filepath.Join(dir, "grpc.sock")
+	httpSocket := filepath.Join(dir, "http.sock")
+	sink := newErrOrSinkConsumer()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = "unix://" + grpcSocket
+	cfg.GRPC.NetAddr.UnixSocket.Permissions = "0600"
+	cfg.HTTP.Endpoint = "unix://" + httpSocket
+	cfg.HTTP.UnixSocket.Permissions = "0660"
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, sink)
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	info, err := os.Stat(grpcSocket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	info, err = os.Stat(httpSocket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0660), info.Mode().Perm())
+
+	cc, err := grpc.Dial("unix://"+grpcSocket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+	require.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", httpSocket)
+		},
+	}}
+	dr := generateTracesRequest(t)
+	req := createHTTPRequest(t, "http://localhost"+dr.path, "", "application/x-protobuf", dr.protoBytes)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, sink.AllTraces(), 2)
+}
+
 func newGRPCReceiver(t *testing.T, settings component.TelemetrySettings, endpoint string, c consumertest.Consumer) component.Component {
 	cfg := createDefaultConfig().(*Config)
 	cfg.GRPC.NetAddr.Endpoint = endpoint
@@ -4,6 +4,7 @@
 package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -13,6 +14,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/admission"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/trace"
@@ -29,17 +31,11 @@ func handleTraces(resp http.ResponseWriter, req *http.Request, tracesReceiver *t
 		return
 	}
 
-	body, ok := readAndCloseBody(resp, req, enc)
+	otlpReq, ok := decodeAndCloseBody(resp, req, enc, enc.decodeTracesRequest)
 	if !ok {
 		return
 	}
 
-	otlpReq, err := enc.unmarshalTracesRequest(body)
-	if err != nil {
-		writeError(resp, enc, err, http.StatusBadRequest)
-		return
-	}
-
 	otlpResp, err := tracesReceiver.Export(req.Context(), otlpReq)
 	if err != nil {
 		writeError(resp, enc, err, http.StatusInternalServerError)
@@ -60,17 +56,11 @@ func handleMetrics(resp http.ResponseWriter, req *http.Request, metricsReceiver
 		return
 	}
 
-	body, ok := readAndCloseBody(resp, req, enc)
+	otlpReq, ok := decodeAndCloseBody(resp, req, enc, enc.decodeMetricsRequest)
 	if !ok {
 		return
 	}
 
-	otlpReq, err := enc.unmarshalMetricsRequest(body)
-	if err != nil {
-		writeError(resp, enc, err, http.StatusBadRequest)
-		return
-	}
-
 	otlpResp, err := metricsReceiver.Export(req.Context(), otlpReq)
 	if err != nil {
 		writeError(resp, enc, err, http.StatusInternalServerError)
@@ -91,17 +81,11 @@ func handleLogs(resp http.ResponseWriter, req *http.Request, logsReceiver *logs.
 		return
 	}
 
-	body, ok := readAndCloseBody(resp, req, enc)
+	otlpReq, ok := decodeAndCloseBody(resp, req, enc, enc.decodeLogsRequest)
 	if !ok {
 		return
 	}
 
-	otlpReq, err := enc.unmarshalLogsRequest(body)
-	if err != nil {
-		writeError(resp, enc, err, http.StatusBadRequest)
-		return
-	}
-
 	otlpResp, err := logsReceiver.Export(req.Context(), otlpReq)
 	if err != nil {
 		writeError(resp, enc, err, http.StatusInternalServerError)
@@ -133,17 +117,44 @@ func readContentType(resp http.ResponseWriter, req *http.Request) (encoder, bool
 	}
 }
 
-func readAndCloseBody(resp http.ResponseWriter, req *http.Request, enc encoder) ([]byte, bool) {
-	body, err := io.ReadAll(req.Body)
+// maxDecompressedBodySizeHandler limits the size of the body while it's decoded, the larger
+// requests are refused with 413 Request Entity Too Large. The body size is limited as received
+// by confighttp, before it's decompressed, so the compressed requests would otherwise be
+// decoded without limit.
+func maxDecompressedBodySizeHandler(next http.Handler, maxSize int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeAndCloseBody decodes the request with decode as its body is read, without reading
+// the whole body in memory first.
+func decodeAndCloseBody[T any](resp http.ResponseWriter, req *http.Request, enc encoder, decode func(io.Reader) (T, error)) (T, bool) {
+	otlpReq, err := decode(req.Body)
 	if err != nil {
-		writeError(resp, enc, err, http.StatusBadRequest)
-		return nil, false
+		writeError(resp, enc, err, bodyErrorStatus(err))
+		return otlpReq, false
 	}
 	if err = req.Body.Close(); err != nil {
 		writeError(resp, enc, err, http.StatusBadRequest)
-		return nil, false
+		return otlpReq, false
 	}
-	return body, true
+	return otlpReq, true
+}
+
+// bodyErrorStatus returns the HTTP status of the errors reading and decoding the body. The
+// requests whose body, once decompressed, is larger than max_request_body_size are too large,
+// and the requests whose body isn't admitted while it's read are rejected like the other requests.
+func bodyErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	if errors.Is(err, admission.ErrRequestTooLarge) || errors.Is(err, admission.ErrLimitReached) {
+		return admissionHTTPStatus(err)
+	}
+	return http.StatusBadRequest
 }
 
 // writeError encodes the HTTP error inside a rpc.Status message as required by the OTLP protocol.
@@ -191,6 +202,12 @@ func errorMsgToStatus(errMsg string, statusCode int) *status.Status {
 	if statusCode == http.StatusBadRequest {
 		return status.New(codes.InvalidArgument, errMsg)
 	}
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestEntityTooLarge {
+		return status.New(codes.ResourceExhausted, errMsg)
+	}
+	if statusCode == http.StatusServiceUnavailable {
+		return status.New(codes.Unavailable, errMsg)
+	}
 	return status.New(codes.Unknown, errMsg)
 }
 
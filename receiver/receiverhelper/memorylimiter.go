@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper // import "go.opentelemetry.io/collector/receiver/receiverhelper"
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// MemoryLimiterRetryDelay is the delay suggested to the clients whose requests are refused
+// by a memory limiter before they retry them.
+const MemoryLimiterRetryDelay = time.Second
+
+var (
+	// ErrMemoryLimited is returned to the clients whose requests are refused by a memory limiter.
+	ErrMemoryLimited = errors.New("request refused due to high memory usage")
+
+	errMemoryLimiterNotFound = errors.New("memory limiter not found")
+	errNotMemoryLimiter      = errors.New("requested extension is not a memory limiter")
+)
+
+// MemoryLimiter is implemented by the extensions refusing data when the memory usage is
+// too high, e.g. the memory limiter extension. Receivers use it to refuse the requests
+// before they're decoded.
+type MemoryLimiter interface {
+	// MustRefuse returns if the requests must be refused because the memory usage
+	// reached the configured limits.
+	MustRefuse() bool
+}
+
+// GetMemoryLimiter attempts to select the memory limiter with the given id from the list of extensions.
+// If the memory limiter is not found, an error is returned.
+func GetMemoryLimiter(extensions map[component.ID]component.Component, id component.ID) (MemoryLimiter, error) {
+	if ext, found := extensions[id]; found {
+		if ml, ok := ext.(MemoryLimiter); ok {
+			return ml, nil
+		}
+		return nil, errNotMemoryLimiter
+	}
+	return nil, fmt.Errorf("failed to resolve memory limiter %q: %w", id, errMemoryLimiterNotFound)
+}
+
+// NewMemoryLimiterHTTPHandler returns a handler refusing the requests with 429 Too Many Requests
+// and a Retry-After header when the memory limiter must refuse data, before the next handler
+// reads them. The error is written with errorHandler, or as plain text if it's nil.
+func NewMemoryLimiterHTTPHandler(ml MemoryLimiter, next http.Handler, errorHandler func(w http.ResponseWriter, r *http.Request, errMsg string, statusCode int)) http.Handler {
+	if errorHandler == nil {
+		errorHandler = func(w http.ResponseWriter, _ *http.Request, errMsg string, statusCode int) {
+			http.Error(w, errMsg, statusCode)
+		}
+	}
+	retryAfter := strconv.Itoa(int(MemoryLimiterRetryDelay / time.Second))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ml.MustRefuse() {
+			w.Header().Set("Retry-After", retryAfter)
+			errorHandler(w, r, ErrMemoryLimited.Error(), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+type mockMemoryLimiter struct {
+	component.StartFunc
+	component.ShutdownFunc
+	mustRefuse bool
+}
+
+func (ml *mockMemoryLimiter) MustRefuse() bool {
+	return ml.mustRefuse
+}
+
+type nopExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func TestGetMemoryLimiter(t *testing.T) {
+	mlID := component.MustNewID("memory_limiter")
+	otherID := component.MustNewID("other")
+	extensions := map[component.ID]component.Component{
+		mlID:    &mockMemoryLimiter{},
+		otherID: &nopExtension{},
+	}
+
+	ml, err := GetMemoryLimiter(extensions, mlID)
+	require.NoError(t, err)
+	assert.Equal(t, extensions[mlID], ml)
+
+	_, err = GetMemoryLimiter(extensions, otherID)
+	assert.ErrorIs(t, err, errNotMemoryLimiter)
+
+	_, err = GetMemoryLimiter(extensions, component.MustNewID("missing"))
+	assert.ErrorIs(t, err, errMemoryLimiterNotFound)
+}
+
+func TestMemoryLimiterHTTPHandler(t *testing.T) {
+	ml := &mockMemoryLimiter{}
+	called := false
+	handler := NewMemoryLimiterHTTPHandler(ml, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}), nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	called = false
+	ml.mustRefuse = true
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), ErrMemoryLimited.Error())
+}
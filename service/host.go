@@ -5,6 +5,7 @@ package service // import "go.opentelemetry.io/collector/service"
 
 import (
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/extension"
@@ -24,7 +25,8 @@ type serviceHost struct {
 	connectors        *connector.Builder
 	extensions        *extension.Builder
 
-	buildInfo component.BuildInfo
+	buildInfo     component.BuildInfo
+	effectiveConf func() (*confmap.Conf, error)
 
 	pipelines         *graph.Graph
 	serviceExtensions *extensions.Extensions
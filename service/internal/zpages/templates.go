@@ -44,6 +44,10 @@ var (
 	//go:embed templates/features_table.html
 	featuresTableBytes    []byte
 	featuresTableTemplate = parseTemplate("features_table", featuresTableBytes)
+
+	//go:embed templates/config.html
+	configBytes    []byte
+	configTemplate = parseTemplate("config", configBytes)
 )
 
 func parseTemplate(name string, bytes []byte) *template.Template {
@@ -172,3 +176,17 @@ func WriteHTMLFeaturesTable(w io.Writer, ftd FeatureGateTableData) {
 		log.Printf("zpages: executing template: %v", err)
 	}
 }
+
+// ConfigData contains data for the config template.
+type ConfigData struct {
+	// Config is the config in YAML, if there is no Error.
+	Config string
+	Error  string
+}
+
+// WriteHTMLConfig writes the config.
+func WriteHTMLConfig(w io.Writer, cd ConfigData) {
+	if err := configTemplate.Execute(w, cd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
@@ -78,6 +78,19 @@ func TestNoCrash(t *testing.T) {
 			},
 		}})
 	})
+	assert.NotPanics(t, func() { WriteHTMLConfig(buf, ConfigData{Config: "key: value\n"}) })
+	assert.NotPanics(t, func() { WriteHTMLConfig(buf, ConfigData{Error: "error"}) })
 	assert.NotPanics(t, func() { WriteHTMLPageFooter(buf) })
 	assert.NotPanics(t, func() { WriteHTMLPageFooter(buf) })
 }
+
+func TestWriteHTMLConfig(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteHTMLConfig(buf, ConfigData{Config: "key: <value>\n"})
+	assert.Contains(t, buf.String(), "<pre>key: &lt;value&gt;\n</pre>")
+
+	buf.Reset()
+	WriteHTMLConfig(buf, ConfigData{Error: "error"})
+	assert.Contains(t, buf.String(), "<p>error</p>")
+	assert.NotContains(t, buf.String(), "<pre>")
+}
@@ -41,6 +41,10 @@ type Settings struct {
 	// CollectorConf contains the Collector's current configuration
 	CollectorConf *confmap.Conf
 
+	// EffectiveConf returns the Collector's effective configuration, with the sensitive values redacted,
+	// rendered by the configz zPage when it is requested. If nil, the configuration is not available.
+	EffectiveConf func() (*confmap.Conf, error)
+
 	// Receivers builder for receivers.
 	Receivers *receiver.Builder
 
@@ -84,6 +88,7 @@ func New(ctx context.Context, set Settings, cfg Config) (*Service, error) {
 			extensions:        set.Extensions,
 			buildInfo:         set.BuildInfo,
 			asyncErrorChannel: set.AsyncErrorChannel,
+			effectiveConf:     set.EffectiveConf,
 		},
 		collectorConf: set.CollectorConf,
 	}
@@ -4,11 +4,14 @@
 package service // import "go.opentelemetry.io/collector/service"
 
 import (
+	"fmt"
 	"net/http"
 	"path"
 	"runtime"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/service/internal/zpages"
@@ -20,6 +23,7 @@ const (
 	zPipelinePath  = "pipelinez"
 	zExtensionPath = "extensionz"
 	zFeaturePath   = "featurez"
+	zConfigPath    = "configz"
 )
 
 var (
@@ -42,6 +46,7 @@ func (host *serviceHost) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
 	mux.HandleFunc(path.Join(pathPrefix, zPipelinePath), host.pipelines.HandleZPages)
 	mux.HandleFunc(path.Join(pathPrefix, zExtensionPath), host.serviceExtensions.HandleZPages)
 	mux.HandleFunc(path.Join(pathPrefix, zFeaturePath), handleFeaturezRequest)
+	mux.HandleFunc(path.Join(pathPrefix, zConfigPath), host.handleConfigzRequest)
 }
 
 func (host *serviceHost) zPagesRequest(w http.ResponseWriter, _ *http.Request) {
@@ -64,9 +69,36 @@ func (host *serviceHost) zPagesRequest(w http.ResponseWriter, _ *http.Request) {
 		ComponentEndpoint: zFeaturePath,
 		Link:              true,
 	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Configuration",
+		ComponentEndpoint: zConfigPath,
+		Link:              true,
+	})
 	zpages.WriteHTMLPageFooter(w)
 }
 
+func (host *serviceHost) handleConfigzRequest(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	zpages.WriteHTMLPageHeader(w, zpages.HeaderData{Title: "Configuration"})
+	zpages.WriteHTMLConfig(w, host.getConfigData())
+	zpages.WriteHTMLPageFooter(w)
+}
+
+func (host *serviceHost) getConfigData() zpages.ConfigData {
+	if host.effectiveConf == nil {
+		return zpages.ConfigData{Error: "The effective configuration is not available."}
+	}
+	conf, err := host.effectiveConf()
+	if err != nil {
+		return zpages.ConfigData{Error: fmt.Sprintf("Failed to marshal the effective configuration: %v", err)}
+	}
+	yamlData, err := yaml.Marshal(conf.ToStringMap())
+	if err != nil {
+		return zpages.ConfigData{Error: fmt.Sprintf("Failed to marshal the effective configuration: %v", err)}
+	}
+	return zpages.ConfigData{Config: string(yamlData)}
+}
+
 func handleFeaturezRequest(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	zpages.WriteHTMLPageHeader(w, zpages.HeaderData{Title: "Feature Gates"})
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/service/internal/zpages"
+)
+
+func TestGetConfigData(t *testing.T) {
+	host := &serviceHost{}
+	assert.Equal(t, zpages.ConfigData{Error: "The effective configuration is not available."}, host.getConfigData())
+
+	host.effectiveConf = func() (*confmap.Conf, error) {
+		return confmap.NewFromStringMap(map[string]any{
+			"receivers": map[string]any{"nop": nil},
+			"exporters": map[string]any{"nop": map[string]any{"token": "[REDACTED]"}},
+		}), nil
+	}
+	assert.Equal(t, zpages.ConfigData{Config: "exporters:\n    nop:\n        token: '[REDACTED]'\nreceivers:\n    nop: null\n"}, host.getConfigData())
+
+	host.effectiveConf = func() (*confmap.Conf, error) {
+		return nil, errors.New("invalid config")
+	}
+	assert.Equal(t, zpages.ConfigData{Error: "Failed to marshal the effective configuration: invalid config"}, host.getConfigData())
+}